@@ -23,11 +23,14 @@ const (
 	ControlPlane_GetEdge_FullMethodName           = "/controlplane.ControlPlane/GetEdge"
 	ControlPlane_KickEdge_FullMethodName          = "/controlplane.ControlPlane/KickEdge"
 	ControlPlane_ListEdgeRPCs_FullMethodName      = "/controlplane.ControlPlane/ListEdgeRPCs"
+	ControlPlane_GetEdgeRateLimit_FullMethodName  = "/controlplane.ControlPlane/GetEdgeRateLimit"
+	ControlPlane_SetEdgeRateLimit_FullMethodName  = "/controlplane.ControlPlane/SetEdgeRateLimit"
 	ControlPlane_ListServices_FullMethodName      = "/controlplane.ControlPlane/ListServices"
 	ControlPlane_GetService_FullMethodName        = "/controlplane.ControlPlane/GetService"
 	ControlPlane_KickService_FullMethodName       = "/controlplane.ControlPlane/KickService"
 	ControlPlane_ListServiceRPCs_FullMethodName   = "/controlplane.ControlPlane/ListServiceRPCs"
 	ControlPlane_ListServiceTopics_FullMethodName = "/controlplane.ControlPlane/ListServiceTopics"
+	ControlPlane_Drain_FullMethodName             = "/controlplane.ControlPlane/Drain"
 )
 
 // ControlPlaneClient is the client API for ControlPlane service.
@@ -39,12 +42,16 @@ type ControlPlaneClient interface {
 	GetEdge(ctx context.Context, in *GetEdgeRequest, opts ...grpc.CallOption) (*Edge, error)
 	KickEdge(ctx context.Context, in *KickEdgeRequest, opts ...grpc.CallOption) (*KickEdgeResponse, error)
 	ListEdgeRPCs(ctx context.Context, in *ListEdgeRPCsRequest, opts ...grpc.CallOption) (*ListEdgeRPCsResponse, error)
+	GetEdgeRateLimit(ctx context.Context, in *GetEdgeRateLimitRequest, opts ...grpc.CallOption) (*EdgeRateLimit, error)
+	SetEdgeRateLimit(ctx context.Context, in *SetEdgeRateLimitRequest, opts ...grpc.CallOption) (*EdgeRateLimit, error)
 	// service related
 	ListServices(ctx context.Context, in *ListServicesRequest, opts ...grpc.CallOption) (*ListServicesResponse, error)
 	GetService(ctx context.Context, in *GetServiceRequest, opts ...grpc.CallOption) (*Service, error)
 	KickService(ctx context.Context, in *KickServiceRequest, opts ...grpc.CallOption) (*KickServiceResponse, error)
 	ListServiceRPCs(ctx context.Context, in *ListServiceRPCsRequest, opts ...grpc.CallOption) (*ListServiceRPCsResponse, error)
 	ListServiceTopics(ctx context.Context, in *ListServiceTopicsRequest, opts ...grpc.CallOption) (*ListServiceTopicsResponse, error)
+	// frontier related
+	Drain(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainResponse, error)
 }
 
 type controlPlaneClient struct {
@@ -91,6 +98,24 @@ func (c *controlPlaneClient) ListEdgeRPCs(ctx context.Context, in *ListEdgeRPCsR
 	return out, nil
 }
 
+func (c *controlPlaneClient) GetEdgeRateLimit(ctx context.Context, in *GetEdgeRateLimitRequest, opts ...grpc.CallOption) (*EdgeRateLimit, error) {
+	out := new(EdgeRateLimit)
+	err := c.cc.Invoke(ctx, ControlPlane_GetEdgeRateLimit_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) SetEdgeRateLimit(ctx context.Context, in *SetEdgeRateLimitRequest, opts ...grpc.CallOption) (*EdgeRateLimit, error) {
+	out := new(EdgeRateLimit)
+	err := c.cc.Invoke(ctx, ControlPlane_SetEdgeRateLimit_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *controlPlaneClient) ListServices(ctx context.Context, in *ListServicesRequest, opts ...grpc.CallOption) (*ListServicesResponse, error) {
 	out := new(ListServicesResponse)
 	err := c.cc.Invoke(ctx, ControlPlane_ListServices_FullMethodName, in, out, opts...)
@@ -136,6 +161,15 @@ func (c *controlPlaneClient) ListServiceTopics(ctx context.Context, in *ListServ
 	return out, nil
 }
 
+func (c *controlPlaneClient) Drain(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainResponse, error) {
+	out := new(DrainResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_Drain_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ControlPlaneServer is the server API for ControlPlane service.
 // All implementations must embed UnimplementedControlPlaneServer
 // for forward compatibility
@@ -145,12 +179,16 @@ type ControlPlaneServer interface {
 	GetEdge(context.Context, *GetEdgeRequest) (*Edge, error)
 	KickEdge(context.Context, *KickEdgeRequest) (*KickEdgeResponse, error)
 	ListEdgeRPCs(context.Context, *ListEdgeRPCsRequest) (*ListEdgeRPCsResponse, error)
+	GetEdgeRateLimit(context.Context, *GetEdgeRateLimitRequest) (*EdgeRateLimit, error)
+	SetEdgeRateLimit(context.Context, *SetEdgeRateLimitRequest) (*EdgeRateLimit, error)
 	// service related
 	ListServices(context.Context, *ListServicesRequest) (*ListServicesResponse, error)
 	GetService(context.Context, *GetServiceRequest) (*Service, error)
 	KickService(context.Context, *KickServiceRequest) (*KickServiceResponse, error)
 	ListServiceRPCs(context.Context, *ListServiceRPCsRequest) (*ListServiceRPCsResponse, error)
 	ListServiceTopics(context.Context, *ListServiceTopicsRequest) (*ListServiceTopicsResponse, error)
+	// frontier related
+	Drain(context.Context, *DrainRequest) (*DrainResponse, error)
 	mustEmbedUnimplementedControlPlaneServer()
 }
 
@@ -170,6 +208,12 @@ func (UnimplementedControlPlaneServer) KickEdge(context.Context, *KickEdgeReques
 func (UnimplementedControlPlaneServer) ListEdgeRPCs(context.Context, *ListEdgeRPCsRequest) (*ListEdgeRPCsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListEdgeRPCs not implemented")
 }
+func (UnimplementedControlPlaneServer) GetEdgeRateLimit(context.Context, *GetEdgeRateLimitRequest) (*EdgeRateLimit, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEdgeRateLimit not implemented")
+}
+func (UnimplementedControlPlaneServer) SetEdgeRateLimit(context.Context, *SetEdgeRateLimitRequest) (*EdgeRateLimit, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetEdgeRateLimit not implemented")
+}
 func (UnimplementedControlPlaneServer) ListServices(context.Context, *ListServicesRequest) (*ListServicesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListServices not implemented")
 }
@@ -185,6 +229,9 @@ func (UnimplementedControlPlaneServer) ListServiceRPCs(context.Context, *ListSer
 func (UnimplementedControlPlaneServer) ListServiceTopics(context.Context, *ListServiceTopicsRequest) (*ListServiceTopicsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListServiceTopics not implemented")
 }
+func (UnimplementedControlPlaneServer) Drain(context.Context, *DrainRequest) (*DrainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Drain not implemented")
+}
 func (UnimplementedControlPlaneServer) mustEmbedUnimplementedControlPlaneServer() {}
 
 // UnsafeControlPlaneServer may be embedded to opt out of forward compatibility for this service.
@@ -270,6 +317,42 @@ func _ControlPlane_ListEdgeRPCs_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ControlPlane_GetEdgeRateLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEdgeRateLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).GetEdgeRateLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_GetEdgeRateLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).GetEdgeRateLimit(ctx, req.(*GetEdgeRateLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_SetEdgeRateLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEdgeRateLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).SetEdgeRateLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_SetEdgeRateLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).SetEdgeRateLimit(ctx, req.(*SetEdgeRateLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ControlPlane_ListServices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListServicesRequest)
 	if err := dec(in); err != nil {
@@ -360,6 +443,24 @@ func _ControlPlane_ListServiceTopics_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ControlPlane_Drain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).Drain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_Drain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).Drain(ctx, req.(*DrainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ControlPlane_ServiceDesc is the grpc.ServiceDesc for ControlPlane service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -383,6 +484,14 @@ var ControlPlane_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListEdgeRPCs",
 			Handler:    _ControlPlane_ListEdgeRPCs_Handler,
 		},
+		{
+			MethodName: "GetEdgeRateLimit",
+			Handler:    _ControlPlane_GetEdgeRateLimit_Handler,
+		},
+		{
+			MethodName: "SetEdgeRateLimit",
+			Handler:    _ControlPlane_SetEdgeRateLimit_Handler,
+		},
 		{
 			MethodName: "ListServices",
 			Handler:    _ControlPlane_ListServices_Handler,
@@ -403,6 +512,10 @@ var ControlPlane_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListServiceTopics",
 			Handler:    _ControlPlane_ListServiceTopics_Handler,
 		},
+		{
+			MethodName: "Drain",
+			Handler:    _ControlPlane_Drain_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "controlplane.proto",
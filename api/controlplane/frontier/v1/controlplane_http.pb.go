@@ -19,7 +19,9 @@ var _ = binding.EncodeURL
 
 const _ = http.SupportPackageIsVersion1
 
+const OperationControlPlaneDrain = "/controlplane.ControlPlane/Drain"
 const OperationControlPlaneGetEdge = "/controlplane.ControlPlane/GetEdge"
+const OperationControlPlaneGetEdgeRateLimit = "/controlplane.ControlPlane/GetEdgeRateLimit"
 const OperationControlPlaneGetService = "/controlplane.ControlPlane/GetService"
 const OperationControlPlaneKickEdge = "/controlplane.ControlPlane/KickEdge"
 const OperationControlPlaneKickService = "/controlplane.ControlPlane/KickService"
@@ -28,9 +30,13 @@ const OperationControlPlaneListEdges = "/controlplane.ControlPlane/ListEdges"
 const OperationControlPlaneListServiceRPCs = "/controlplane.ControlPlane/ListServiceRPCs"
 const OperationControlPlaneListServiceTopics = "/controlplane.ControlPlane/ListServiceTopics"
 const OperationControlPlaneListServices = "/controlplane.ControlPlane/ListServices"
+const OperationControlPlaneSetEdgeRateLimit = "/controlplane.ControlPlane/SetEdgeRateLimit"
 
 type ControlPlaneHTTPServer interface {
+	// Drain frontier related
+	Drain(context.Context, *DrainRequest) (*DrainResponse, error)
 	GetEdge(context.Context, *GetEdgeRequest) (*Edge, error)
+	GetEdgeRateLimit(context.Context, *GetEdgeRateLimitRequest) (*EdgeRateLimit, error)
 	GetService(context.Context, *GetServiceRequest) (*Service, error)
 	KickEdge(context.Context, *KickEdgeRequest) (*KickEdgeResponse, error)
 	KickService(context.Context, *KickServiceRequest) (*KickServiceResponse, error)
@@ -41,6 +47,7 @@ type ControlPlaneHTTPServer interface {
 	ListServiceTopics(context.Context, *ListServiceTopicsRequest) (*ListServiceTopicsResponse, error)
 	// ListServices service related
 	ListServices(context.Context, *ListServicesRequest) (*ListServicesResponse, error)
+	SetEdgeRateLimit(context.Context, *SetEdgeRateLimitRequest) (*EdgeRateLimit, error)
 }
 
 func RegisterControlPlaneHTTPServer(s *http.Server, srv ControlPlaneHTTPServer) {
@@ -49,11 +56,14 @@ func RegisterControlPlaneHTTPServer(s *http.Server, srv ControlPlaneHTTPServer)
 	r.GET("/v1/edges/{edge_id}", _ControlPlane_GetEdge0_HTTP_Handler(srv))
 	r.DELETE("/v1/edges/{edge_id}", _ControlPlane_KickEdge0_HTTP_Handler(srv))
 	r.GET("/v1/edges/rpcs", _ControlPlane_ListEdgeRPCs0_HTTP_Handler(srv))
+	r.GET("/v1/edges/{edge_id}/ratelimit", _ControlPlane_GetEdgeRateLimit0_HTTP_Handler(srv))
+	r.PUT("/v1/edges/{edge_id}/ratelimit", _ControlPlane_SetEdgeRateLimit0_HTTP_Handler(srv))
 	r.GET("/v1/services", _ControlPlane_ListServices0_HTTP_Handler(srv))
 	r.GET("/v1/services/{service_id}", _ControlPlane_GetService0_HTTP_Handler(srv))
 	r.DELETE("/v1/services/{service_id}", _ControlPlane_KickService0_HTTP_Handler(srv))
 	r.GET("/v1/services/rpcs", _ControlPlane_ListServiceRPCs0_HTTP_Handler(srv))
 	r.GET("/v1/services/topics", _ControlPlane_ListServiceTopics0_HTTP_Handler(srv))
+	r.POST("/v1/drain", _ControlPlane_Drain0_HTTP_Handler(srv))
 }
 
 func _ControlPlane_ListEdges0_HTTP_Handler(srv ControlPlaneHTTPServer) func(ctx http.Context) error {
@@ -138,6 +148,50 @@ func _ControlPlane_ListEdgeRPCs0_HTTP_Handler(srv ControlPlaneHTTPServer) func(c
 	}
 }
 
+func _ControlPlane_GetEdgeRateLimit0_HTTP_Handler(srv ControlPlaneHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in GetEdgeRateLimitRequest
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		if err := ctx.BindVars(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationControlPlaneGetEdgeRateLimit)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.GetEdgeRateLimit(ctx, req.(*GetEdgeRateLimitRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*EdgeRateLimit)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _ControlPlane_SetEdgeRateLimit0_HTTP_Handler(srv ControlPlaneHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in SetEdgeRateLimitRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		if err := ctx.BindVars(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationControlPlaneSetEdgeRateLimit)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.SetEdgeRateLimit(ctx, req.(*SetEdgeRateLimitRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*EdgeRateLimit)
+		return ctx.Result(200, reply)
+	}
+}
+
 func _ControlPlane_ListServices0_HTTP_Handler(srv ControlPlaneHTTPServer) func(ctx http.Context) error {
 	return func(ctx http.Context) error {
 		var in ListServicesRequest
@@ -239,8 +293,29 @@ func _ControlPlane_ListServiceTopics0_HTTP_Handler(srv ControlPlaneHTTPServer) f
 	}
 }
 
+func _ControlPlane_Drain0_HTTP_Handler(srv ControlPlaneHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in DrainRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationControlPlaneDrain)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.Drain(ctx, req.(*DrainRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*DrainResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
 type ControlPlaneHTTPClient interface {
+	Drain(ctx context.Context, req *DrainRequest, opts ...http.CallOption) (rsp *DrainResponse, err error)
 	GetEdge(ctx context.Context, req *GetEdgeRequest, opts ...http.CallOption) (rsp *Edge, err error)
+	GetEdgeRateLimit(ctx context.Context, req *GetEdgeRateLimitRequest, opts ...http.CallOption) (rsp *EdgeRateLimit, err error)
 	GetService(ctx context.Context, req *GetServiceRequest, opts ...http.CallOption) (rsp *Service, err error)
 	KickEdge(ctx context.Context, req *KickEdgeRequest, opts ...http.CallOption) (rsp *KickEdgeResponse, err error)
 	KickService(ctx context.Context, req *KickServiceRequest, opts ...http.CallOption) (rsp *KickServiceResponse, err error)
@@ -249,6 +324,7 @@ type ControlPlaneHTTPClient interface {
 	ListServiceRPCs(ctx context.Context, req *ListServiceRPCsRequest, opts ...http.CallOption) (rsp *ListServiceRPCsResponse, err error)
 	ListServiceTopics(ctx context.Context, req *ListServiceTopicsRequest, opts ...http.CallOption) (rsp *ListServiceTopicsResponse, err error)
 	ListServices(ctx context.Context, req *ListServicesRequest, opts ...http.CallOption) (rsp *ListServicesResponse, err error)
+	SetEdgeRateLimit(ctx context.Context, req *SetEdgeRateLimitRequest, opts ...http.CallOption) (rsp *EdgeRateLimit, err error)
 }
 
 type ControlPlaneHTTPClientImpl struct {
@@ -259,6 +335,19 @@ func NewControlPlaneHTTPClient(client *http.Client) ControlPlaneHTTPClient {
 	return &ControlPlaneHTTPClientImpl{client}
 }
 
+func (c *ControlPlaneHTTPClientImpl) Drain(ctx context.Context, in *DrainRequest, opts ...http.CallOption) (*DrainResponse, error) {
+	var out DrainResponse
+	pattern := "/v1/drain"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation(OperationControlPlaneDrain))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 func (c *ControlPlaneHTTPClientImpl) GetEdge(ctx context.Context, in *GetEdgeRequest, opts ...http.CallOption) (*Edge, error) {
 	var out Edge
 	pattern := "/v1/edges/{edge_id}"
@@ -272,6 +361,19 @@ func (c *ControlPlaneHTTPClientImpl) GetEdge(ctx context.Context, in *GetEdgeReq
 	return &out, nil
 }
 
+func (c *ControlPlaneHTTPClientImpl) GetEdgeRateLimit(ctx context.Context, in *GetEdgeRateLimitRequest, opts ...http.CallOption) (*EdgeRateLimit, error) {
+	var out EdgeRateLimit
+	pattern := "/v1/edges/{edge_id}/ratelimit"
+	path := binding.EncodeURL(pattern, in, true)
+	opts = append(opts, http.Operation(OperationControlPlaneGetEdgeRateLimit))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "GET", path, nil, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 func (c *ControlPlaneHTTPClientImpl) GetService(ctx context.Context, in *GetServiceRequest, opts ...http.CallOption) (*Service, error) {
 	var out Service
 	pattern := "/v1/services/{service_id}"
@@ -375,3 +477,16 @@ func (c *ControlPlaneHTTPClientImpl) ListServices(ctx context.Context, in *ListS
 	}
 	return &out, nil
 }
+
+func (c *ControlPlaneHTTPClientImpl) SetEdgeRateLimit(ctx context.Context, in *SetEdgeRateLimitRequest, opts ...http.CallOption) (*EdgeRateLimit, error) {
+	var out EdgeRateLimit
+	pattern := "/v1/edges/{edge_id}/ratelimit"
+	path := binding.EncodeURL(pattern, in, false)
+	opts = append(opts, http.Operation(OperationControlPlaneSetEdgeRateLimit))
+	opts = append(opts, http.PathTemplate(pattern))
+	err := c.cc.Invoke(ctx, "PUT", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
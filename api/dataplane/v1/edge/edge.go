@@ -15,6 +15,10 @@ type RPCer interface {
 	Call(ctx context.Context, method string, req geminio.Request) (geminio.Response, error)
 	CallAsync(ctx context.Context, method string, req geminio.Request, ch chan *geminio.Call) (*geminio.Call, error)
 	Register(ctx context.Context, method string, rpc geminio.RPC) error
+
+	// Call the method registered at another edge, routed by frontier,
+	// the request comes to the edge with ClientID of the caller edge
+	CallEdge(ctx context.Context, edgeID uint64, method string, req geminio.Request) (geminio.Response, error)
 }
 
 // Messager is topic oriented
@@ -26,6 +30,15 @@ type Messager interface {
 	// Publish async a message to specific topic
 	PublishAsync(ctx context.Context, topic string, msg geminio.Message, ch chan *geminio.Publish) (*geminio.Publish, error)
 	Receive(ctx context.Context) (geminio.Message, error)
+
+	// Publish a message with topic to another edge, routed by frontier,
+	// the message comes to the edge with ClientID of the publisher edge
+	PublishEdge(ctx context.Context, edgeID uint64, topic string, msg geminio.Message) error
+
+	// Subscribe topics that services publish to by PublishTopic, the messages come from Receive,
+	// the subscriptions are restored after the edge reconnected
+	Subscribe(ctx context.Context, topics ...string) error
+	Unsubscribe(ctx context.Context, topics ...string) error
 }
 
 type RPCMessager interface {
@@ -33,10 +46,24 @@ type RPCMessager interface {
 	Messager
 }
 
+// Rawer is raw io with services on the main connection, routed by frontier with flow control,
+// so lightweight devices can stream bytes without opening streams
+type Rawer interface {
+	// Write raw bytes to a specific service, blocks while the service is not reading,
+	// and fails if the bytes written before were dropped for the service is not online
+	WriteRaw(serviceName string, data []byte) (int, error)
+	// Read raw bytes from any service
+	ReadRaw() (serviceName string, data []byte, err error)
+}
+
 // Stream multiplexer
 type Multiplexer interface {
-	// Open a stream to a specific service
+	// Open a stream to a specific service, returns apis.ErrServiceNotOnline if no such service
 	OpenStream(serviceName string) (geminio.Stream, error)
+	// Open a stream to another edge, the peer of accepted stream at that edge is "$edge/{edgeID}",
+	// returns apis.ErrEdgeNotOnline or apis.ErrEdgeToEdgeDenied if the stream can't be forwarded
+	OpenEdgeStream(edgeID uint64) (geminio.Stream, error)
+	// rpcs and messages over streams come with ClientID and StreamID of the caller
 	AcceptStream() (geminio.Stream, error)
 	ListStreams() []geminio.Stream
 }
@@ -45,6 +72,9 @@ type Edge interface {
 	// Edge can directly Publish Message or Call RPC
 	RPCMessager
 
+	// Edge can directly Write or Read raw bytes
+	Rawer
+
 	// Edge can manage(create, list...) streams from or to a Service
 	Multiplexer
 
@@ -55,6 +85,9 @@ type Edge interface {
 
 	// Meta
 	EdgeID() uint64
+	// Update the meta on the live connection, services registered EdgeMetaUpdate are told,
+	// the edge created by NewEdge updates it again after reconnected
+	UpdateMeta(ctx context.Context, meta []byte) error
 
 	Close() error
 }
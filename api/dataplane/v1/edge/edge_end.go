@@ -2,14 +2,53 @@ package edge
 
 import (
 	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/frontier/pkg/frontier/raw"
 	"github.com/singchia/geminio"
 	"github.com/singchia/geminio/client"
+	"github.com/singchia/geminio/delegate"
 	"github.com/singchia/geminio/options"
 )
 
+// max time to wait for frontier to forward an opened stream
+const streamAckTimeout = 30 * time.Second
+
 type edgeEnd struct {
 	geminio.End
+	raw *raw.Conn
+
+	logger Logger
+	opt    *edgeOption
+	// subscribed topics, frontier drops them once the edge is offline
+	topicMtx sync.Mutex
+	topics   map[string]struct{}
+	// meta updated after connected, nil if never
+	metaMtx sync.Mutex
+	meta    []byte
+}
+
+// resubscriber restores the subscriptions after the retry end is online again
+type resubscriber struct {
+	*delegate.UnimplementedDelegate
+	// set after the retry end is built, nothing to restore before
+	end atomic.Pointer[edgeEnd]
+}
+
+func (rs *resubscriber) EndReOnline(_ delegate.ClientDescriber) {
+	end := rs.end.Load()
+	if end == nil {
+		return
+	}
+	// not to block the reinit
+	go func() {
+		end.remeta()
+		end.resubscribe()
+	}()
 }
 
 func newEdgeEnd(dialer client.Dialer, opts ...EdgeOption) (*edgeEnd, error) {
@@ -21,6 +60,9 @@ func newEdgeEnd(dialer client.Dialer, opts ...EdgeOption) (*edgeEnd, error) {
 	for _, opt := range opts {
 		opt(eopt)
 	}
+	if err := eopt.check(); err != nil {
+		return nil, err
+	}
 
 	// turn to end options
 	eopts := client.NewEndOptions()
@@ -33,8 +75,8 @@ func newEdgeEnd(dialer client.Dialer, opts ...EdgeOption) (*edgeEnd, error) {
 	if eopt.edgeID != nil {
 		eopts.SetClientID(*eopt.edgeID)
 	}
-	if eopt.meta != nil {
-		eopts.SetMeta(eopt.meta)
+	if meta := eopt.connMeta(); meta != nil {
+		eopts.SetMeta(meta)
 	}
 	if eopt.readBufferSize != -1 || eopt.writeBufferSize != -1 {
 		eopts.SetBufferSize(eopt.readBufferSize, eopt.writeBufferSize)
@@ -45,7 +87,7 @@ func newEdgeEnd(dialer client.Dialer, opts ...EdgeOption) (*edgeEnd, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &edgeEnd{end}, nil
+	return &edgeEnd{End: end, raw: raw.NewConn(end), logger: eopt.logger, opt: eopt, topics: map[string]struct{}{}}, nil
 }
 
 func newRetryEdgeEnd(dialer client.Dialer, opts ...EdgeOption) (*edgeEnd, error) {
@@ -57,6 +99,9 @@ func newRetryEdgeEnd(dialer client.Dialer, opts ...EdgeOption) (*edgeEnd, error)
 	for _, opt := range opts {
 		opt(eopt)
 	}
+	if err := eopt.check(); err != nil {
+		return nil, err
+	}
 
 	// turn to end options
 	eopts := client.NewEndOptions()
@@ -69,19 +114,35 @@ func newRetryEdgeEnd(dialer client.Dialer, opts ...EdgeOption) (*edgeEnd, error)
 	if eopt.edgeID != nil {
 		eopts.SetClientID(*eopt.edgeID)
 	}
-	if eopt.meta != nil {
-		eopts.SetMeta(eopt.meta)
+	meta := eopt.connMeta()
+	if meta != nil {
+		eopts.SetMeta(meta)
 	}
 	if eopt.readBufferSize != -1 || eopt.writeBufferSize != -1 {
 		eopts.SetBufferSize(eopt.readBufferSize, eopt.writeBufferSize)
 	}
 
+	rs := &resubscriber{UnimplementedDelegate: &delegate.UnimplementedDelegate{}}
+	eopts.SetDelegate(rs)
+	rd := &redirector{dialer: dialer, dial: eopt.redirect, logger: eopt.logger, meta: meta}
+	if eopt.hmacSecret != nil {
+		// the signature expires, renew it at every reconnect
+		rd.seal = eopt.connMeta
+	}
+
 	// new geminio end
-	end, err := client.NewRetryEndWithDialer(dialer, eopts)
+	end, err := client.NewRetryEndWithDialer(rd.Dial, eopts)
 	if err != nil {
 		return nil, err
 	}
-	return &edgeEnd{end}, nil
+	// the draining frontier tells the edge to reconnect, and where to if redirected
+	if err = end.Register(context.TODO(), apis.RPCReconnect, rd.reconnect); err != nil {
+		end.Close()
+		return nil, err
+	}
+	ee := &edgeEnd{End: end, raw: raw.NewConn(end), logger: eopt.logger, opt: eopt, topics: map[string]struct{}{}}
+	rs.end.Store(ee)
+	return ee, nil
 }
 
 // RPCer
@@ -98,7 +159,33 @@ func (end *edgeEnd) CallAsync(ctx context.Context, method string, req geminio.Re
 }
 
 func (end *edgeEnd) Register(ctx context.Context, method string, rpc geminio.RPC) error {
-	return end.End.Register(ctx, method, rpc)
+	wrap := func(ctx context.Context, req geminio.Request, rsp geminio.Response) {
+		// headers are carried only if the caller sets them or the caller is an edge
+		custom, headers, ok := apis.DecodeEnvelope(req.Custom())
+		if ok {
+			if edgeID, ok := headers.Uint64(apis.HeaderEdgeID); ok {
+				req.SetClientID(edgeID)
+			}
+			req = apis.WithRequestHeaders(req, custom, headers)
+		}
+		rpc(ctx, req, rsp)
+	}
+	return end.End.Register(ctx, method, wrap)
+}
+
+// the target edgeID is carried in method, and frontier will check the edge to edge policy
+func (end *edgeEnd) CallEdge(ctx context.Context, edgeID uint64, method string, req geminio.Request) (geminio.Response, error) {
+	return end.End.Call(ctx, apis.EdgeTarget(edgeID, method), req)
+}
+
+// Rawer
+func (end *edgeEnd) WriteRaw(serviceName string, data []byte) (int, error) {
+	return end.raw.Write(raw.Peer{Name: serviceName}, data)
+}
+
+func (end *edgeEnd) ReadRaw() (string, []byte, error) {
+	peer, data, err := end.raw.Read()
+	return peer.Name, data, err
 }
 
 // Messager
@@ -116,23 +203,155 @@ func (end *edgeEnd) PublishAsync(ctx context.Context, topic string, msg geminio.
 	return end.End.PublishAsync(ctx, msg, ch)
 }
 
+func (end *edgeEnd) PublishEdge(ctx context.Context, edgeID uint64, topic string, msg geminio.Message) error {
+	msg.SetTopic(apis.EdgeTarget(edgeID, topic))
+	return end.End.Publish(ctx, msg)
+}
+
+// the subscriptions are recorded by frontier
+func (end *edgeEnd) Subscribe(ctx context.Context, topics ...string) error {
+	if err := end.subscription(ctx, apis.RPCSubscribe, topics); err != nil {
+		return err
+	}
+	end.topicMtx.Lock()
+	for _, topic := range topics {
+		end.topics[topic] = struct{}{}
+	}
+	end.topicMtx.Unlock()
+	return nil
+}
+
+func (end *edgeEnd) Unsubscribe(ctx context.Context, topics ...string) error {
+	if err := end.subscription(ctx, apis.RPCUnsubscribe, topics); err != nil {
+		return err
+	}
+	end.topicMtx.Lock()
+	for _, topic := range topics {
+		delete(end.topics, topic)
+	}
+	end.topicMtx.Unlock()
+	return nil
+}
+
+func (end *edgeEnd) subscription(ctx context.Context, method string, topics []string) error {
+	data, err := json.Marshal(&apis.Subscription{Topics: topics})
+	if err != nil {
+		return err
+	}
+	_, err = end.End.Call(ctx, method, end.End.NewRequest(data))
+	return err
+}
+
+func (end *edgeEnd) resubscribe() {
+	end.topicMtx.Lock()
+	topics := make([]string, 0, len(end.topics))
+	for topic := range end.topics {
+		topics = append(topics, topic)
+	}
+	end.topicMtx.Unlock()
+	if len(topics) == 0 {
+		return
+	}
+	err := end.subscription(context.TODO(), apis.RPCSubscribe, topics)
+	if err != nil && end.logger != nil {
+		end.logger.Errorf("edge resubscribe err: %s, topics: %v", err, topics)
+	}
+}
+
+// the meta is recorded by frontier
+func (end *edgeEnd) UpdateMeta(ctx context.Context, meta []byte) error {
+	if err := end.updateMeta(ctx, meta); err != nil {
+		return err
+	}
+	end.metaMtx.Lock()
+	end.meta = meta
+	end.metaMtx.Unlock()
+	return nil
+}
+
+func (end *edgeEnd) updateMeta(ctx context.Context, meta []byte) error {
+	data, err := json.Marshal(&apis.MetaUpdate{Meta: end.opt.seal(meta)})
+	if err != nil {
+		return err
+	}
+	_, err = end.End.Call(ctx, apis.RPCUpdateMeta, end.End.NewRequest(data))
+	return err
+}
+
+// the reconnected edge carries the meta of options, update it again
+func (end *edgeEnd) remeta() {
+	end.metaMtx.Lock()
+	meta := end.meta
+	end.metaMtx.Unlock()
+	if meta == nil {
+		return
+	}
+	err := end.updateMeta(context.TODO(), meta)
+	if err != nil && end.logger != nil {
+		end.logger.Errorf("edge update meta again err: %s", err)
+	}
+}
+
 func (end *edgeEnd) Receive(ctx context.Context) (geminio.Message, error) {
-	return end.End.Receive(ctx)
+	msg, err := end.End.Receive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	custom, headers, ok := apis.DecodeEnvelope(msg.Custom())
+	if !ok {
+		return msg, nil
+	}
+	// the source edge of messages from other edges
+	if edgeID, ok := headers.Uint64(apis.HeaderEdgeID); ok {
+		msg.SetClientID(edgeID)
+	}
+	return apis.WithMessageHeaders(msg, custom, headers), nil
 }
 
 // Multiplexer
 func (end *edgeEnd) OpenStream(serviceName string) (geminio.Stream, error) {
+	return end.openStream(serviceName)
+}
+
+func (end *edgeEnd) OpenEdgeStream(edgeID uint64) (geminio.Stream, error) {
+	return end.openStream(apis.EdgeTarget(edgeID, ""))
+}
+
+// openStream waits for frontier to tell whether the stream is forwarded to the peer
+func (end *edgeEnd) openStream(peer string) (geminio.Stream, error) {
+	meta, _ := json.Marshal(&apis.StreamOpen{Ack: true, Caller: true})
 	opt := options.OpenStream()
-	opt.SetPeer(serviceName)
-	return end.End.OpenStream(opt)
+	opt.SetPeer(peer)
+	opt.SetMeta(meta)
+	stream, err := end.End.OpenStream(opt)
+	if err != nil {
+		return nil, err
+	}
+	stream = apis.WithCaller(stream)
+	stream.SetDeadline(time.Now().Add(streamAckTimeout))
+	err = apis.WaitStreamAck(stream)
+	stream.SetDeadline(time.Time{})
+	if err != nil {
+		stream.Close()
+		return nil, err
+	}
+	return stream, nil
 }
 
 func (end *edgeEnd) AcceptStream() (geminio.Stream, error) {
-	return end.End.AcceptStream()
+	stream, err := end.End.AcceptStream()
+	if err != nil {
+		return nil, err
+	}
+	return apis.WithCaller(stream), nil
 }
 
 func (end *edgeEnd) ListStreams() []geminio.Stream {
-	return end.End.ListStreams()
+	streams := end.End.ListStreams()
+	for i, stream := range streams {
+		streams[i] = apis.WithCaller(stream)
+	}
+	return streams
 }
 
 // Meta
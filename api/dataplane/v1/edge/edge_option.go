@@ -1,7 +1,12 @@
 package edge
 
 import (
+	"net"
+	"time"
+
 	"github.com/jumboframes/armorigo/log"
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/frontier/pkg/frontier/label"
 
 	"github.com/singchia/go-timer/v2"
 )
@@ -14,6 +19,10 @@ type edgeOption struct {
 	tmr                             Timer
 	edgeID                          *uint64
 	meta                            []byte
+	labels                          map[string]string
+	credential                      string
+	hmacSecret                      []byte
+	redirect                        func(addr string) (net.Conn, error)
 	readBufferSize, writeBufferSize int
 }
 
@@ -45,6 +54,61 @@ func OptionEdgeMeta(meta []byte) EdgeOption {
 	}
 }
 
+// Labels of the edge, like "site": "fra1", services and the control plane select edges by them,
+// keys are like "site" or "example.com/site", names and values are at most 63 alphanumerics, '-', '_' or '.'
+func OptionEdgeLabels(labels map[string]string) EdgeOption {
+	return func(opt *edgeOption) {
+		opt.labels = labels
+	}
+}
+
+// Credential for the token or jwt auth mode of frontier
+func OptionEdgeCredential(credential string) EdgeOption {
+	return func(opt *edgeOption) {
+		opt.credential = credential
+	}
+}
+
+// Sign the meta for the hmac auth mode of frontier, the secret is not sent, the signature
+// carries the time it's issued, so the clock of the edge must be within the max skew of frontier
+func OptionEdgeHMAC(secret []byte) EdgeOption {
+	return func(opt *edgeOption) {
+		opt.hmacSecret = secret
+	}
+}
+
+// Dial the frontier picked by the draining one at the next reconnect, addr is its advertised edgebound addr,
+// the edge reconnects by the dialer if not set or failed, only for the edge created by NewEdge
+func OptionEdgeRedirect(dial func(addr string) (net.Conn, error)) EdgeOption {
+	return func(opt *edgeOption) {
+		opt.redirect = dial
+	}
+}
+
+// the meta to connect, carries the labels and credential if any
+func (opt *edgeOption) connMeta() []byte {
+	return opt.seal(opt.meta)
+}
+
+// seal carries the labels, credential and envelope version with meta, frontier authenticates the updated meta too
+func (opt *edgeOption) seal(meta []byte) []byte {
+	if len(opt.labels) != 0 {
+		meta = apis.EncodeLabels(meta, opt.labels)
+	}
+	switch {
+	case opt.hmacSecret != nil:
+		meta = apis.EncodeCredential(meta, apis.SignMeta(opt.hmacSecret, meta, time.Now()))
+	case opt.credential != "":
+		meta = apis.EncodeCredential(meta, opt.credential)
+	}
+	// outside the credential, so frontier tells the source edge of rpcs and messages from other edges
+	return apis.EncodeMetaEnvelope(meta, apis.EnvelopeVersion)
+}
+
+func (opt *edgeOption) check() error {
+	return label.Validate(opt.labels)
+}
+
 func OptionServiceBufferSize(read, write int) EdgeOption {
 	return func(opt *edgeOption) {
 		opt.readBufferSize = read
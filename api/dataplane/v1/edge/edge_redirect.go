@@ -0,0 +1,83 @@
+package edge
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"sync"
+
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/geminio"
+	"github.com/singchia/geminio/client"
+)
+
+// redirector dials the frontier told by the draining one at the next reconnect,
+// and falls back to the dialer if not told or failed
+type redirector struct {
+	dialer client.Dialer
+	// nil means the told addr is ignored
+	dial   func(addr string) (net.Conn, error)
+	logger Logger
+	// the meta geminio connects with, resealed before each dial to renew the hmac issued at,
+	// geminio shares the buffer and reads it after dialing
+	meta []byte
+	seal func() []byte
+
+	mtx  sync.Mutex
+	addr string
+}
+
+func (rd *redirector) Dial() (net.Conn, error) {
+	rd.renew()
+
+	rd.mtx.Lock()
+	addr := rd.addr
+	rd.addr = ""
+	rd.mtx.Unlock()
+
+	if addr != "" {
+		conn, err := rd.dial(addr)
+		if err == nil {
+			return conn, nil
+		}
+		if rd.logger != nil {
+			rd.logger.Warnf("edge redirect dial err: %s, addr: %s", err, addr)
+		}
+	}
+	return rd.dialer()
+}
+
+// renew reseals the meta in place, the length is kept unless the issued at grows a digit
+func (rd *redirector) renew() {
+	if rd.seal == nil {
+		return
+	}
+	meta := rd.seal()
+	if len(meta) != len(rd.meta) {
+		if rd.logger != nil {
+			rd.logger.Warnf("edge renew meta, length changed from %d to %d", len(rd.meta), len(meta))
+		}
+		return
+	}
+	copy(rd.meta, meta)
+}
+
+// reconnect is called by the draining frontier, which closes the connection after
+func (rd *redirector) reconnect(_ context.Context, req geminio.Request, _ geminio.Response) {
+	reconnect := &apis.Reconnect{}
+	if err := json.Unmarshal(req.Data(), reconnect); err != nil {
+		if rd.logger != nil {
+			rd.logger.Errorf("edge reconnect, json unmarshal err: %s", err)
+		}
+		return
+	}
+	if rd.logger != nil {
+		rd.logger.Infof("edge told to reconnect by frontier, addr: %s", reconnect.Addr)
+	}
+	if rd.dial == nil || reconnect.Addr == "" {
+		return
+	}
+	rd.mtx.Lock()
+	rd.addr = reconnect.Addr
+	rd.mtx.Unlock()
+}
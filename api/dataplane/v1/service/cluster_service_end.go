@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net"
 	"strings"
@@ -46,6 +48,7 @@ type clusterServiceEnd struct {
 	// fan-in channels
 	acceptStreamCh chan geminio.Stream
 	acceptMsgCh    chan geminio.Message
+	acceptRawCh    chan *rawData
 
 	closed chan struct{}
 }
@@ -68,6 +71,7 @@ func newclusterServiceEnd(addr string, opts ...ServiceOption) (*clusterServiceEn
 		edgefrontiers:  mapmap.NewBiMap(),
 		acceptStreamCh: make(chan geminio.Stream, 128),
 		acceptMsgCh:    make(chan geminio.Message, 128),
+		acceptRawCh:    make(chan *rawData, 128),
 		closed:         make(chan struct{}),
 	}
 	end.serviceOption.delegate = end
@@ -78,6 +82,7 @@ func newclusterServiceEnd(addr string, opts ...ServiceOption) (*clusterServiceEn
 	if end.serviceOption.logger == nil {
 		end.serviceOption.logger = armlog.DefaultLog
 	}
+	end.topics.Append(end.serviceOption.topics...)
 	err = end.update()
 	if err != nil {
 		return nil, err
@@ -241,13 +246,17 @@ func (end *clusterServiceEnd) newServiceEnd(addr string) (*serviceEnd, error) {
 	dialer := func() (net.Conn, error) {
 		return net.Dial("tcp", addr)
 	}
+	// topics subscribed at runtime are carried in meta of new frontiers
+	topics := end.topics.ToSlice()
 	serviceEnd, err := newServiceEnd(dialer,
 		OptionServiceLog(end.serviceOption.logger),
 		OptionServiceDelegate(end.serviceOption.delegate),
 		OptionServiceName(end.serviceOption.service),
-		OptionServiceReceiveTopics(end.serviceOption.topics),
+		OptionServiceReceiveTopics(topics),
 		OptionServiceTimer(end.serviceOption.tmr),
 		OptionServiceID(end.serviceOption.serviceID),
+		OptionServiceWeight(end.serviceOption.weight),
+		OptionServiceGroup(end.serviceOption.group),
 		OptionServiceBufferSize(end.serviceOption.readBufferSize, end.serviceOption.writeBufferSize))
 	if err != nil {
 		return nil, err
@@ -274,6 +283,15 @@ func (end *clusterServiceEnd) newServiceEnd(addr string) (*serviceEnd, error) {
 			end.acceptMsgCh <- msg
 		}
 	}()
+	go func() {
+		for {
+			edgeID, data, err := serviceEnd.ReadRaw()
+			if err != nil {
+				return
+			}
+			end.acceptRawCh <- &rawData{edgeID: edgeID, data: data}
+		}
+	}()
 
 	end.appMtx.RLock()
 	defer end.appMtx.RUnlock()
@@ -285,6 +303,19 @@ func (end *clusterServiceEnd) newServiceEnd(addr string) (*serviceEnd, error) {
 			goto ERR
 		}
 	}
+	// topics changed while connecting
+	if added := end.topics.Difference(mapset.NewSet(topics...)); added.Cardinality() != 0 {
+		err = serviceEnd.Subscribe(context.TODO(), added.ToSlice()...)
+		if err != nil {
+			goto ERR
+		}
+	}
+	if removed := mapset.NewSet(topics...).Difference(end.topics); removed.Cardinality() != 0 {
+		err = serviceEnd.Unsubscribe(context.TODO(), removed.ToSlice()...)
+		if err != nil {
+			goto ERR
+		}
+	}
 	return serviceEnd, nil
 
 ERR:
@@ -292,6 +323,33 @@ ERR:
 	return nil, err
 }
 
+// rawer
+type rawData struct {
+	edgeID uint64
+	data   []byte
+}
+
+func (end *clusterServiceEnd) WriteRaw(edgeID uint64, data []byte) (int, error) {
+	frontierID, serviceEnd, err := end.lookup(edgeID)
+	if err != nil {
+		return 0, err
+	}
+	n, err := serviceEnd.WriteRaw(edgeID, data)
+	if err != nil {
+		end.clear(frontierID)
+		return n, err
+	}
+	return n, nil
+}
+
+func (end *clusterServiceEnd) ReadRaw() (uint64, []byte, error) {
+	raw, ok := <-end.acceptRawCh
+	if !ok {
+		return 0, nil, io.EOF
+	}
+	return raw.edgeID, raw.data, nil
+}
+
 // multiplexer
 func (end *clusterServiceEnd) AcceptStream() (geminio.Stream, error) {
 	st, ok := <-end.acceptStreamCh
@@ -361,6 +419,110 @@ func (end *clusterServiceEnd) PublishAsync(ctx context.Context, edgeID uint64, m
 	return pub, err
 }
 
+// the message is queued at the frontier where the edge was last seen
+func (end *clusterServiceEnd) PublishQueued(ctx context.Context, edgeID uint64, msg geminio.Message) error {
+	fronterID, serviceEnd, err := end.lookup(edgeID)
+	if err != nil {
+		return err
+	}
+	err = serviceEnd.PublishQueued(ctx, edgeID, msg)
+	if err != nil {
+		end.clear(fronterID)
+		return err
+	}
+	return nil
+}
+
+func (end *clusterServiceEnd) Multicast(ctx context.Context, edgeIDs []uint64, msg geminio.Message) ([]*Delivery, error) {
+	type frontierNedges struct {
+		serviceEnd *serviceEnd
+		edgeIDs    []uint64
+	}
+	deliveries := []*Delivery{}
+	// group edges by frontier
+	groups := map[string]*frontierNedges{}
+	for _, edgeID := range edgeIDs {
+		frontierID, serviceEnd, err := end.lookup(edgeID)
+		if err != nil {
+			deliveries = append(deliveries, &Delivery{EdgeID: edgeID, Error: err})
+			continue
+		}
+		group, ok := groups[frontierID]
+		if !ok {
+			group = &frontierNedges{serviceEnd: serviceEnd}
+			groups[frontierID] = group
+		}
+		group.edgeIDs = append(group.edgeIDs, edgeID)
+	}
+
+	var (
+		mtx sync.Mutex
+		wg  sync.WaitGroup
+	)
+	for frontierID, group := range groups {
+		wg.Add(1)
+		go func(frontierID string, group *frontierNedges) {
+			defer wg.Done()
+			subs, err := group.serviceEnd.Multicast(ctx, group.edgeIDs, msg)
+			if err != nil {
+				end.clear(frontierID)
+				subs = make([]*Delivery, len(group.edgeIDs))
+				for i, edgeID := range group.edgeIDs {
+					subs[i] = &Delivery{EdgeID: edgeID, Error: err}
+				}
+			}
+			mtx.Lock()
+			deliveries = append(deliveries, subs...)
+			mtx.Unlock()
+		}(frontierID, group)
+	}
+	wg.Wait()
+	return deliveries, nil
+}
+
+func (end *clusterServiceEnd) Broadcast(ctx context.Context, selector *EdgeSelector, msg geminio.Message) ([]*Delivery, error) {
+	return end.fanall("broadcast", func(serviceEnd *serviceEnd) ([]*Delivery, error) {
+		return serviceEnd.Broadcast(ctx, selector, msg)
+	})
+}
+
+func (end *clusterServiceEnd) PublishTopic(ctx context.Context, topic string, msg geminio.Message) ([]*Delivery, error) {
+	return end.fanall("publish topic", func(serviceEnd *serviceEnd) ([]*Delivery, error) {
+		return serviceEnd.PublishTopic(ctx, topic, msg)
+	})
+}
+
+// fanall calls fn on all frontiers and merges the deliveries
+func (end *clusterServiceEnd) fanall(op string, fn func(*serviceEnd) ([]*Delivery, error)) ([]*Delivery, error) {
+	var (
+		deliveries = []*Delivery{}
+		mtx        sync.Mutex
+		wg         sync.WaitGroup
+		reterr     error
+	)
+	end.frontiers.Range(func(key, value interface{}) bool {
+		wg.Add(1)
+		go func(frontierID string, serviceEnd *serviceEnd) {
+			defer wg.Done()
+			subs, err := fn(serviceEnd)
+			mtx.Lock()
+			defer mtx.Unlock()
+			if err != nil {
+				end.logger.Errorf("%s to frontier: %s err: %s", op, frontierID, err)
+				reterr = err
+				return
+			}
+			deliveries = append(deliveries, subs...)
+		}(key.(string), value.(*frontierNend).end)
+		return true
+	})
+	wg.Wait()
+	if len(deliveries) == 0 && reterr != nil {
+		return nil, reterr
+	}
+	return deliveries, nil
+}
+
 func (end *clusterServiceEnd) Receive(ctx context.Context) (geminio.Message, error) {
 	msg, ok := <-end.acceptMsgCh
 	if !ok {
@@ -391,6 +553,19 @@ func (end *clusterServiceEnd) Call(ctx context.Context, edgeID uint64, method st
 	return rsp, nil
 }
 
+func (end *clusterServiceEnd) EdgeSupports(ctx context.Context, edgeID uint64, method string) (bool, error) {
+	fronterID, serviceEnd, err := end.lookup(edgeID)
+	if err != nil {
+		return false, err
+	}
+	ok, err := serviceEnd.EdgeSupports(ctx, edgeID, method)
+	if err != nil {
+		end.clear(fronterID)
+		return false, err
+	}
+	return ok, nil
+}
+
 func (end *clusterServiceEnd) CallAsync(ctx context.Context, edgeID uint64, method string, req geminio.Request, ch chan *geminio.Call) (*geminio.Call, error) {
 	fronterID, serviceEnd, err := end.lookup(edgeID)
 	if err != nil {
@@ -423,6 +598,70 @@ func (end *clusterServiceEnd) Register(ctx context.Context, method string, rpc g
 	return err
 }
 
+// Subscribe subscribes topics on all frontiers, the topics are kept for new frontiers only if all succeed,
+// otherwise the subscribed ones are unsubscribed and errors of all failed frontiers are returned
+func (end *clusterServiceEnd) Subscribe(ctx context.Context, topics ...string) error {
+	// new frontiers take the topics kept after the lock released
+	end.appMtx.Lock()
+	defer end.appMtx.Unlock()
+
+	err := end.fanout(ctx, func(ctx context.Context, serviceEnd *serviceEnd) error {
+		return serviceEnd.Subscribe(ctx, topics...)
+	}, func(ctx context.Context, serviceEnd *serviceEnd) error {
+		return serviceEnd.Unsubscribe(ctx, topics...)
+	})
+	if err != nil {
+		return err
+	}
+	end.topics.Append(topics...)
+	return nil
+}
+
+// Unsubscribe unsubscribes topics on all frontiers, the topics are removed only if all succeed,
+// otherwise the unsubscribed ones are subscribed again and errors of all failed frontiers are returned
+func (end *clusterServiceEnd) Unsubscribe(ctx context.Context, topics ...string) error {
+	end.appMtx.Lock()
+	defer end.appMtx.Unlock()
+
+	err := end.fanout(ctx, func(ctx context.Context, serviceEnd *serviceEnd) error {
+		return serviceEnd.Unsubscribe(ctx, topics...)
+	}, func(ctx context.Context, serviceEnd *serviceEnd) error {
+		return serviceEnd.Subscribe(ctx, topics...)
+	})
+	if err != nil {
+		return err
+	}
+	end.topics.RemoveAll(topics...)
+	return nil
+}
+
+// fanout does on all frontiers, and undoes on the done ones if any failed
+func (end *clusterServiceEnd) fanout(ctx context.Context, do, undo func(context.Context, *serviceEnd) error) error {
+	var (
+		done []*frontierNend
+		errs []error
+	)
+	end.frontiers.Range(func(key, value interface{}) bool {
+		fe := value.(*frontierNend)
+		if err := do(ctx, fe.end); err != nil {
+			errs = append(errs, fmt.Errorf("frontier %v: %w", key, err))
+			return true
+		}
+		done = append(done, fe)
+		return true
+	})
+	if len(errs) == 0 {
+		return nil
+	}
+	for _, fe := range done {
+		// ctx may be done already
+		if err := undo(context.TODO(), fe.end); err != nil {
+			end.logger.Errorf("cluster service undo err: %s, frontier: %s", err, fe.frontier.FrontierId)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // net.Listener
 func (end *clusterServiceEnd) Accept() (net.Conn, error) {
 	st, ok := <-end.acceptStreamCh
@@ -454,6 +693,7 @@ func (end *clusterServiceEnd) Addr() net.Addr {
 func (end *clusterServiceEnd) Close() error {
 	close(end.closed)
 	close(end.acceptMsgCh)
+	close(end.acceptRawCh)
 	close(end.acceptStreamCh)
 
 	var (
@@ -523,3 +763,38 @@ func (end *clusterServiceEnd) RegisterEdgeOffline(ctx context.Context, edgeOffli
 		}
 	})
 }
+
+func (end *clusterServiceEnd) RegisterEdgeMetaUpdate(ctx context.Context, edgeMetaUpdate EdgeMetaUpdate) error {
+	return end.Register(ctx, apis.RPCEdgeMetaUpdate, func(ctx context.Context, req geminio.Request, rsp geminio.Response) {
+		update := &apis.OnEdgeMetaUpdate{}
+		err := json.Unmarshal(req.Data(), update)
+		if err != nil {
+			// shouldn't be here
+			rsp.SetError(err)
+			return
+		}
+		err = edgeMetaUpdate(update.EdgeID, update.Meta, update)
+		if err != nil {
+			rsp.SetError(err)
+			return
+		}
+	})
+}
+
+func (end *clusterServiceEnd) RegisterEdgeToEdge(ctx context.Context, edgeToEdge EdgeToEdge) error {
+	return end.Register(ctx, apis.RPCEdgeToEdge, func(ctx context.Context, req geminio.Request, rsp geminio.Response) {
+		e2e := &apis.OnEdgeToEdge{}
+		err := json.Unmarshal(req.Data(), e2e)
+		if err != nil {
+			// shouldn't be here
+			rsp.SetError(err)
+			return
+		}
+		err = edgeToEdge(e2e.SrcEdgeID, e2e.DstEdgeID)
+		if err != nil {
+			// the err will be delivered to the src edge
+			rsp.SetError(err)
+			return
+		}
+	})
+}
@@ -15,6 +15,9 @@ type RPCer interface {
 	Call(ctx context.Context, edgeID uint64, method string, req geminio.Request) (geminio.Response, error)
 	CallAsync(ctx context.Context, edgeID uint64, method string, req geminio.Request, ch chan *geminio.Call) (*geminio.Call, error)
 	Register(ctx context.Context, method string, rpc geminio.RPC) error
+	// EdgeSupports tells whether the edge registered the method, Call to an unregistered
+	// method fails fast with apis.ErrRPCNotRegistered
+	EdgeSupports(ctx context.Context, edgeID uint64, method string) (bool, error)
 }
 
 // Messager is edge oriented
@@ -24,6 +27,37 @@ type Messager interface {
 	Publish(ctx context.Context, edgeID uint64, msg geminio.Message) error
 	PublishAsync(ctx context.Context, edgeID uint64, msg geminio.Message, ch chan *geminio.Publish) (*geminio.Publish, error)
 	Receive(ctx context.Context) (geminio.Message, error)
+	// Publish a message to the edge, the message is queued in frontier's outbox if the edge is offline
+	// and delivered in order when the edge online, instead of failing like Publish,
+	// errors of the online edge like timeouts and rejections are returned without queued
+	PublishQueued(ctx context.Context, edgeID uint64, msg geminio.Message) error
+
+	// Subscribe topics to receive at runtime besides OptionServiceReceiveTopics, without reconnecting,
+	// the subscriptions are kept after reconnected
+	Subscribe(ctx context.Context, topics ...string) error
+	Unsubscribe(ctx context.Context, topics ...string) error
+
+	// Multicast a message to edges, the fan-out is done inside frontier
+	Multicast(ctx context.Context, edgeIDs []uint64, msg geminio.Message) ([]*Delivery, error)
+	// Broadcast a message to all edges matched by the selector, nil selector matches all
+	Broadcast(ctx context.Context, selector *EdgeSelector, msg geminio.Message) ([]*Delivery, error)
+	// PublishTopic publishes a message to all edges subscribed to the topic
+	PublishTopic(ctx context.Context, topic string, msg geminio.Message) ([]*Delivery, error)
+}
+
+// EdgeSelector selects online edges for Broadcast, both conditions apply if set
+type EdgeSelector struct {
+	// prefix of edge meta, empty matches all
+	Meta string
+	// label selector in the kubernetes style, like "site=fra1,model in (x200,x300),!canary", empty matches all
+	Labels string
+}
+
+// Delivery is the result of Multicast, Broadcast or PublishTopic to one edge
+type Delivery struct {
+	EdgeID uint64
+	// nil if the message is delivered
+	Error error
 }
 
 type RPCMessager interface {
@@ -31,10 +65,20 @@ type RPCMessager interface {
 	Messager
 }
 
+// Rawer is raw io with edges on the main connection, routed by frontier with flow control
+type Rawer interface {
+	// Write raw bytes to a specific edge, blocks while the edge is not reading,
+	// and fails if the bytes written before were dropped for the edge is not online
+	WriteRaw(edgeID uint64, data []byte) (int, error)
+	// Read raw bytes from any edge
+	ReadRaw() (edgeID uint64, data []byte, err error)
+}
+
 // Stream multiplexer
 type Multiplexer interface {
-	// Open a stream to specific edgeID
+	// Open a stream to specific edgeID, returns apis.ErrEdgeNotOnline if the edge is offline
 	OpenStream(ctx context.Context, edgeID uint64) (geminio.Stream, error)
+	// rpcs and messages over streams come with ClientID and StreamID of the caller
 	AcceptStream() (geminio.Stream, error)
 	ListStreams() []geminio.Stream
 }
@@ -44,10 +88,18 @@ type GetEdgeID func(meta []byte) (uint64, error)
 type EdgeOnline func(edgeID uint64, meta []byte, addr net.Addr) error
 type EdgeOffline func(edgeID uint64, meta []byte, addr net.Addr) error
 
+// meta is the new one updated by the edge, the return err is only logged by frontier
+type EdgeMetaUpdate func(edgeID uint64, meta []byte, addr net.Addr) error
+
+// return err to deny the src edge reaching the dst edge
+type EdgeToEdge func(srcEdgeID, dstEdgeID uint64) error
+
 type ControlRegister interface {
 	RegisterGetEdgeID(ctx context.Context, getEdgeID GetEdgeID) error
 	RegisterEdgeOnline(ctx context.Context, edgeOnline EdgeOnline) error
 	RegisterEdgeOffline(ctx context.Context, edgeOffline EdgeOffline) error
+	RegisterEdgeMetaUpdate(ctx context.Context, edgeMetaUpdate EdgeMetaUpdate) error
+	RegisterEdgeToEdge(ctx context.Context, edgeToEdge EdgeToEdge) error
 }
 
 // Service
@@ -55,6 +107,9 @@ type Service interface {
 	// Service can direct Message or RPC
 	RPCMessager
 
+	// Service can directly Write or Read raw bytes
+	Rawer
+
 	// Service can manage streams from or to a Edge
 	Multiplexer
 
@@ -4,16 +4,53 @@ import (
 	"context"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/frontier/pkg/frontier/raw"
 	"github.com/singchia/geminio"
 	"github.com/singchia/geminio/client"
+	"github.com/singchia/geminio/delegate"
 	"github.com/singchia/geminio/options"
 )
 
+// max time to wait for frontier to forward an opened stream
+const streamAckTimeout = 30 * time.Second
+
 type serviceEnd struct {
 	geminio.End
+	raw *raw.Conn
+
+	logger Logger
+	// runtime subscriptions, the meta only carries topics of the options
+	topicMtx     sync.Mutex
+	subscribed   map[string]struct{}
+	unsubscribed map[string]struct{}
+}
+
+func newServiceEndFromEnd(end geminio.End, logger Logger) *serviceEnd {
+	return &serviceEnd{
+		End:          end,
+		raw:          raw.NewConn(end),
+		logger:       logger,
+		subscribed:   map[string]struct{}{},
+		unsubscribed: map[string]struct{}{},
+	}
+}
+
+// resubscriber restores the runtime subscriptions after the retry end is online again
+type resubscriber struct {
+	Delegate
+	end *serviceEnd
+}
+
+func (rs *resubscriber) EndReOnline(d delegate.ClientDescriber) {
+	rs.Delegate.EndReOnline(d)
+	// not to block the reinit
+	go rs.end.resubscribe()
 }
 
 func newServiceEnd(dialer client.Dialer, opts ...ServiceOption) (*serviceEnd, error) {
@@ -47,6 +84,9 @@ func newServiceEnd(dialer client.Dialer, opts ...ServiceOption) (*serviceEnd, er
 	if sopt.service != "" {
 		meta.Service = sopt.service
 	}
+	meta.Weight = sopt.weight
+	meta.Group = sopt.group
+	meta.Envelope = apis.EnvelopeVersion
 	data, err := json.Marshal(meta)
 	if err != nil {
 		return nil, err
@@ -61,7 +101,7 @@ func newServiceEnd(dialer client.Dialer, opts ...ServiceOption) (*serviceEnd, er
 	if err != nil {
 		return nil, err
 	}
-	return &serviceEnd{end}, nil
+	return newServiceEndFromEnd(end, sopt.logger), nil
 }
 
 func newRetryServiceEnd(dialer client.Dialer, opts ...ServiceOption) (*serviceEnd, error) {
@@ -91,15 +131,20 @@ func newRetryServiceEnd(dialer client.Dialer, opts ...ServiceOption) (*serviceEn
 	if sopt.service != "" {
 		meta.Service = sopt.service
 	}
+	meta.Weight = sopt.weight
+	meta.Group = sopt.group
+	meta.Envelope = apis.EnvelopeVersion
 	data, err := json.Marshal(meta)
 	if err != nil {
 		return nil, err
 	}
 	sopts.SetMeta(data)
-	// delegate
-	if sopt.delegate != nil {
-		sopts.SetDelegate(sopt.delegate)
+	// delegate, wrapped to resubscribe after reconnected
+	rs := &resubscriber{Delegate: sopt.delegate}
+	if rs.Delegate == nil {
+		rs.Delegate = &delegate.UnimplementedDelegate{}
 	}
+	sopts.SetDelegate(rs)
 	if sopt.readBufferSize != -1 || sopt.writeBufferSize != -1 {
 		sopts.SetBufferSize(sopt.readBufferSize, sopt.writeBufferSize)
 	}
@@ -108,7 +153,8 @@ func newRetryServiceEnd(dialer client.Dialer, opts ...ServiceOption) (*serviceEn
 	if err != nil {
 		return nil, err
 	}
-	return &serviceEnd{end}, nil
+	rs.end = newServiceEndFromEnd(end, sopt.logger)
+	return rs.end, nil
 }
 
 // Control Register
@@ -163,22 +209,49 @@ func (end *serviceEnd) RegisterEdgeOffline(ctx context.Context, edgeOffline Edge
 	})
 }
 
+func (end *serviceEnd) RegisterEdgeMetaUpdate(ctx context.Context, edgeMetaUpdate EdgeMetaUpdate) error {
+	return end.End.Register(ctx, apis.RPCEdgeMetaUpdate, func(ctx context.Context, req geminio.Request, rsp geminio.Response) {
+		update := &apis.OnEdgeMetaUpdate{}
+		err := json.Unmarshal(req.Data(), update)
+		if err != nil {
+			// shouldn't be here
+			rsp.SetError(err)
+			return
+		}
+		err = edgeMetaUpdate(update.EdgeID, update.Meta, update)
+		if err != nil {
+			rsp.SetError(err)
+			return
+		}
+	})
+}
+
+func (end *serviceEnd) RegisterEdgeToEdge(ctx context.Context, edgeToEdge EdgeToEdge) error {
+	return end.End.Register(ctx, apis.RPCEdgeToEdge, func(ctx context.Context, req geminio.Request, rsp geminio.Response) {
+		e2e := &apis.OnEdgeToEdge{}
+		err := json.Unmarshal(req.Data(), e2e)
+		if err != nil {
+			// shouldn't be here
+			rsp.SetError(err)
+			return
+		}
+		err = edgeToEdge(e2e.SrcEdgeID, e2e.DstEdgeID)
+		if err != nil {
+			// the err will be delivered to the src edge
+			rsp.SetError(err)
+			return
+		}
+	})
+}
+
 // RPCer
 func (end *serviceEnd) NewRequest(data []byte) geminio.Request {
 	return end.End.NewRequest(data)
 }
 
 func (end *serviceEnd) Call(ctx context.Context, edgeID uint64, method string, req geminio.Request) (geminio.Response, error) {
-	// we append the likely short one to slice
-	tail := make([]byte, 8)
-	binary.BigEndian.PutUint64(tail, edgeID)
-	custom := req.Custom()
-	if custom == nil {
-		custom = tail
-	} else {
-		custom = append(custom, tail...)
-	}
-	req.SetCustom(custom)
+	// the edgeID is carried in the envelope for frontier
+	setEdgeID(req, edgeID)
 
 	// call real end
 	rsp, err := end.End.Call(ctx, method, req)
@@ -189,19 +262,16 @@ func (end *serviceEnd) Call(ctx context.Context, edgeID uint64, method string, r
 	return rsp, nil
 }
 
+func setEdgeID(carrier apis.Carrier, edgeID uint64) {
+	headers := apis.Headers{}
+	headers.SetUint64(apis.HeaderEdgeID, edgeID)
+	apis.SetHeaders(carrier, headers)
+}
+
 // It's just like the go rpc way
 func (end *serviceEnd) CallAsync(ctx context.Context, edgeID uint64, method string, req geminio.Request, ch chan *geminio.Call) (*geminio.Call, error) {
-	// we append the likely short one to slice
-	// the last 8 bytes is for frontier
-	tail := make([]byte, 8)
-	binary.BigEndian.PutUint64(tail, edgeID)
-	custom := req.Custom()
-	if custom == nil {
-		custom = tail
-	} else {
-		custom = append(custom, tail...)
-	}
-	req.SetCustom(custom)
+	// the edgeID is carried in the envelope for frontier
+	setEdgeID(req, edgeID)
 
 	// call real end
 	call, err := end.End.CallAsync(ctx, method, req, ch)
@@ -214,15 +284,16 @@ func (end *serviceEnd) CallAsync(ctx context.Context, edgeID uint64, method stri
 
 func (end *serviceEnd) Register(ctx context.Context, method string, rpc geminio.RPC) error {
 	wrap := func(_ context.Context, req geminio.Request, rsp geminio.Response) {
-		custom := req.Custom()
-		if len(custom) < 8 {
+		custom, headers, ok := apis.DecodeServiceCustom(req.Custom())
+		if !ok {
 			rpc(ctx, req, rsp)
 			return
 		}
-		edgeID := binary.BigEndian.Uint64(custom[len(custom)-8:])
-		req.SetClientID(edgeID)
-		rsp.SetClientID(edgeID)
-		rpc(ctx, req, rsp)
+		if edgeID, ok := headers.Uint64(apis.HeaderEdgeID); ok {
+			req.SetClientID(edgeID)
+			rsp.SetClientID(edgeID)
+		}
+		rpc(ctx, apis.WithRequestHeaders(req, custom, headers), rsp)
 		return
 	}
 	return end.End.Register(ctx, method, wrap)
@@ -234,15 +305,7 @@ func (end *serviceEnd) NewMessage(data []byte) geminio.Message {
 }
 
 func (end *serviceEnd) Publish(ctx context.Context, edgeID uint64, msg geminio.Message) error {
-	tail := make([]byte, 8)
-	binary.BigEndian.PutUint64(tail, edgeID)
-	custom := msg.Custom()
-	if custom == nil {
-		custom = tail
-	} else {
-		custom = append(custom, tail...)
-	}
-	msg.SetCustom(custom)
+	setEdgeID(msg, edgeID)
 
 	// publish real end
 	err := end.End.Publish(ctx, msg)
@@ -252,15 +315,7 @@ func (end *serviceEnd) Publish(ctx context.Context, edgeID uint64, msg geminio.M
 }
 
 func (end *serviceEnd) PublishAsync(ctx context.Context, edgeID uint64, msg geminio.Message, ch chan *geminio.Publish) (*geminio.Publish, error) {
-	tail := make([]byte, 8)
-	binary.BigEndian.PutUint64(tail, edgeID)
-	custom := msg.Custom()
-	if custom == nil {
-		custom = tail
-	} else {
-		custom = append(custom, tail...)
-	}
-	msg.SetCustom(custom)
+	setEdgeID(msg, edgeID)
 
 	// publish async
 	pub, err := end.End.PublishAsync(ctx, msg, ch)
@@ -268,37 +323,231 @@ func (end *serviceEnd) PublishAsync(ctx context.Context, edgeID uint64, msg gemi
 	return pub, err
 }
 
+// the queueing is served by frontier
+func (end *serviceEnd) PublishQueued(ctx context.Context, edgeID uint64, msg geminio.Message) error {
+	data, err := json.Marshal(&apis.Outbound{
+		EdgeID: edgeID,
+		Topic:  msg.Topic(),
+		Data:   msg.Data(),
+		Custom: msg.Custom(),
+	})
+	if err != nil {
+		return err
+	}
+	req := end.End.NewRequest(data)
+	_, err = end.End.Call(ctx, apis.RPCPublishQueued, req)
+	msg.SetClientID(edgeID)
+	return err
+}
+
+// the registration is looked up by frontier
+func (end *serviceEnd) EdgeSupports(ctx context.Context, edgeID uint64, method string) (bool, error) {
+	data, err := json.Marshal(&apis.EdgeSupports{
+		EdgeID: edgeID,
+		Method: method,
+	})
+	if err != nil {
+		return false, err
+	}
+	req := end.End.NewRequest(data)
+	rsp, err := end.End.Call(ctx, apis.RPCEdgeSupports, req)
+	if err != nil {
+		return false, err
+	}
+	result := &apis.EdgeSupportsResult{}
+	if err = json.Unmarshal(rsp.Data(), result); err != nil {
+		return false, err
+	}
+	return result.Supported, nil
+}
+
+// the subscriptions are served by frontier
+func (end *serviceEnd) Subscribe(ctx context.Context, topics ...string) error {
+	if err := end.subscription(ctx, apis.RPCSubscribe, topics); err != nil {
+		return err
+	}
+	end.topicMtx.Lock()
+	for _, topic := range topics {
+		end.subscribed[topic] = struct{}{}
+		delete(end.unsubscribed, topic)
+	}
+	end.topicMtx.Unlock()
+	return nil
+}
+
+func (end *serviceEnd) Unsubscribe(ctx context.Context, topics ...string) error {
+	if err := end.subscription(ctx, apis.RPCUnsubscribe, topics); err != nil {
+		return err
+	}
+	end.topicMtx.Lock()
+	for _, topic := range topics {
+		delete(end.subscribed, topic)
+		end.unsubscribed[topic] = struct{}{}
+	}
+	end.topicMtx.Unlock()
+	return nil
+}
+
+func (end *serviceEnd) subscription(ctx context.Context, method string, topics []string) error {
+	data, err := json.Marshal(&apis.Subscription{Topics: topics})
+	if err != nil {
+		return err
+	}
+	_, err = end.End.Call(ctx, method, end.End.NewRequest(data))
+	return err
+}
+
+// resubscribe applies the runtime subscriptions to the topics of meta again
+func (end *serviceEnd) resubscribe() {
+	end.topicMtx.Lock()
+	subscribed := make([]string, 0, len(end.subscribed))
+	for topic := range end.subscribed {
+		subscribed = append(subscribed, topic)
+	}
+	unsubscribed := make([]string, 0, len(end.unsubscribed))
+	for topic := range end.unsubscribed {
+		unsubscribed = append(unsubscribed, topic)
+	}
+	end.topicMtx.Unlock()
+
+	if len(subscribed) != 0 {
+		err := end.subscription(context.TODO(), apis.RPCSubscribe, subscribed)
+		if err != nil && end.logger != nil {
+			end.logger.Errorf("service resubscribe err: %s, topics: %v", err, subscribed)
+		}
+	}
+	if len(unsubscribed) != 0 {
+		err := end.subscription(context.TODO(), apis.RPCUnsubscribe, unsubscribed)
+		if err != nil && end.logger != nil {
+			end.logger.Errorf("service unsubscribe again err: %s, topics: %v", err, unsubscribed)
+		}
+	}
+}
+
+func (end *serviceEnd) Multicast(ctx context.Context, edgeIDs []uint64, msg geminio.Message) ([]*Delivery, error) {
+	return end.multicast(ctx, &apis.Multicast{
+		EdgeIDs: edgeIDs,
+		Topic:   msg.Topic(),
+		Data:    msg.Data(),
+		Custom:  msg.Custom(),
+	})
+}
+
+func (end *serviceEnd) Broadcast(ctx context.Context, selector *EdgeSelector, msg geminio.Message) ([]*Delivery, error) {
+	mc := &apis.Multicast{
+		Broadcast: true,
+		Topic:     msg.Topic(),
+		Data:      msg.Data(),
+		Custom:    msg.Custom(),
+	}
+	if selector != nil {
+		mc.Meta = selector.Meta
+		mc.Selector = selector.Labels
+	}
+	return end.multicast(ctx, mc)
+}
+
+func (end *serviceEnd) PublishTopic(ctx context.Context, topic string, msg geminio.Message) ([]*Delivery, error) {
+	return end.multicast(ctx, &apis.Multicast{
+		Subscribed: true,
+		Topic:      topic,
+		Data:       msg.Data(),
+		Custom:     msg.Custom(),
+	})
+}
+
+// the multicast is served by frontier, and we get all deliveries back
+func (end *serviceEnd) multicast(ctx context.Context, mc *apis.Multicast) ([]*Delivery, error) {
+	data, err := json.Marshal(mc)
+	if err != nil {
+		return nil, err
+	}
+	req := end.End.NewRequest(data)
+	rsp, err := end.End.Call(ctx, apis.RPCMulticast, req)
+	if err != nil {
+		return nil, err
+	}
+	result := &apis.MulticastResult{}
+	if err = json.Unmarshal(rsp.Data(), result); err != nil {
+		return nil, err
+	}
+	deliveries := make([]*Delivery, len(result.Deliveries))
+	for i, delivery := range result.Deliveries {
+		deliveries[i] = &Delivery{EdgeID: delivery.EdgeID}
+		if delivery.Error != "" {
+			deliveries[i].Error = errors.New(delivery.Error)
+		}
+	}
+	return deliveries, nil
+}
+
 func (end *serviceEnd) Receive(ctx context.Context) (geminio.Message, error) {
 	msg, err := end.End.Receive(ctx)
 	if err != nil {
 		return nil, err
 	}
-	custom := msg.Custom()
-	if custom == nil || len(custom) < 8 {
+	custom, headers, ok := apis.DecodeServiceCustom(msg.Custom())
+	if !ok {
 		// shoudn't be here
 		return msg, nil
 	}
-	edgeID := binary.BigEndian.Uint64(custom[len(custom)-8:])
-	custom = custom[:len(custom)-8]
-	msg.SetClientID(edgeID)
-	msg.SetCustom(custom)
-	return msg, nil
+	if edgeID, ok := headers.Uint64(apis.HeaderEdgeID); ok {
+		msg.SetClientID(edgeID)
+	}
+	return apis.WithMessageHeaders(msg, custom, headers), nil
+}
+
+// Rawer
+func (end *serviceEnd) WriteRaw(edgeID uint64, data []byte) (int, error) {
+	return end.raw.Write(raw.Peer{ID: edgeID}, data)
+}
+
+func (end *serviceEnd) ReadRaw() (uint64, []byte, error) {
+	peer, data, err := end.raw.Read()
+	return peer.ID, data, err
 }
 
 // Multiplexer
 func (end *serviceEnd) OpenStream(ctx context.Context, edgeID uint64) (geminio.Stream, error) {
 	id := strconv.FormatUint(edgeID, 10)
+	meta, _ := json.Marshal(&apis.StreamOpen{Ack: true, Caller: true})
 	opt := options.OpenStream()
 	opt.SetPeer(id)
-	return end.End.OpenStream(opt)
+	opt.SetMeta(meta)
+	stream, err := end.End.OpenStream(opt)
+	if err != nil {
+		return nil, err
+	}
+	stream = apis.WithCaller(stream)
+	// wait for frontier to tell whether the stream is forwarded to the edge
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(streamAckTimeout)
+	}
+	stream.SetDeadline(deadline)
+	err = apis.WaitStreamAck(stream)
+	stream.SetDeadline(time.Time{})
+	if err != nil {
+		stream.Close()
+		return nil, err
+	}
+	return stream, nil
 }
 
 func (end *serviceEnd) AcceptStream() (geminio.Stream, error) {
-	return end.End.AcceptStream()
+	stream, err := end.End.AcceptStream()
+	if err != nil {
+		return nil, err
+	}
+	return apis.WithCaller(stream), nil
 }
 
 func (end *serviceEnd) ListStreams() []geminio.Stream {
-	return end.End.ListStreams()
+	streams := end.End.ListStreams()
+	for i, stream := range streams {
+		streams[i] = apis.WithCaller(stream)
+	}
+	return streams
 }
 
 func (end *serviceEnd) Close() error {
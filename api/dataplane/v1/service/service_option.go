@@ -23,6 +23,10 @@ type serviceOption struct {
 	delegate                        Delegate
 	serviceID                       uint64
 	readBufferSize, writeBufferSize int
+	// to tell frontier our weight in weighted hashby, default 1
+	weight int
+	// to tell frontier our delivery group of topics
+	group string
 }
 
 type ServiceOption func(*serviceOption)
@@ -40,6 +44,7 @@ func OptionServiceTimer(tmr Timer) ServiceOption {
 	}
 }
 
+// Topics to receive, MQTT style wildcards "+" and "#" are supported, like "telemetry/+/+/temp"
 func OptionServiceReceiveTopics(topics []string) ServiceOption {
 	return func(opt *serviceOption) {
 		opt.topics = topics
@@ -65,6 +70,21 @@ func OptionServiceID(serviceID uint64) ServiceOption {
 	}
 }
 
+// Weight of the service instance when frontier hashes by weighted
+func OptionServiceWeight(weight int) ServiceOption {
+	return func(opt *serviceOption) {
+		opt.weight = weight
+	}
+}
+
+// Delivery group of the receiving topics, services in the same group share messages,
+// and each group gets a copy. Default no group, sharing with external MQs and other services without group
+func OptionServiceGroup(group string) ServiceOption {
+	return func(opt *serviceOption) {
+		opt.group = group
+	}
+}
+
 func OptionServiceBufferSize(read, write int) ServiceOption {
 	return func(opt *serviceOption) {
 		opt.readBufferSize = read
@@ -4,17 +4,20 @@ import (
 	"context"
 	_ "net/http/pprof"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/jumboframes/armorigo/sigaction"
 	"github.com/singchia/frontier/pkg/frontier"
+	"github.com/singchia/frontier/pkg/utils"
 	"k8s.io/klog/v2"
 )
 
-// drainSecondsFromEnv 决定 SIGTERM 抵达后、调用 frontier.Close() 前的等待时长。
-// 这段时间内进程不接新流量也不主动断已有连接——给上游 kube-proxy
-// 把本 pod 从 endpoints 摘除、给已建立的 edge 长连接自然结束的窗口。
+// drainSecondsFromEnv 决定 SIGTERM 或 control plane 要求 drain 后，等待 edge 排空的最长时间。
+// 这段时间内 /readyz 返回 NotReady、不接新的 edge，已有 edge 在 rpc 和 stream 结束后被通知重连并关闭——
+// 给上游 kube-proxy 把本 pod 从 endpoints 摘除、给已建立的 edge 长连接自然结束的窗口。
 //
 // 上限由 K8s 侧的 terminationGracePeriodSeconds 控制。Operator 默认设 60s，
 // 给 Close 自身留 ~10s 余量，所以 drain 默认 30s 是合理起点。用户可调。
@@ -45,12 +48,39 @@ func main() {
 	frontier.Run()
 
 	sig := sigaction.NewSignal()
-	sig.Wait(context.TODO())
+	// reload certs without restarting
+	sig.Add(syscall.SIGHUP, utils.CertsNotifier{})
+	// drain on signals or the control plane
+	ctx, cancel := context.WithCancel(context.TODO())
+	stopped := make(chan struct{})
+	go func() {
+		sig.Wait(ctx)
+		close(stopped)
+	}()
 
-	if drain := drainSecondsFromEnv(); drain > 0 {
-		klog.Infof("frontier received shutdown signal, draining for %ds before close", drain)
-		time.Sleep(time.Duration(drain) * time.Second)
+	drain := time.Duration(drainSecondsFromEnv()) * time.Second
+	drainCtx, drainCancel := context.WithCancel(context.TODO())
+	defer drainCancel()
+	select {
+	case <-stopped:
+		klog.Infof("frontier received shutdown signal, draining for at most %s before close", drain)
+	case timeout := <-frontier.DrainRequested():
+		if timeout > 0 {
+			drain = timeout
+		}
+		klog.Infof("frontier drain requested, draining for at most %s before close", drain)
+		// a shutdown signal while draining cuts it short, and the next one is handled by os
+		go func() {
+			<-stopped
+			drainCancel()
+		}()
 	}
+	if drain > 0 {
+		frontier.Drain(drainCtx, drain)
+	}
+	// stop waiting for signals, and let os handle them while closing
+	cancel()
+	signal.Reset(sigaction.ReservedFiniSignals...)
 
 	frontier.Close()
 }
@@ -3,9 +3,11 @@ package main
 import (
 	"context"
 	_ "net/http/pprof"
+	"syscall"
 
 	"github.com/jumboframes/armorigo/sigaction"
 	"github.com/singchia/frontier/pkg/frontlas"
+	"github.com/singchia/frontier/pkg/utils"
 	"k8s.io/klog/v2"
 )
 
@@ -18,6 +20,8 @@ func main() {
 	frontlas.Run()
 
 	sig := sigaction.NewSignal()
+	// reload certs without restarting
+	sig.Add(syscall.SIGHUP, utils.CertsNotifier{})
 	sig.Wait(context.TODO())
 
 	frontlas.Close()
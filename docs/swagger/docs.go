@@ -22,6 +22,33 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/v1/drain": {
+            "post": {
+                "tags": [
+                    "1.0"
+                ],
+                "summary": "Drain",
+                "parameters": [
+                    {
+                        "description": "body",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.DrainRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "result",
+                        "schema": {
+                            "$ref": "#/definitions/v1.DrainResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/edges": {
             "get": {
                 "tags": [
@@ -69,6 +96,11 @@ const docTemplate = `{
                         "name": "rpc",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "name": "selector",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "name": "start_time",
@@ -182,6 +214,54 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/edges/{edge_id}/ratelimit": {
+            "get": {
+                "tags": [
+                    "1.0"
+                ],
+                "summary": "Get Edge Rate Limit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "name": "edge_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "result",
+                        "schema": {
+                            "$ref": "#/definitions/v1.EdgeRateLimit"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "tags": [
+                    "1.0"
+                ],
+                "summary": "Set Edge Rate Limit",
+                "parameters": [
+                    {
+                        "description": "body",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.SetEdgeRateLimitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "result",
+                        "schema": {
+                            "$ref": "#/definitions/v1.EdgeRateLimit"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/services": {
             "get": {
                 "tags": [
@@ -402,6 +482,18 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "v1.DrainRequest": {
+            "type": "object",
+            "properties": {
+                "timeout": {
+                    "description": "seconds to wait at most for the edges, FRONTIER_DRAIN_SECONDS by default",
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.DrainResponse": {
+            "type": "object"
+        },
         "v1.Edge": {
             "type": "object",
             "properties": {
@@ -414,11 +506,34 @@ const docTemplate = `{
                 "edge_id": {
                     "type": "integer"
                 },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "meta": {
                     "type": "string"
                 }
             }
         },
+        "v1.EdgeRateLimit": {
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "type": "integer"
+                },
+                "edge_id": {
+                    "type": "integer"
+                },
+                "messages": {
+                    "type": "integer"
+                },
+                "rpcs": {
+                    "type": "integer"
+                }
+            }
+        },
         "v1.KickEdgeResponse": {
             "type": "object"
         },
@@ -511,6 +626,23 @@ const docTemplate = `{
                     "type": "integer"
                 }
             }
+        },
+        "v1.SetEdgeRateLimitRequest": {
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "type": "integer"
+                },
+                "edge_id": {
+                    "type": "integer"
+                },
+                "messages": {
+                    "type": "integer"
+                },
+                "rpcs": {
+                    "type": "integer"
+                }
+            }
         }
     }
 }`
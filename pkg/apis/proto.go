@@ -13,6 +13,7 @@ const (
 
 	// frontier related
 	RPCFrontierStats = "frontier_stats"
+	RPCFrontierPick  = "frontier_pick"
 )
 
 type FrontierInstance struct {
@@ -29,6 +30,11 @@ type FrontierStats struct {
 	ServiceCount int    `json:"service_count"`
 }
 
+// ask frontlas for another frontier to take over the edges while draining
+type FrontierPick struct {
+	FrontierID string `json:"frontier_id"`
+}
+
 // edge protocols
 type EdgeOnline struct {
 	FrontierID string `json:"frontier_id"`
@@ -0,0 +1,105 @@
+package apis
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// the credential of edges is carried in the envelope of meta, and stripped by frontier
+const HeaderCredential = "frontier-credential"
+
+// reason codes of rejected edges
+type AuthCode string
+
+const (
+	AuthMissingCredential AuthCode = "missing_credential"
+	AuthInvalidCredential AuthCode = "invalid_credential"
+	AuthInvalidSignature  AuthCode = "invalid_signature"
+	AuthUnknownKey        AuthCode = "unknown_key"
+	AuthExpired           AuthCode = "expired"
+	AuthInvalidClaims     AuthCode = "invalid_claims"
+	// mtls client certificates
+	AuthMissingCertificate AuthCode = "missing_certificate"
+	AuthInvalidCertificate AuthCode = "invalid_certificate"
+	AuthIdentityMismatch   AuthCode = "identity_mismatch"
+)
+
+const authErrPrefix = "auth rejected: "
+
+// AuthError is returned to the edge while connecting, only the string survives the wire,
+// use ParseAuthError to get the code back
+type AuthError struct {
+	Code   AuthCode
+	Reason string
+}
+
+func NewAuthError(code AuthCode, reason string) *AuthError {
+	return &AuthError{Code: code, Reason: reason}
+}
+
+func (err *AuthError) Error() string {
+	if err.Reason == "" {
+		return authErrPrefix + string(err.Code)
+	}
+	return authErrPrefix + string(err.Code) + ", " + err.Reason
+}
+
+// ParseAuthError returns the AuthError from the error or its string, ok is false if it's not
+func ParseAuthError(err error) (*AuthError, bool) {
+	if err == nil {
+		return nil, false
+	}
+	var ae *AuthError
+	if errors.As(err, &ae) {
+		return ae, true
+	}
+	str := err.Error()
+	idx := strings.Index(str, authErrPrefix)
+	if idx < 0 {
+		return nil, false
+	}
+	code, reason, _ := strings.Cut(str[idx+len(authErrPrefix):], ", ")
+	return &AuthError{Code: AuthCode(code), Reason: reason}, true
+}
+
+// EncodeCredential appends the credential to meta, meta is not modified
+func EncodeCredential(meta []byte, credential string) []byte {
+	custom, headers, ok := DecodeEnvelope(meta)
+	if !ok {
+		headers = Headers{}
+	}
+	headers[HeaderCredential] = credential
+	return EncodeEnvelope(custom, headers)
+}
+
+// DecodeCredential strips the credential from meta, credential is empty if there is none
+func DecodeCredential(meta []byte) ([]byte, string) {
+	custom, headers, ok := DecodeEnvelope(meta)
+	if !ok {
+		return meta, ""
+	}
+	credential, ok := headers[HeaderCredential]
+	if !ok {
+		return meta, ""
+	}
+	delete(headers, HeaderCredential)
+	if len(headers) == 0 {
+		return custom, credential
+	}
+	return EncodeEnvelope(custom, headers), credential
+}
+
+// SignMeta returns the credential of the hmac auth mode, like "<issued at in unix seconds>.<hex hmac-sha256>",
+// the issued at is signed with meta, frontier rejects credentials out of its max skew
+func SignMeta(secret, meta []byte, issuedAt time.Time) string {
+	ts := strconv.FormatInt(issuedAt.Unix(), 10)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts + "."))
+	mac.Write(meta)
+	return ts + "." + hex.EncodeToString(mac.Sum(nil))
+}
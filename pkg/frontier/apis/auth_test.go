@@ -0,0 +1,55 @@
+package apis
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/singchia/frontier/pkg/frontier/label"
+)
+
+func TestCredential(t *testing.T) {
+	for _, meta := range [][]byte{nil, []byte("meta")} {
+		got, credential := DecodeCredential(EncodeCredential(meta, "token"))
+		if string(got) != string(meta) || credential != "token" {
+			t.Fatalf("decode credential got %q, %q", got, credential)
+		}
+	}
+	got, credential := DecodeCredential([]byte("meta"))
+	if string(got) != "meta" || credential != "" {
+		t.Fatalf("decode meta without credential got %q, %q", got, credential)
+	}
+}
+
+func TestParseAuthError(t *testing.T) {
+	err := NewAuthError(AuthExpired, "token expired")
+	// only the string is delivered to edges
+	ae, ok := ParseAuthError(errors.New(err.Error()))
+	if !ok || ae.Code != AuthExpired || ae.Reason != "token expired" {
+		t.Fatalf("parse auth error got %v, %v", ae, ok)
+	}
+	if _, ok := ParseAuthError(ErrEdgeNotOnline); ok {
+		t.Fatal("parse non auth error should fail")
+	}
+}
+
+func TestLabels(t *testing.T) {
+	labels := map[string]string{"site": "fra1", "model": "x200"}
+	// the credential is outside
+	encoded := EncodeCredential(EncodeLabels([]byte("meta"), labels), "token")
+	meta, credential := DecodeCredential(encoded)
+	if credential != "token" {
+		t.Fatalf("decode credential got %q", credential)
+	}
+	got, gotLabels, err := DecodeLabels(meta)
+	if err != nil || string(got) != "meta" || len(gotLabels) != 2 || gotLabels["site"] != "fra1" {
+		t.Fatalf("decode labels got %q, %v, %v", got, gotLabels, err)
+	}
+	got, gotLabels, err = DecodeLabels([]byte("meta"))
+	if err != nil || string(got) != "meta" || gotLabels != nil {
+		t.Fatalf("decode meta without labels got %q, %v, %v", got, gotLabels, err)
+	}
+	_, _, err = DecodeLabels(EncodeLabels(nil, map[string]string{"site": "fra 1"}))
+	if !errors.Is(err, label.ErrIllegalLabel) {
+		t.Fatalf("decode illegal labels got %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package apis
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"strconv"
+
+	"github.com/singchia/geminio"
+)
+
+// headers of rpcs and messages are carried in a versioned envelope at the tail of custom:
+// | custom | headers json | headers length 4 bytes | version 1 byte | magic 4 bytes |
+// the old 8 bytes edgeID tail from services is still translated by frontier.
+const EnvelopeVersion = 1
+
+var envelopeMagic = []byte{0xf7, 0x6e, 0x76, 0x6c}
+
+const envelopeTrailerLen = 4 + 1 + 4
+
+// headers set by frontier, the others are passed through
+const (
+	// the edge of rpcs and messages between edge and service, or the source edge between edges
+	HeaderEdgeID = "frontier-edge-id"
+	// the frontier which forwards
+	HeaderFrontierID = "frontier-id"
+	// the caller of rpcs and messages over streams
+	HeaderClientID = "frontier-client-id"
+	HeaderStreamID = "frontier-stream-id"
+	// the deadline of rpcs in unix milliseconds
+	HeaderDeadline = "frontier-deadline"
+	// w3c trace context, passed through
+	HeaderTraceParent = "traceparent"
+)
+
+type Headers map[string]string
+
+func (headers Headers) Get(key string) string {
+	return headers[key]
+}
+
+func (headers Headers) Uint64(key string) (uint64, bool) {
+	value, ok := headers[key]
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	return n, err == nil
+}
+
+func (headers Headers) SetUint64(key string, n uint64) {
+	headers[key] = strconv.FormatUint(n, 10)
+}
+
+// EncodeEnvelope appends headers to custom, custom is not modified
+func EncodeEnvelope(custom []byte, headers Headers) []byte {
+	data, _ := json.Marshal(headers)
+	trailer := make([]byte, envelopeTrailerLen)
+	binary.BigEndian.PutUint32(trailer[:4], uint32(len(data)))
+	trailer[4] = EnvelopeVersion
+	copy(trailer[5:], envelopeMagic)
+
+	buf := make([]byte, 0, len(custom)+len(data)+envelopeTrailerLen)
+	buf = append(buf, custom...)
+	buf = append(buf, data...)
+	return append(buf, trailer...)
+}
+
+// DecodeEnvelope splits custom and headers, ok is false if there is no envelope
+func DecodeEnvelope(custom []byte) ([]byte, Headers, bool) {
+	if len(custom) < envelopeTrailerLen || !bytes.Equal(custom[len(custom)-4:], envelopeMagic) {
+		return custom, nil, false
+	}
+	trailer := custom[len(custom)-envelopeTrailerLen:]
+	if trailer[4] == 0 || trailer[4] > EnvelopeVersion {
+		return custom, nil, false
+	}
+	n := int(binary.BigEndian.Uint32(trailer[:4]))
+	pos := len(custom) - envelopeTrailerLen - n
+	if pos < 0 {
+		return custom, nil, false
+	}
+	headers := Headers{}
+	if err := json.Unmarshal(custom[pos:pos+n], &headers); err != nil {
+		return custom, nil, false
+	}
+	return custom[:pos], headers, true
+}
+
+// the envelope version known by edges is carried in the envelope of meta outside the credential,
+// and stripped by frontier, 0 means edges get custom as it's sent
+const HeaderEnvelope = "frontier-envelope"
+
+// EncodeMetaEnvelope appends the envelope version to meta, meta is not modified
+func EncodeMetaEnvelope(meta []byte, version int) []byte {
+	custom, headers, ok := DecodeEnvelope(meta)
+	if !ok {
+		headers = Headers{}
+	}
+	headers[HeaderEnvelope] = strconv.Itoa(version)
+	return EncodeEnvelope(custom, headers)
+}
+
+// DecodeMetaEnvelope strips the envelope version from meta, version is 0 if there is none
+func DecodeMetaEnvelope(meta []byte) ([]byte, int) {
+	custom, headers, ok := DecodeEnvelope(meta)
+	if !ok {
+		return meta, 0
+	}
+	value, ok := headers[HeaderEnvelope]
+	if !ok {
+		return meta, 0
+	}
+	delete(headers, HeaderEnvelope)
+	if len(headers) != 0 {
+		custom = EncodeEnvelope(custom, headers)
+	}
+	version, _ := strconv.Atoi(value)
+	return custom, version
+}
+
+// DecodeServiceCustom splits custom and headers from services, and translates the old edgeID tail
+func DecodeServiceCustom(custom []byte) ([]byte, Headers, bool) {
+	if custom, headers, ok := DecodeEnvelope(custom); ok {
+		return custom, headers, true
+	}
+	if len(custom) < 8 {
+		return custom, nil, false
+	}
+	headers := Headers{}
+	headers.SetUint64(HeaderEdgeID, binary.BigEndian.Uint64(custom[len(custom)-8:]))
+	return custom[:len(custom)-8], headers, true
+}
+
+// Carrier is a request or message, the custom of responses isn't carried by geminio
+type Carrier interface {
+	Custom() []byte
+	SetCustom([]byte)
+}
+
+// SetHeaders adds headers to a request or message before sending
+func SetHeaders(carrier Carrier, headers Headers) {
+	custom, old, _ := DecodeEnvelope(carrier.Custom())
+	if old == nil {
+		old = Headers{}
+	}
+	for key, value := range headers {
+		old[key] = value
+	}
+	carrier.SetCustom(EncodeEnvelope(custom, old))
+}
+
+// GetHeaders returns headers of a request or message received by sdk
+func GetHeaders(carrier any) Headers {
+	if h, ok := carrier.(interface{ Headers() Headers }); ok {
+		return h.Headers()
+	}
+	return nil
+}
+
+type headerRequest struct {
+	geminio.Request
+	headers Headers
+}
+
+func (req *headerRequest) Headers() Headers { return req.headers }
+
+type headerMessage struct {
+	geminio.Message
+	headers Headers
+}
+
+func (msg *headerMessage) Headers() Headers { return msg.headers }
+
+// WithRequestHeaders is for sdk to strip the envelope and attach headers to the request
+func WithRequestHeaders(req geminio.Request, custom []byte, headers Headers) geminio.Request {
+	req.SetCustom(custom)
+	return &headerRequest{req, headers}
+}
+
+// WithMessageHeaders is for sdk to strip the envelope and attach headers to the message
+func WithMessageHeaders(msg geminio.Message, custom []byte, headers Headers) geminio.Message {
+	msg.SetCustom(custom)
+	return &headerMessage{msg, headers}
+}
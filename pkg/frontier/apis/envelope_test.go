@@ -0,0 +1,79 @@
+package apis
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestEnvelope(t *testing.T) {
+	headers := Headers{"x-user": "u"}
+	headers.SetUint64(HeaderEdgeID, 10086)
+	for _, custom := range [][]byte{nil, []byte("custom")} {
+		buf := EncodeEnvelope(custom, headers)
+		got, gotHeaders, ok := DecodeEnvelope(buf)
+		if !ok {
+			t.Fatalf("decode envelope failed, custom: %q", custom)
+		}
+		if !bytes.Equal(got, custom) {
+			t.Fatalf("decode custom got %q, want %q", got, custom)
+		}
+		if edgeID, _ := gotHeaders.Uint64(HeaderEdgeID); edgeID != 10086 || gotHeaders.Get("x-user") != "u" {
+			t.Fatalf("decode headers got %v", gotHeaders)
+		}
+	}
+	if _, _, ok := DecodeEnvelope([]byte("no envelope")); ok {
+		t.Fatal("decode custom without envelope should fail")
+	}
+}
+
+func TestDecodeServiceCustom(t *testing.T) {
+	// the old edgeID tail
+	tail := make([]byte, 8)
+	binary.BigEndian.PutUint64(tail, 10086)
+	custom, headers, ok := DecodeServiceCustom(append([]byte("custom"), tail...))
+	if !ok || string(custom) != "custom" {
+		t.Fatalf("decode old tail got %q, %v", custom, ok)
+	}
+	if edgeID, _ := headers.Uint64(HeaderEdgeID); edgeID != 10086 {
+		t.Fatalf("decode old tail edgeID got %d", edgeID)
+	}
+	if _, _, ok := DecodeServiceCustom([]byte("short")); ok {
+		t.Fatal("decode short custom should fail")
+	}
+}
+
+func TestSetHeaders(t *testing.T) {
+	carrier := &custom{data: []byte("custom")}
+	SetHeaders(carrier, Headers{"a": "1"})
+	SetHeaders(carrier, Headers{"b": "2"})
+	got, headers, ok := DecodeEnvelope(carrier.data)
+	if !ok || string(got) != "custom" || headers.Get("a") != "1" || headers.Get("b") != "2" {
+		t.Fatalf("set headers got %q, %v", got, headers)
+	}
+}
+
+type custom struct {
+	data []byte
+}
+
+func (c *custom) Custom() []byte        { return c.data }
+func (c *custom) SetCustom(data []byte) { c.data = data }
+
+func TestMetaEnvelope(t *testing.T) {
+	// the envelope version is outside the credential
+	signed := EncodeLabels([]byte("meta"), map[string]string{"site": "fra1"})
+	encoded := EncodeMetaEnvelope(EncodeCredential(signed, "token"), EnvelopeVersion)
+	meta, version := DecodeMetaEnvelope(encoded)
+	if version != EnvelopeVersion {
+		t.Fatalf("decode envelope version got %d", version)
+	}
+	meta, credential := DecodeCredential(meta)
+	if credential != "token" || string(meta) != string(signed) {
+		t.Fatalf("decode credential got %q, %q", meta, credential)
+	}
+	got, version := DecodeMetaEnvelope([]byte("meta"))
+	if string(got) != "meta" || version != 0 {
+		t.Fatalf("decode meta without envelope version got %q, %d", got, version)
+	}
+}
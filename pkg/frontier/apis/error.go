@@ -14,6 +14,18 @@ var (
 	ErrIllegalEdgeID    = errors.New("illegal edgeID")
 	ErrRecordNotFound   = gorm.ErrRecordNotFound
 	ErrEmptyAddress     = errors.New("empty address")
+	ErrEdgeToEdgeDenied = errors.New("edge to edge denied")
+	ErrOutboxFull       = errors.New("outbox full")
+	ErrBacklogFull      = errors.New("backlog full")
+	ErrFailoverExceeded = errors.New("failover attempts exceeded")
+	ErrStreamRefused    = errors.New("stream refused")
+	ErrRateLimited      = errors.New("rate limited")
+	ErrCircuitOpen      = errors.New("circuit open")
+	ErrRPCNotRegistered = errors.New("rpc not registered")
+	ErrTooManyTopics    = errors.New("too many topics")
+	ErrTopicTooLong     = errors.New("topic too long")
+	// the edge is reconnecting to another frontier, retry later
+	ErrEdgeDraining = errors.New("edge draining")
 )
 
 var (
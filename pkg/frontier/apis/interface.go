@@ -1,7 +1,9 @@
 package apis
 
 import (
+	"context"
 	"net"
+	"time"
 
 	"github.com/singchia/frontier/pkg/frontier/repo/model"
 	"github.com/singchia/frontier/pkg/frontier/repo/query"
@@ -23,10 +25,29 @@ type Exchange interface {
 	ForwardToService(geminio.End)
 	// stream to service
 	StreamToService(geminio.Stream)
+	// deliver messages queued while the edge was offline
+	FlushOutbox(geminio.End)
+
+	// for management
+	// override the configured rate limit of the edge, nil to restore
+	SetEdgeRateLimit(edgeID uint64, limit *RateLimit)
+	GetEdgeRateLimit(edgeID uint64) RateLimit
+	// rpcs and messages forwarded from or to the edge and not returned yet
+	EdgeInflight(edgeID uint64) int
+	// refuse new rpcs, messages and streams from or to the edge with ErrEdgeDraining until undrain is called
+	DrainEdge(edgeID uint64) (undrain func())
 
 	// for exchange
 	AddEdgebound(Edgebound)
 	AddServicebound(Servicebound)
+	Close() error
+}
+
+// RateLimit in per second, the burst is the same as the rate, 0 means unlimited
+type RateLimit struct {
+	Messages int
+	RPCs     int
+	Bytes    int
 }
 
 // edge related
@@ -34,7 +55,18 @@ type Edgebound interface {
 	ListEdges() []geminio.End
 	// for management
 	GetEdgeByID(edgeID uint64) geminio.End
+	// whether the rpc is registered by the edge, err if the repo can't tell
+	HasEdgeRPC(edgeID uint64, rpc string) (bool, error)
+	// topics subscribed by edges
+	SubscribeTopics(edgeID uint64, topics []string) error
+	UnsubscribeTopics(edgeID uint64, topics []string) error
+	GetEdgeIDsByTopic(topic string) ([]uint64, error)
+	// meta updated by the edge after online, it's authenticated as connecting and the stored meta is returned
+	UpdateEdgeMeta(edgeID uint64, meta []byte) ([]byte, error)
 	DelEdgeByID(edgeID uint64) error
+	// stop accepting edges, and close the online ones after their rpcs and streams are done,
+	// they are told to reconnect to addr if not empty
+	Drain(ctx context.Context, addr string) error
 
 	Serve() error
 	Close() error
@@ -52,6 +84,9 @@ type Servicebound interface {
 	GetServicesByTopic(topic string) ([]geminio.End, error)
 	DelServiceByID(serviceID uint64) error
 	DelSerivces(service string) error
+	// topics received by the service, updated at runtime
+	SubscribeTopics(end geminio.End, topics []string) error
+	UnsubscribeTopics(end geminio.End, topics []string) error
 
 	Serve() error
 	Close() error
@@ -80,16 +115,26 @@ type Repo interface {
 	CountServiceTopics(query *query.ServiceTopicQuery) (int64, error)
 	CountServices(query *query.ServiceQuery) (int64, error)
 	CreateEdge(edge *model.Edge) error
+	CreateEdgeLabel(label *model.EdgeLabel) error
 	CreateEdgeRPC(rpc *model.EdgeRPC) error
+	CreateEdgeTopic(topic *model.EdgeTopic) error
 	CreateService(service *model.Service) error
 	CreateServiceRPC(rpc *model.ServiceRPC) error
 	CreateServiceTopic(topic *model.ServiceTopic) error
 	DeleteEdge(delete *query.EdgeDelete) error
+	DeleteEdgeLabels(edgeID uint64) error
 	DeleteEdgeRPCs(edgeID uint64) error
+	DeleteEdgeTopic(edgeID uint64, topic string) error
+	DeleteEdgeTopics(edgeID uint64) error
 	DeleteService(delete *query.ServiceDelete) error
 	DeleteServiceRPCs(serviceID uint64) error
+	DeleteServiceTopic(serviceID uint64, topic string) error
 	DeleteServiceTopics(serviceID uint64) error
 	GetEdge(edgeID uint64) (*model.Edge, error)
+	GetEdgeLabels(edgeID uint64) ([]*model.EdgeLabel, error)
+	GetEdgesLabels(edgeIDs []uint64) (map[uint64][]*model.EdgeLabel, error)
+	GetEdgeRPC(edgeID uint64, rpc string) (*model.EdgeRPC, error)
+	GetEdgeTopics(topic string) ([]*model.EdgeTopic, error)
 	GetService(serviceID uint64) (*model.Service, error)
 	GetServiceByName(name string) (*model.Service, error)
 	GetServicesByName(name string) ([]*model.Service, error)
@@ -102,6 +147,7 @@ type Repo interface {
 	ListServiceRPCs(query *query.ServiceRPCQuery) ([]string, error)
 	ListServiceTopics(query *query.ServiceTopicQuery) ([]string, error)
 	ListServices(query *query.ServiceQuery) ([]*model.Service, error)
+	UpdateEdgeMeta(edgeID uint64, meta string) error
 }
 
 // mq manager and mq related
@@ -111,7 +157,8 @@ type MQM interface {
 	AddMQ(topics []string, mq MQ)
 	AddMQByEnd(topics []string, end geminio.End)
 	DelMQ(mq MQ)
-	DelMQByEnd(end geminio.End)
+	// only from the topics if any given
+	DelMQByEnd(end geminio.End, topics ...string)
 	GetMQ(topic string) MQ
 	GetMQs(topic string) []MQ
 }
@@ -122,9 +169,31 @@ type MQ interface {
 }
 
 type ProduceOption struct {
-	Origin interface{}
-	EdgeID uint64
-	Addr   net.Addr
+	Origin  interface{}
+	EdgeID  uint64
+	Addr    net.Addr
+	Breaker Breaker
+}
+
+// Breaker tells whether a service instance is tripped, and records the result of forwarding to it
+type Breaker interface {
+	// Ready tells whether the instance can be picked, it doesn't change the state
+	Ready(serviceID uint64) bool
+	// Allow claims the call to the picked instance, only one probe is let through to a half-open instance
+	Allow(serviceID uint64) bool
+	Record(serviceID uint64, err error, latency time.Duration)
+}
+
+// Drainer drains frontier on the call of the control plane
+type Drainer interface {
+	// RequestDrain asks to drain and then exit, timeout 0 means the default
+	RequestDrain(timeout time.Duration)
+}
+
+// EdgeAuthenticator authenticates edges before their edgeIDs are allocated,
+// return an *AuthError to reject the edge with a reason code
+type EdgeAuthenticator interface {
+	Authenticate(meta []byte, credential string) error
 }
 
 type OptionProduce func(*ProduceOption)
@@ -146,3 +215,10 @@ func WithAddr(addr net.Addr) OptionProduce {
 		po.Addr = addr
 	}
 }
+
+// WithBreaker skips the tripped service instances
+func WithBreaker(breaker Breaker) OptionProduce {
+	return func(po *ProduceOption) {
+		po.Breaker = breaker
+	}
+}
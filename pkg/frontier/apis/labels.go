@@ -0,0 +1,47 @@
+package apis
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/singchia/frontier/pkg/frontier/label"
+)
+
+// the labels claimed by edges are carried in the envelope of meta as a json object,
+// inside the credential so that the hmac signature covers them, and stripped by frontier
+const HeaderLabels = "frontier-labels"
+
+// EncodeLabels appends the labels to meta, meta is not modified
+func EncodeLabels(meta []byte, labels map[string]string) []byte {
+	custom, headers, ok := DecodeEnvelope(meta)
+	if !ok {
+		headers = Headers{}
+	}
+	data, _ := json.Marshal(labels)
+	headers[HeaderLabels] = string(data)
+	return EncodeEnvelope(custom, headers)
+}
+
+// DecodeLabels strips the labels from meta, labels is nil if there is none
+func DecodeLabels(meta []byte) ([]byte, map[string]string, error) {
+	custom, headers, ok := DecodeEnvelope(meta)
+	if !ok {
+		return meta, nil, nil
+	}
+	value, ok := headers[HeaderLabels]
+	if !ok {
+		return meta, nil, nil
+	}
+	delete(headers, HeaderLabels)
+	if len(headers) != 0 {
+		custom = EncodeEnvelope(custom, headers)
+	}
+	labels := map[string]string{}
+	if err := json.Unmarshal([]byte(value), &labels); err != nil {
+		return custom, nil, fmt.Errorf("%w: %s", label.ErrIllegalLabel, err)
+	}
+	if err := label.Validate(labels); err != nil {
+		return custom, nil, err
+	}
+	return custom, labels, nil
+}
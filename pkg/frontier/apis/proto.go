@@ -1,11 +1,22 @@
 package apis
 
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
 // frontier -> service
 // global rpcs
 var (
 	RPCGetEdgeID   = "get_edge_id"
 	RPCEdgeOnline  = "edge_online"
 	RPCEdgeOffline = "edge_offline"
+	RPCEdgeToEdge  = "edge_to_edge"
+	// the edge updated its meta after online
+	RPCEdgeMetaUpdate = "edge_meta_update"
 )
 
 type OnEdgeOnline struct {
@@ -39,9 +50,211 @@ func (offline *OnEdgeOffline) String() string {
 	return offline.Str
 }
 
+// frontier -> service
+type OnEdgeMetaUpdate struct {
+	EdgeID uint64
+	Meta   []byte
+	Net    string
+	Str    string
+}
+
+func (update *OnEdgeMetaUpdate) Network() string {
+	return update.Net
+}
+
+func (update *OnEdgeMetaUpdate) String() string {
+	return update.Str
+}
+
+// frontier -> service
+// ask service whether the edge is allowed to reach the peer edge
+type OnEdgeToEdge struct {
+	SrcEdgeID uint64 `json:"src_edge_id"`
+	DstEdgeID uint64 `json:"dst_edge_id"`
+}
+
+// stream opener -> frontier, carried in the stream meta to ask frontier
+// to tell whether the stream is forwarded, and then written as the first packet,
+// frontier acks after reading it, so the ack never comes before the stream is opened
+type StreamOpen struct {
+	Ack bool `json:"ack"`
+	// the opener takes the caller in the envelope of rpcs and messages forwarded over the stream
+	Caller bool `json:"caller,omitempty"`
+}
+
+// frontier -> stream acceptor, carried in the stream meta, rpcs and messages forwarded over the stream
+// come from the caller, the acceptor doesn't get the caller in the envelope unless it's a service knowing envelopes
+type StreamCaller struct {
+	ClientID uint64 `json:"client_id"`
+	StreamID uint64 `json:"stream_id"`
+}
+
+// frontier -> stream opener, the first raw packet of the stream if asked
+type StreamAck struct {
+	Error string `json:"error,omitempty"`
+}
+
+// Err returns the typed error of the ack
+func (ack *StreamAck) Err() error {
+	for _, err := range []error{ErrEdgeNotOnline, ErrServiceNotOnline, ErrIllegalEdgeID, ErrEdgeToEdgeDenied, ErrStreamRefused, ErrEdgeDraining} {
+		if ack.Error == err.Error() {
+			return err
+		}
+	}
+	if ack.Error != "" {
+		return errors.New(ack.Error)
+	}
+	return nil
+}
+
+// WaitStreamAck writes the StreamOpen and reads the StreamAck of a stream opened with StreamOpen.Ack
+func WaitStreamAck(rw io.ReadWriter) error {
+	data, _ := json.Marshal(&StreamOpen{Ack: true, Caller: true})
+	if _, err := rw.Write(data); err != nil {
+		return err
+	}
+	buf := make([]byte, 1024)
+	n, err := rw.Read(buf)
+	if err != nil {
+		return err
+	}
+	ack := &StreamAck{}
+	if err = json.Unmarshal(buf[:n], ack); err != nil {
+		return err
+	}
+	return ack.Err()
+}
+
 // service -> frontier
 // meta carried when service inited
 type Meta struct {
 	Service string   `json:"service"`
 	Topics  []string `json:"topics"`
+	// weight in weighted hashby, 0 is treated as 1
+	Weight int `json:"weight,omitempty"`
+	// delivery group of topics, services in the same group share messages and each group gets a copy
+	Group string `json:"group,omitempty"`
+	// envelope version known by the service, 0 means the old edgeID tail
+	Envelope int `json:"envelope,omitempty"`
+}
+
+// service -> frontier
+// rpcs served by frontier itself rather than forwarded to edge
+var (
+	RPCMulticast     = "frontier_multicast"
+	RPCPublishQueued = "frontier_publish_queued"
+	RPCEdgeSupports  = "frontier_edge_supports"
+)
+
+// edge or service -> frontier
+// rpcs served by frontier itself rather than forwarded to service or edge
+var (
+	RPCSubscribe   = "frontier_subscribe"
+	RPCUnsubscribe = "frontier_unsubscribe"
+)
+
+// edge -> frontier
+// rpcs served by frontier itself rather than forwarded to service
+var (
+	RPCUpdateMeta = "frontier_update_meta"
+)
+
+// edge -> frontier
+// the new meta of the edge, replaces the one carried when connected,
+// it carries the labels and credential like connecting, the labels can't be changed
+type MetaUpdate struct {
+	Meta []byte `json:"meta"`
+}
+
+// frontier -> edge
+// rpcs served by the edge sdk
+var (
+	RPCReconnect = "frontier_reconnect"
+)
+
+// frontier -> edge
+// frontier is draining, the edge is closed after the call and should reconnect, to Addr if not empty
+type Reconnect struct {
+	Addr string `json:"addr,omitempty"`
+}
+
+// edge or service -> frontier
+// topics to subscribe or unsubscribe at runtime
+type Subscription struct {
+	Topics []string `json:"topics"`
+}
+
+// service -> frontier
+// multicast a message to edges, the fan-out is done inside frontier
+type Multicast struct {
+	// target edges, ignored when Broadcast or Subscribed is set
+	EdgeIDs []uint64 `json:"edge_ids,omitempty"`
+	// publish to edges subscribed to the Topic
+	Subscribed bool `json:"subscribed,omitempty"`
+	// broadcast to all edges matched by the selector
+	Broadcast bool   `json:"broadcast,omitempty"`
+	Meta      string `json:"meta,omitempty"` // prefix of edge meta, empty matches all
+	// label selector of edges, see label.ParseSelector, empty matches all
+	Selector string `json:"selector,omitempty"`
+	// the message
+	Topic  string `json:"topic,omitempty"`
+	Data   []byte `json:"data"`
+	Custom []byte `json:"custom,omitempty"`
+}
+
+// frontier -> service
+type MulticastResult struct {
+	Deliveries []*Delivery `json:"deliveries"`
+}
+
+type Delivery struct {
+	EdgeID uint64 `json:"edge_id"`
+	Error  string `json:"error,omitempty"`
+}
+
+// service -> frontier
+// ask whether the edge registered the method
+type EdgeSupports struct {
+	EdgeID uint64 `json:"edge_id"`
+	Method string `json:"method"`
+}
+
+// frontier -> service
+type EdgeSupportsResult struct {
+	Supported bool `json:"supported"`
+}
+
+// service -> frontier
+// message to the edge, queued in outbox if the edge is offline
+type Outbound struct {
+	EdgeID uint64 `json:"edge_id"`
+	Topic  string `json:"topic,omitempty"`
+	Data   []byte `json:"data"`
+	Custom []byte `json:"custom,omitempty"`
+}
+
+// edge -> edge
+// the target edge is carried by the prefix of method, topic or stream peer,
+// like "$edge/123/echo" to call method echo at edge 123
+const EdgeTargetPrefix = "$edge/"
+
+func EdgeTarget(edgeID uint64, key string) string {
+	target := EdgeTargetPrefix + strconv.FormatUint(edgeID, 10)
+	if key == "" {
+		return target
+	}
+	return target + "/" + key
+}
+
+func ParseEdgeTarget(target string) (uint64, string, bool) {
+	if !strings.HasPrefix(target, EdgeTargetPrefix) {
+		return 0, "", false
+	}
+	target = target[len(EdgeTargetPrefix):]
+	id, key, _ := strings.Cut(target, "/")
+	edgeID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return edgeID, key, true
 }
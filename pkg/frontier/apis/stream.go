@@ -0,0 +1,101 @@
+package apis
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/singchia/geminio"
+	"github.com/singchia/geminio/options"
+)
+
+// EncodeCaller adds the caller to the envelope of rpcs and messages forwarded over streams to the side asked for it,
+// the clientID is the edgeID or serviceID of the caller, and the streamID is the stream at the caller's side
+func EncodeCaller(custom []byte, clientID, streamID uint64) []byte {
+	custom, headers, _ := DecodeEnvelope(custom)
+	if headers == nil {
+		headers = Headers{}
+	}
+	headers.SetUint64(HeaderClientID, clientID)
+	headers.SetUint64(HeaderStreamID, streamID)
+	return EncodeEnvelope(custom, headers)
+}
+
+// callerStream sets the caller to ClientID and StreamID of rpcs and messages forwarded over the stream,
+// and attaches the headers
+type callerStream struct {
+	geminio.Stream
+	// told by the meta of streams opened by frontier, nil if not told
+	caller *StreamCaller
+}
+
+// WithCaller is for sdk to wrap the streams forwarded by frontier
+func WithCaller(stream geminio.Stream) geminio.Stream {
+	if stream == nil {
+		return nil
+	}
+	if _, ok := stream.(*callerStream); ok {
+		return stream
+	}
+	cs := &callerStream{Stream: stream}
+	caller := &StreamCaller{}
+	if err := json.Unmarshal(stream.Meta(), caller); err == nil && caller.ClientID != 0 {
+		cs.caller = caller
+	}
+	return cs
+}
+
+func (stream *callerStream) withCaller(req geminio.Request, rsp geminio.Response) geminio.Request {
+	if caller := stream.caller; caller != nil {
+		req.SetClientID(caller.ClientID)
+		rsp.SetClientID(caller.ClientID)
+		req.SetStreamID(caller.StreamID)
+		rsp.SetStreamID(caller.StreamID)
+	}
+	custom, headers, ok := DecodeEnvelope(req.Custom())
+	if !ok {
+		return req
+	}
+	if clientID, ok := headers.Uint64(HeaderClientID); ok {
+		req.SetClientID(clientID)
+		rsp.SetClientID(clientID)
+	}
+	if streamID, ok := headers.Uint64(HeaderStreamID); ok {
+		req.SetStreamID(streamID)
+		rsp.SetStreamID(streamID)
+	}
+	return WithRequestHeaders(req, custom, headers)
+}
+
+func (stream *callerStream) Register(ctx context.Context, method string, rpc geminio.RPC) error {
+	return stream.Stream.Register(ctx, method, func(ctx context.Context, req geminio.Request, rsp geminio.Response) {
+		rpc(ctx, stream.withCaller(req, rsp), rsp)
+	})
+}
+
+func (stream *callerStream) Hijack(rpc geminio.HijackRPC, opts ...*options.HijackOptions) error {
+	return stream.Stream.Hijack(func(ctx context.Context, method string, req geminio.Request, rsp geminio.Response) {
+		rpc(ctx, method, stream.withCaller(req, rsp), rsp)
+	}, opts...)
+}
+
+func (stream *callerStream) Receive(ctx context.Context) (geminio.Message, error) {
+	msg, err := stream.Stream.Receive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if caller := stream.caller; caller != nil {
+		msg.SetClientID(caller.ClientID)
+		msg.SetStreamID(caller.StreamID)
+	}
+	custom, headers, ok := DecodeEnvelope(msg.Custom())
+	if !ok {
+		return msg, nil
+	}
+	if clientID, ok := headers.Uint64(HeaderClientID); ok {
+		msg.SetClientID(clientID)
+	}
+	if streamID, ok := headers.Uint64(HeaderStreamID); ok {
+		msg.SetStreamID(streamID)
+	}
+	return WithMessageHeaders(msg, custom, headers), nil
+}
@@ -30,6 +30,13 @@ type Daemon struct {
 	PProf  PProf  `yaml:"pprof,omitempty" json:"pprof"`
 	// use with frontlas
 	FrontierID string `yaml:"frontier_id,omitempty" json:"frontier_id"`
+	Drain      Drain  `yaml:"drain,omitempty" json:"drain"`
+}
+
+// draining on SIGTERM or the control plane, the deadline is FRONTIER_DRAIN_SECONDS
+type Drain struct {
+	// tell edges to reconnect to the frontier picked by frontlas, use with frontlas
+	Redirect bool `yaml:"redirect,omitempty" json:"redirect"`
 }
 
 // edgebound
@@ -46,6 +53,74 @@ type Edgebound struct {
 	BypassEnable bool          `yaml:"bypass_enable,omitempty" json:"bypass_enable"`
 	// alloc edgeID when no get_id function online
 	EdgeIDAllocWhenNoIDServiceOn bool `yaml:"edgeid_alloc_when_no_idservice_on" json:"edgeid_alloc_when_no_idservice_on"`
+	// authenticate edges before their edgeIDs are allocated
+	Auth EdgeAuth `yaml:"auth,omitempty" json:"auth"`
+	// derive edgeIDs and labels from verified mtls client certificates
+	CertIdentity CertIdentity `yaml:"cert_identity,omitempty" json:"cert_identity"`
+	// limit connections before handshaking
+	Admission Admission `yaml:"admission,omitempty" json:"admission"`
+	// limit the topics subscribed by edges
+	Subscription Subscription `yaml:"subscription,omitempty" json:"subscription"`
+}
+
+// Subscription limits the topics subscribed by each edge, 0 means the default
+type Subscription struct {
+	// max topics subscribed by an edge, default 1024
+	MaxTopics int `yaml:"max_topics,omitempty" json:"max_topics"`
+	// max length of a topic in bytes, default 256
+	MaxTopicLength int `yaml:"max_topic_length,omitempty" json:"max_topic_length"`
+}
+
+// Admission closes the exceeded connections before the geminio end is built, 0 means unlimited
+type Admission struct {
+	// max edges online and handshaking
+	MaxEdges int `yaml:"max_edges,omitempty" json:"max_edges"`
+	// max concurrent handshakes, the others wait in the backlog
+	MaxHandshakes int `yaml:"max_handshakes,omitempty" json:"max_handshakes"`
+	// in-process queue of accepted connections waiting for handshaking, not the listen backlog
+	// of the kernel, each holds a fd, default 1024 if max_handshakes is set
+	Backlog int `yaml:"backlog,omitempty" json:"backlog"`
+	// new connections per second from each source ip, the burst is the same as the rate
+	ConnRate int `yaml:"conn_rate,omitempty" json:"conn_rate"`
+}
+
+// EdgeAuth checks the credential carried by edges, rejected edges get a reason code
+type EdgeAuth struct {
+	Enable bool `yaml:"enable" json:"enable"`
+	// token, hmac or jwt
+	Mode string `yaml:"mode" json:"mode"`
+	// static tokens for the token mode
+	Tokens []string `yaml:"tokens,omitempty" json:"tokens"`
+	// secret for the hmac mode, edges sign their meta by hmac-sha256 with it
+	Secret string `yaml:"secret,omitempty" json:"secret"`
+	// in seconds between the signed issued at and now for the hmac mode, default 300
+	MaxSkew int `yaml:"max_skew,omitempty" json:"max_skew"`
+	// for the jwt mode
+	JWT JWT `yaml:"jwt,omitempty" json:"jwt"`
+}
+
+// CertIdentity maps fields of client certificates to edges, fields are
+// cn, san_uri, san_dns, serial or oid:<dotted oid> of a subject attribute or an extension
+type CertIdentity struct {
+	Enable bool `yaml:"enable" json:"enable"`
+	// field of the edgeID, empty means edgeIDs still come from meta or GetEdgeID
+	EdgeID string `yaml:"edge_id,omitempty" json:"edge_id"`
+	// regexp to extract the edgeID from the field by the first group
+	EdgeIDPattern string `yaml:"edge_id_pattern,omitempty" json:"edge_id_pattern"`
+	// the meta claimed by edges must equal the field if set
+	Meta string `yaml:"meta,omitempty" json:"meta"`
+	// label key to field
+	Labels map[string]string `yaml:"labels,omitempty" json:"labels"`
+}
+
+type JWT struct {
+	// local jwks file to verify tokens
+	JWKS string `yaml:"jwks" json:"jwks"`
+	// check the iss and aud claims if not empty
+	Issuer   string `yaml:"issuer,omitempty" json:"issuer"`
+	Audience string `yaml:"audience,omitempty" json:"audience"`
+	// in seconds to tolerate clock skew on exp and nbf, default 60
+	Leeway int `yaml:"leeway,omitempty" json:"leeway"`
 }
 
 // servicebound
@@ -228,8 +303,100 @@ type MQM struct {
 }
 
 // exchange
+// Outbox stores messages to offline edges, and delivers them in order when the edge online
+type Outbox struct {
+	Enable bool `yaml:"enable" json:"enable"`
+	// spool file to persist messages, empty means in memory
+	Path string `yaml:"path,omitempty" json:"path"`
+	// seconds for a message to live in outbox, 0 means never expire
+	TTL int `yaml:"ttl,omitempty" json:"ttl"`
+	// max messages queued for one edge, 0 means unlimited
+	MaxDepth int `yaml:"max_depth,omitempty" json:"max_depth"`
+	// max bytes queued for one edge, 0 means unlimited
+	MaxBytes int `yaml:"max_bytes,omitempty" json:"max_bytes"`
+}
+
+// Timeout for forwarding, all in milliseconds. The caller's deadline is always honored,
+// an override caps it, and the default is only used when neither exists
+type Timeout struct {
+	// default 30000
+	Default int `yaml:"default,omitempty" json:"default"`
+	// rpc method to timeout, including frontier's rpcs like get_edge_id and edge_online
+	Methods map[string]int `yaml:"methods,omitempty" json:"methods"`
+	// message topic to timeout
+	Topics map[string]int `yaml:"topics,omitempty" json:"topics"`
+}
+
+// Forward for asynchronous message forwarding
+type Forward struct {
+	// max messages published to one edge and waiting for acks, default 32
+	EdgeInflight int `yaml:"edge_inflight,omitempty" json:"edge_inflight"`
+	// max messages producing to one topic at the same time, default 128
+	TopicInflight int `yaml:"topic_inflight,omitempty" json:"topic_inflight"`
+	// max messages queued to or from one edge, the exceeded will be rejected, default 1024
+	Backlog int `yaml:"backlog,omitempty" json:"backlog"`
+}
+
+// Failover to the next service instance when forwarding rpc fails
+type Failover struct {
+	// max instances to try including the first one, default 1 means no failover
+	MaxAttempts int `yaml:"max_attempts,omitempty" json:"max_attempts"`
+	// in milliseconds, doubled after each attempt
+	Backoff int `yaml:"backoff,omitempty" json:"backoff"`
+	// idempotent methods opting in, "*" means all
+	Methods []string `yaml:"methods,omitempty" json:"methods"`
+}
+
+// Breaker skips a service instance which keeps failing or slow, and probes it after the cooldown
+type Breaker struct {
+	Enable bool `yaml:"enable" json:"enable"`
+	// in seconds to count calls, default 10
+	Window int `yaml:"window,omitempty" json:"window"`
+	// min calls in the window to judge, default 20
+	MinRequests int `yaml:"min_requests,omitempty" json:"min_requests"`
+	// percent of failed calls to trip, default 50
+	ErrorRate int `yaml:"error_rate,omitempty" json:"error_rate"`
+	// in milliseconds, calls slower than it are counted as failed, 0 means latency isn't judged
+	SlowCall int `yaml:"slow_call,omitempty" json:"slow_call"`
+	// in seconds to skip the tripped instance before probing, default 5
+	Cooldown int `yaml:"cooldown,omitempty" json:"cooldown"`
+}
+
+// Limit in per second, the burst is the same as the rate, 0 means unlimited
+type Limit struct {
+	Messages int `yaml:"messages,omitempty" json:"messages"`
+	RPCs     int `yaml:"rpcs,omitempty" json:"rpcs"`
+	Bytes    int `yaml:"bytes,omitempty" json:"bytes"`
+}
+
+// RateLimit for traffic pushed into frontier, the exceeded will be rejected with rate limited
+type RateLimit struct {
+	// for each edge, can be overridden by the control plane
+	Edge Limit `yaml:"edge,omitempty" json:"edge"`
+	// for each service instance
+	Service Limit `yaml:"service,omitempty" json:"service"`
+	// for each topic produced by edges
+	Topic TopicLimit `yaml:"topic,omitempty" json:"topic"`
+}
+
+// TopicLimit is the Limit without rpcs, which don't go to topics
+type TopicLimit struct {
+	Messages int `yaml:"messages,omitempty" json:"messages"`
+	Bytes    int `yaml:"bytes,omitempty" json:"bytes"`
+}
+
 type Exchange struct {
-	HashBy string `yaml:"hashby" json:"hashby"` // default edgeid, options: srcip random
+	HashBy string `yaml:"hashby" json:"hashby"` // default edgeid, options: srcip random consistent weighted
+	// allow edge to edge when no edge_to_edge policy function online
+	EdgeToEdgeAllowWhenNoPolicyOn bool      `yaml:"edge_to_edge_allow_when_no_policy_on,omitempty" json:"edge_to_edge_allow_when_no_policy_on"`
+	Outbox                        Outbox    `yaml:"outbox,omitempty" json:"outbox"`
+	Timeout                       Timeout   `yaml:"timeout,omitempty" json:"timeout"`
+	Forward                       Forward   `yaml:"forward,omitempty" json:"forward"`
+	Failover                      Failover  `yaml:"failover,omitempty" json:"failover"`
+	Breaker                       Breaker   `yaml:"breaker,omitempty" json:"breaker"`
+	RateLimit                     RateLimit `yaml:"rate_limit,omitempty" json:"rate_limit"`
+	// in milliseconds to cache the edge to edge allowed by the policy function, 0 means asked every time
+	EdgeToEdgeCacheTTL int `yaml:"edge_to_edge_cache_ttl,omitempty" json:"edge_to_edge_cache_ttl"`
 }
 
 type Dao struct {
@@ -16,7 +16,7 @@ type ControlPlane struct {
 	app *kratos.App
 }
 
-func NewControlPlane(conf *config.Configuration, repo apis.Repo, servicebound apis.Servicebound, edgebound apis.Edgebound) (*ControlPlane, error) {
+func NewControlPlane(conf *config.Configuration, repo apis.Repo, servicebound apis.Servicebound, edgebound apis.Edgebound, exchange apis.Exchange, drainer apis.Drainer) (*ControlPlane, error) {
 	listen := &conf.ControlPlane.Listen
 	ln, err := utils.Listen(listen)
 	if err != nil {
@@ -25,7 +25,7 @@ func NewControlPlane(conf *config.Configuration, repo apis.Repo, servicebound ap
 	}
 
 	// service
-	svc := service.NewControlPlaneService(repo, servicebound, edgebound)
+	svc := service.NewControlPlaneService(repo, servicebound, edgebound, exchange, drainer)
 
 	// http and grpc server
 	cm := cmux.New(ln)
@@ -21,13 +21,17 @@ type ControlPlaneService struct {
 	repo         apis.Repo
 	servicebound apis.Servicebound
 	edgebound    apis.Edgebound
+	exchange     apis.Exchange
+	drainer      apis.Drainer
 }
 
-func NewControlPlaneService(repo apis.Repo, servicebound apis.Servicebound, edgebound apis.Edgebound) *ControlPlaneService {
+func NewControlPlaneService(repo apis.Repo, servicebound apis.Servicebound, edgebound apis.Edgebound, exchange apis.Exchange, drainer apis.Drainer) *ControlPlaneService {
 	cp := &ControlPlaneService{
 		repo:         repo,
 		servicebound: servicebound,
 		edgebound:    edgebound,
+		exchange:     exchange,
+		drainer:      drainer,
 	}
 	return cp
 }
@@ -68,6 +72,24 @@ func (cps *ControlPlaneService) ListEdgeRPCs(ctx context.Context, req *v1.ListEd
 	return cps.listEdgeRPCs(ctx, req)
 }
 
+// @Summary Get Edge Rate Limit
+// @Tags 1.0
+// @Param params query v1.GetEdgeRateLimitRequest true "queries"
+// @Success 200 {object} v1.EdgeRateLimit "result"
+// @Router /v1/edges/{edge_id}/ratelimit [get]
+func (cps *ControlPlaneService) GetEdgeRateLimit(ctx context.Context, req *v1.GetEdgeRateLimitRequest) (*v1.EdgeRateLimit, error) {
+	return cps.getEdgeRateLimit(ctx, req)
+}
+
+// @Summary Set Edge Rate Limit
+// @Tags 1.0
+// @Param params body v1.SetEdgeRateLimitRequest true "body"
+// @Success 200 {object} v1.EdgeRateLimit "result"
+// @Router /v1/edges/{edge_id}/ratelimit [put]
+func (cps *ControlPlaneService) SetEdgeRateLimit(ctx context.Context, req *v1.SetEdgeRateLimitRequest) (*v1.EdgeRateLimit, error) {
+	return cps.setEdgeRateLimit(ctx, req)
+}
+
 // @Summary List Services
 // @Tags 1.0
 // @Param params query v1.ListServicesRequest true "queries"
@@ -112,3 +134,12 @@ func (cps *ControlPlaneService) ListServiceRPCs(ctx context.Context, req *v1.Lis
 func (cps *ControlPlaneService) ListServiceTopics(ctx context.Context, req *v1.ListServiceTopicsRequest) (*v1.ListServiceTopicsResponse, error) {
 	return cps.listServiceTopics(ctx, req)
 }
+
+// @Summary Drain
+// @Tags 1.0
+// @Param params body v1.DrainRequest true "body"
+// @Success 200 {object} v1.DrainResponse "result"
+// @Router /v1/drain [post]
+func (cps *ControlPlaneService) Drain(ctx context.Context, req *v1.DrainRequest) (*v1.DrainResponse, error) {
+	return cps.drain(ctx, req)
+}
@@ -4,12 +4,24 @@ import (
 	"context"
 
 	v1 "github.com/singchia/frontier/api/controlplane/frontier/v1"
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/frontier/pkg/frontier/label"
 	"github.com/singchia/frontier/pkg/frontier/repo/dao/membuntdb"
 	"github.com/singchia/frontier/pkg/frontier/repo/model"
 	"github.com/singchia/frontier/pkg/frontier/repo/query"
 )
 
 func (cps *ControlPlaneService) listEdges(_ context.Context, req *v1.ListEdgesRequest) (*v1.ListEdgesResponse, error) {
+	var (
+		selector label.Selector
+		err      error
+	)
+	if req.Selector != nil {
+		selector, err = label.ParseSelector(*req.Selector)
+		if err != nil {
+			return nil, err
+		}
+	}
 	query := &query.EdgeQuery{}
 	// conditions
 	if req.Meta != nil {
@@ -21,6 +33,7 @@ func (cps *ControlPlaneService) listEdges(_ context.Context, req *v1.ListEdgesRe
 	if req.Rpc != nil {
 		query.RPC = *req.Rpc
 	}
+	query.Selector = selector
 	// order
 	if req.Order != nil && len(*req.Order) != 0 {
 		order := *req.Order
@@ -58,6 +71,9 @@ func (cps *ControlPlaneService) listEdges(_ context.Context, req *v1.ListEdgesRe
 		}
 	}
 	retEdges := transferEdges(edges)
+	if err = cps.withLabels(retEdges...); err != nil {
+		return nil, err
+	}
 	return &v1.ListEdgesResponse{
 		Edges: retEdges,
 		Count: int32(count),
@@ -69,7 +85,11 @@ func (cps *ControlPlaneService) getEdge(_ context.Context, req *v1.GetEdgeReques
 	if err != nil {
 		return nil, err
 	}
-	return transferEdge(edge), nil
+	retEdge := transferEdge(edge)
+	if err = cps.withLabels(retEdge); err != nil {
+		return nil, err
+	}
+	return retEdge, nil
 }
 
 func (cps *ControlPlaneService) kickEdge(_ context.Context, req *v1.KickEdgeRequest) (*v1.KickEdgeResponse, error) {
@@ -80,6 +100,31 @@ func (cps *ControlPlaneService) kickEdge(_ context.Context, req *v1.KickEdgeRequ
 	return &v1.KickEdgeResponse{}, nil
 }
 
+func (cps *ControlPlaneService) getEdgeRateLimit(_ context.Context, req *v1.GetEdgeRateLimitRequest) (*v1.EdgeRateLimit, error) {
+	limit := cps.exchange.GetEdgeRateLimit(req.EdgeId)
+	return transferEdgeRateLimit(req.EdgeId, limit), nil
+}
+
+func (cps *ControlPlaneService) setEdgeRateLimit(_ context.Context, req *v1.SetEdgeRateLimitRequest) (*v1.EdgeRateLimit, error) {
+	if req.Messages == nil && req.Rpcs == nil && req.Bytes == nil {
+		// restore the configured
+		cps.exchange.SetEdgeRateLimit(req.EdgeId, nil)
+		return transferEdgeRateLimit(req.EdgeId, cps.exchange.GetEdgeRateLimit(req.EdgeId)), nil
+	}
+	limit := cps.exchange.GetEdgeRateLimit(req.EdgeId)
+	if req.Messages != nil {
+		limit.Messages = int(*req.Messages)
+	}
+	if req.Rpcs != nil {
+		limit.RPCs = int(*req.Rpcs)
+	}
+	if req.Bytes != nil {
+		limit.Bytes = int(*req.Bytes)
+	}
+	cps.exchange.SetEdgeRateLimit(req.EdgeId, &limit)
+	return transferEdgeRateLimit(req.EdgeId, limit), nil
+}
+
 func (cps *ControlPlaneService) listEdgeRPCs(_ context.Context, req *v1.ListEdgeRPCsRequest) (*v1.ListEdgeRPCsResponse, error) {
 	query := &query.EdgeRPCQuery{}
 	// conditions
@@ -129,6 +174,29 @@ func (cps *ControlPlaneService) listEdgeRPCs(_ context.Context, req *v1.ListEdge
 	}, nil
 }
 
+// withLabels fills the labels of edges by one lookup
+func (cps *ControlPlaneService) withLabels(edges ...*v1.Edge) error {
+	edgeIDs := make([]uint64, len(edges))
+	for i, edge := range edges {
+		edgeIDs[i] = edge.EdgeId
+	}
+	edgesLabels, err := cps.repo.GetEdgesLabels(edgeIDs)
+	if err != nil {
+		return err
+	}
+	for _, edge := range edges {
+		labels := edgesLabels[edge.EdgeId]
+		if len(labels) == 0 {
+			continue
+		}
+		edge.Labels = make(map[string]string, len(labels))
+		for _, label := range labels {
+			edge.Labels[label.Key] = label.Value
+		}
+	}
+	return nil
+}
+
 func transferEdges(edges []*model.Edge) []*v1.Edge {
 	retEdges := make([]*v1.Edge, len(edges))
 	for i, edge := range edges {
@@ -147,3 +215,12 @@ func transferEdge(edge *model.Edge) *v1.Edge {
 	}
 	return retEdge
 }
+
+func transferEdgeRateLimit(edgeID uint64, limit apis.RateLimit) *v1.EdgeRateLimit {
+	return &v1.EdgeRateLimit{
+		EdgeId:   edgeID,
+		Messages: int64(limit.Messages),
+		Rpcs:     int64(limit.RPCs),
+		Bytes:    int64(limit.Bytes),
+	}
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/singchia/frontier/api/controlplane/frontier/v1"
+)
+
+func (cps *ControlPlaneService) drain(_ context.Context, req *v1.DrainRequest) (*v1.DrainResponse, error) {
+	timeout := time.Duration(0)
+	if req.Timeout != nil {
+		timeout = time.Duration(*req.Timeout) * time.Second
+	}
+	// the draining goes on after the response
+	cps.drainer.RequestDrain(timeout)
+	return &v1.DrainResponse{}, nil
+}
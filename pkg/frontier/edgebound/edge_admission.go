@@ -0,0 +1,147 @@
+package edgebound
+
+import (
+	"container/list"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/singchia/frontier/pkg/frontier/config"
+	"github.com/singchia/frontier/pkg/frontier/misc"
+	"k8s.io/klog/v2"
+)
+
+const (
+	defaultBacklog = 1024
+	// source ips kept in lru, the least recently seen are evicted when full or idle
+	maxIPs        = 65536
+	ipIdleTimeout = time.Minute
+)
+
+// admission runs before handshaking, so the rejected are closed cheaply
+type admission struct {
+	conf *config.Admission
+	// admitted and not online yet
+	handshaking atomic.Int64
+	// in-process queue of accepted conns waiting for handshakes, they hold fds
+	// and are not the listen backlog of the kernel, nil means unlimited handshakes
+	backlog chan net.Conn
+
+	mtx sync.Mutex
+	// key: source ip, value: element of ipBucket in lru
+	ips map[string]*list.Element
+	// front is the most recently seen
+	lru *list.List
+}
+
+type ipBucket struct {
+	ip     string
+	bucket *misc.Bucket
+	last   time.Time
+}
+
+func newAdmission(conf *config.Admission) *admission {
+	adm := &admission{
+		conf: conf,
+		ips:  map[string]*list.Element{},
+		lru:  list.New(),
+	}
+	if conf.MaxHandshakes > 0 {
+		backlog := conf.Backlog
+		if backlog <= 0 {
+			backlog = defaultBacklog
+		}
+		adm.backlog = make(chan net.Conn, backlog)
+	}
+	return adm
+}
+
+// allowIP takes a token of the source ip
+func (adm *admission) allowIP(addr net.Addr) bool {
+	if adm.conf.ConnRate <= 0 {
+		return true
+	}
+	ip, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		ip = addr.String()
+	}
+	now := time.Now()
+
+	adm.mtx.Lock()
+	defer adm.mtx.Unlock()
+	// the idle ones have full buckets, dropping them changes nothing
+	for elem := adm.lru.Back(); elem != nil; elem = adm.lru.Back() {
+		value := elem.Value.(*ipBucket)
+		if now.Sub(value.last) <= ipIdleTimeout && len(adm.ips) < maxIPs {
+			break
+		}
+		adm.lru.Remove(elem)
+		delete(adm.ips, value.ip)
+	}
+	elem, ok := adm.ips[ip]
+	if ok {
+		adm.lru.MoveToFront(elem)
+	} else {
+		elem = adm.lru.PushFront(&ipBucket{ip: ip, bucket: misc.NewBucket(adm.conf.ConnRate)})
+		adm.ips[ip] = elem
+	}
+	value := elem.Value.(*ipBucket)
+	value.last = now
+	return value.bucket.Take(1)
+}
+
+// admit checks the conn before handshaking, the rejected is closed
+func (em *edgeManager) admit(conn net.Conn) bool {
+	adm := em.adm
+	reason := ""
+	if em.draining.Load() {
+		reason = "draining"
+	} else if limit := adm.conf.MaxEdges; limit > 0 && em.CountEdges()+int(adm.handshaking.Load()) >= limit {
+		reason = "max_edges"
+	} else if !adm.allowIP(conn.RemoteAddr()) {
+		reason = "conn_rate"
+	}
+	if reason != "" {
+		admissionRejected.WithLabelValues(reason).Inc()
+		klog.V(2).Infof("edge manager admission rejected, reason: %s, addr: %s", reason, conn.RemoteAddr())
+		conn.Close()
+		return false
+	}
+	adm.handshaking.Add(1)
+	handshaking.Inc()
+	return true
+}
+
+// dispatch handshakes the admitted conn now or queues it in the backlog
+func (em *edgeManager) dispatch(conn net.Conn) {
+	adm := em.adm
+	if adm.backlog == nil {
+		go em.handshake(conn)
+		return
+	}
+	select {
+	case adm.backlog <- conn:
+	default:
+		adm.handshaking.Add(-1)
+		handshaking.Dec()
+		admissionRejected.WithLabelValues("backlog").Inc()
+		klog.V(2).Infof("edge manager admission rejected, reason: backlog, addr: %s", conn.RemoteAddr())
+		conn.Close()
+	}
+}
+
+// handshakes takes conns from the backlog, it returns after the backlog is closed
+func (em *edgeManager) handshakes() {
+	for conn := range em.adm.backlog {
+		em.handshake(conn)
+	}
+}
+
+func (em *edgeManager) handshake(conn net.Conn) {
+	defer func() {
+		em.adm.handshaking.Add(-1)
+		handshaking.Dec()
+	}()
+	em.handleConn(conn)
+}
@@ -0,0 +1,44 @@
+package edgebound
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/singchia/frontier/pkg/frontier/config"
+)
+
+func TestAdmissionIPs(t *testing.T) {
+	adm := newAdmission(&config.Admission{ConnRate: 1})
+	addr := func(i int) net.Addr {
+		return &net.TCPAddr{IP: net.IPv4(10, byte(i>>16), byte(i>>8), byte(i)), Port: 1}
+	}
+
+	if !adm.allowIP(addr(0)) {
+		t.Fatal("first conn rejected")
+	}
+	// the debt of the refilled fraction is paid by the next one
+	adm.allowIP(addr(0))
+	if adm.allowIP(addr(0)) {
+		t.Fatal("conn over the rate allowed")
+	}
+	// bounded by the lru
+	for i := 1; i <= maxIPs; i++ {
+		adm.allowIP(addr(i))
+	}
+	if len(adm.ips) != maxIPs || adm.lru.Len() != maxIPs {
+		t.Fatalf("unexpected ips: %d, lru: %d", len(adm.ips), adm.lru.Len())
+	}
+	if _, ok := adm.ips["10.0.0.0"]; ok {
+		t.Fatal("least recently seen ip not evicted")
+	}
+
+	// the idle ones are dropped
+	for elem := adm.lru.Front(); elem != nil; elem = elem.Next() {
+		elem.Value.(*ipBucket).last = time.Now().Add(-2 * ipIdleTimeout)
+	}
+	adm.allowIP(addr(0))
+	if len(adm.ips) != 1 || adm.lru.Len() != 1 {
+		t.Fatalf("idle ips kept: %d, lru: %d", len(adm.ips), adm.lru.Len())
+	}
+}
@@ -0,0 +1,143 @@
+package edgebound
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/frontier/pkg/frontier/config"
+	"k8s.io/klog/v2"
+)
+
+const (
+	AuthModeToken = "token"
+	AuthModeHMAC  = "hmac"
+	AuthModeJWT   = "jwt"
+)
+
+type EdgeboundOption func(*edgeManager)
+
+// OptionEdgeAuthenticator replaces the authenticator built from the configuration
+func OptionEdgeAuthenticator(auth apis.EdgeAuthenticator) EdgeboundOption {
+	return func(em *edgeManager) {
+		em.auth = auth
+	}
+}
+
+// NewEdgeAuthenticator returns the built-in authenticator of the mode, nil if auth is disabled
+func NewEdgeAuthenticator(conf *config.EdgeAuth) (apis.EdgeAuthenticator, error) {
+	if !conf.Enable {
+		return nil, nil
+	}
+	switch conf.Mode {
+	case AuthModeToken:
+		if len(conf.Tokens) == 0 {
+			return nil, errors.New("no tokens for the token auth mode")
+		}
+		return &tokenAuthenticator{tokens: conf.Tokens}, nil
+	case AuthModeHMAC:
+		if conf.Secret == "" {
+			return nil, errors.New("no secret for the hmac auth mode")
+		}
+		maxSkew := conf.MaxSkew
+		if maxSkew <= 0 {
+			maxSkew = 300
+		}
+		return &hmacAuthenticator{
+			secret:  []byte(conf.Secret),
+			maxSkew: time.Duration(maxSkew) * time.Second,
+			now:     time.Now,
+		}, nil
+	case AuthModeJWT:
+		return newJWTAuthenticator(&conf.JWT)
+	}
+	return nil, fmt.Errorf("unsupported auth mode: %s", conf.Mode)
+}
+
+// static tokens shared by edges
+type tokenAuthenticator struct {
+	tokens []string
+}
+
+func (auth *tokenAuthenticator) Authenticate(_ []byte, credential string) error {
+	if credential == "" {
+		return apis.NewAuthError(apis.AuthMissingCredential, "")
+	}
+	for _, token := range auth.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(credential)) == 1 {
+			return nil
+		}
+	}
+	return apis.NewAuthError(apis.AuthInvalidCredential, "unknown token")
+}
+
+// meta signed by hmac-sha256 with the issued at, see apis.SignMeta
+type hmacAuthenticator struct {
+	secret  []byte
+	maxSkew time.Duration
+	now     func() time.Time
+}
+
+func (auth *hmacAuthenticator) Authenticate(meta []byte, credential string) error {
+	if credential == "" {
+		return apis.NewAuthError(apis.AuthMissingCredential, "")
+	}
+	ts, _, ok := strings.Cut(credential, ".")
+	if !ok {
+		return apis.NewAuthError(apis.AuthInvalidCredential, "malformed signature")
+	}
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return apis.NewAuthError(apis.AuthInvalidCredential, "malformed issued at")
+	}
+	issuedAt := time.Unix(sec, 0)
+	if !hmac.Equal([]byte(apis.SignMeta(auth.secret, meta, issuedAt)), []byte(credential)) {
+		return apis.NewAuthError(apis.AuthInvalidSignature, "meta signature mismatch")
+	}
+	// replays are limited to the skew
+	if skew := auth.now().Sub(issuedAt); skew > auth.maxSkew || skew < -auth.maxSkew {
+		return apis.NewAuthError(apis.AuthExpired, "issued at out of skew")
+	}
+	return nil
+}
+
+// authenticate strips the credential, labels and envelope version from meta and checks them,
+// meta without them is returned
+func (em *edgeManager) authenticate(meta []byte) ([]byte, error) {
+	// the envelope version only tells what the edge knows, it's not signed
+	meta, _ = apis.DecodeMetaEnvelope(meta)
+	meta, credential := apis.DecodeCredential(meta)
+	// the labels are signed with meta in the hmac mode
+	stripped, _, err := apis.DecodeLabels(meta)
+	if err != nil {
+		klog.V(1).Infof("edge labels rejected, err: %s, meta: %s", err, string(stripped))
+		return stripped, err
+	}
+	// nested headers would be kept in the meta and taken as frontier's
+	if !bytes.Equal(stripMeta(stripped), stripped) {
+		authRejected.WithLabelValues(string(apis.AuthInvalidCredential)).Inc()
+		klog.V(1).Infof("edge meta rejected, nested credential or labels, meta: %s", string(stripped))
+		return stripped, apis.NewAuthError(apis.AuthInvalidCredential, "nested credential or labels in meta")
+	}
+	if em.auth == nil {
+		return stripped, nil
+	}
+	err = em.auth.Authenticate(meta, credential)
+	if err == nil {
+		return stripped, nil
+	}
+	ae, ok := apis.ParseAuthError(err)
+	if !ok {
+		// custom authenticators may return plain errors
+		ae = apis.NewAuthError(apis.AuthInvalidCredential, err.Error())
+	}
+	authRejected.WithLabelValues(string(ae.Code)).Inc()
+	klog.V(1).Infof("edge auth rejected, err: %s, meta: %s", ae, string(stripped))
+	return stripped, ae
+}
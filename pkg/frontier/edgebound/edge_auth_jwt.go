@@ -0,0 +1,300 @@
+package edgebound
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/frontier/pkg/frontier/config"
+)
+
+// jwt signed by asymmetric keys in a local jwks file, hmac algs are not supported
+type jwtAuthenticator struct {
+	keys     map[string]*jwtKey
+	only     *jwtKey
+	issuer   string
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+type jwtKey struct {
+	alg string
+	key crypto.PublicKey
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	// rsa
+	N string `json:"n"`
+	E string `json:"e"`
+	// ec and okp
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Iss string          `json:"iss"`
+	Aud json.RawMessage `json:"aud"`
+	Exp *float64        `json:"exp"`
+	Nbf *float64        `json:"nbf"`
+}
+
+func newJWTAuthenticator(conf *config.JWT) (*jwtAuthenticator, error) {
+	if conf.JWKS == "" {
+		return nil, errors.New("no jwks for the jwt auth mode")
+	}
+	data, err := os.ReadFile(conf.JWKS)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+	leeway := conf.Leeway
+	if leeway <= 0 {
+		leeway = 60
+	}
+	auth := &jwtAuthenticator{
+		keys:     keys,
+		issuer:   conf.Issuer,
+		audience: conf.Audience,
+		leeway:   time.Duration(leeway) * time.Second,
+		now:      time.Now,
+	}
+	if len(keys) == 1 {
+		for _, key := range keys {
+			auth.only = key
+		}
+	}
+	return auth, nil
+}
+
+func parseJWKS(data []byte) (map[string]*jwtKey, error) {
+	set := struct {
+		Keys []*jwk `json:"keys"`
+	}{}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	keys := map[string]*jwtKey{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwks key %q: %s", k.Kid, err)
+		}
+		keys[k.Kid] = &jwtKey{alg: k.Alg, key: key}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no signing keys in jwks")
+	}
+	return keys, nil
+}
+
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exp := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !exp.IsInt64() || exp.Int64() < 3 || exp.Int64() > 1<<31-1 {
+			return nil, errors.New("illegal rsa key")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		// ECDH validates the point on the curve
+		if _, err := pub.ECDH(); err != nil {
+			return nil, err
+		}
+		return pub, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("illegal ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func (auth *jwtAuthenticator) Authenticate(_ []byte, credential string) error {
+	if credential == "" {
+		return apis.NewAuthError(apis.AuthMissingCredential, "")
+	}
+	parts := strings.Split(credential, ".")
+	if len(parts) != 3 {
+		return apis.NewAuthError(apis.AuthInvalidCredential, "malformed token")
+	}
+	header := &jwtHeader{}
+	if err := decodeJWTPart(parts[0], header); err != nil {
+		return apis.NewAuthError(apis.AuthInvalidCredential, "malformed token header")
+	}
+	key, ok := auth.keys[header.Kid]
+	if !ok && header.Kid == "" {
+		// the only key is used if the token doesn't tell
+		key, ok = auth.only, auth.only != nil
+	}
+	if !ok {
+		return apis.NewAuthError(apis.AuthUnknownKey, "kid "+header.Kid)
+	}
+	if key.alg != "" && key.alg != header.Alg {
+		return apis.NewAuthError(apis.AuthInvalidSignature, "alg mismatch")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !verifyJWT(header.Alg, key.key, []byte(parts[0]+"."+parts[1]), sig) {
+		return apis.NewAuthError(apis.AuthInvalidSignature, "token signature mismatch")
+	}
+
+	claims := &jwtClaims{}
+	if err := decodeJWTPart(parts[1], claims); err != nil {
+		return apis.NewAuthError(apis.AuthInvalidCredential, "malformed token claims")
+	}
+	now := auth.now()
+	if claims.Exp != nil && now.Add(-auth.leeway).After(unixTime(*claims.Exp)) {
+		return apis.NewAuthError(apis.AuthExpired, "token expired")
+	}
+	if claims.Nbf != nil && now.Add(auth.leeway).Before(unixTime(*claims.Nbf)) {
+		return apis.NewAuthError(apis.AuthInvalidClaims, "token not valid yet")
+	}
+	if auth.issuer != "" && claims.Iss != auth.issuer {
+		return apis.NewAuthError(apis.AuthInvalidClaims, "issuer mismatch")
+	}
+	if auth.audience != "" && !claims.hasAudience(auth.audience) {
+		return apis.NewAuthError(apis.AuthInvalidClaims, "audience mismatch")
+	}
+	return nil
+}
+
+func decodeJWTPart(part string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func unixTime(sec float64) time.Time {
+	return time.Unix(0, int64(sec*float64(time.Second)))
+}
+
+// aud is a string or an array of strings
+func (claims *jwtClaims) hasAudience(audience string) bool {
+	if len(claims.Aud) == 0 {
+		return false
+	}
+	var aud string
+	if err := json.Unmarshal(claims.Aud, &aud); err == nil {
+		return aud == audience
+	}
+	var auds []string
+	if err := json.Unmarshal(claims.Aud, &auds); err == nil {
+		return slices.Contains(auds, audience)
+	}
+	return false
+}
+
+// ES512 goes with P-521
+var esCurveBits = map[string]int{"256": 256, "384": 384, "512": 521}
+
+func verifyJWT(alg string, key crypto.PublicKey, input, sig []byte) bool {
+	if alg == "EdDSA" {
+		pub, ok := key.(ed25519.PublicKey)
+		return ok && ed25519.Verify(pub, input, sig)
+	}
+	if len(alg) != 5 {
+		return false
+	}
+	var hash crypto.Hash
+	switch alg[2:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	default:
+		return false
+	}
+	h := hash.New()
+	h.Write(input)
+	digest := h.Sum(nil)
+
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		switch alg[:2] {
+		case "RS":
+			return rsa.VerifyPKCS1v15(pub, hash, digest, sig) == nil
+		case "PS":
+			return rsa.VerifyPSS(pub, hash, digest, sig, nil) == nil
+		}
+	case *ecdsa.PublicKey:
+		bits := pub.Curve.Params().BitSize
+		size := (bits + 7) / 8
+		if alg[:2] != "ES" || esCurveBits[alg[2:]] != bits || len(sig) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		return ecdsa.Verify(pub, digest, r, s)
+	}
+	return false
+}
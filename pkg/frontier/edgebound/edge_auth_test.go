@@ -0,0 +1,201 @@
+package edgebound
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/frontier/pkg/frontier/config"
+	"github.com/singchia/geminio"
+)
+
+func authCode(err error) apis.AuthCode {
+	if err == nil {
+		return ""
+	}
+	ae, _ := apis.ParseAuthError(err)
+	return ae.Code
+}
+
+func TestTokenAuthenticator(t *testing.T) {
+	auth, err := NewEdgeAuthenticator(&config.EdgeAuth{Enable: true, Mode: AuthModeToken, Tokens: []string{"t1", "t2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for credential, code := range map[string]apis.AuthCode{
+		"t2": "", "": apis.AuthMissingCredential, "t3": apis.AuthInvalidCredential,
+	} {
+		if got := authCode(auth.Authenticate(nil, credential)); got != code {
+			t.Fatalf("credential %q got code %q, want %q", credential, got, code)
+		}
+	}
+}
+
+func TestHMACAuthenticator(t *testing.T) {
+	auth, err := NewEdgeAuthenticator(&config.EdgeAuth{Enable: true, Mode: AuthModeHMAC, Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	auth.(*hmacAuthenticator).now = func() time.Time { return now }
+
+	meta := []byte("meta")
+	if err := auth.Authenticate(meta, apis.SignMeta([]byte("secret"), meta, now)); err != nil {
+		t.Fatal(err)
+	}
+	// within the default skew
+	if err := auth.Authenticate(meta, apis.SignMeta([]byte("secret"), meta, now.Add(-299*time.Second))); err != nil {
+		t.Fatal(err)
+	}
+	signature := apis.SignMeta([]byte("secret"), meta, now)
+	_, mac, _ := strings.Cut(signature, ".")
+	for credential, code := range map[string]apis.AuthCode{
+		apis.SignMeta([]byte("secret"), []byte("other"), now):            apis.AuthInvalidSignature,
+		apis.SignMeta([]byte("secret"), meta, now.Add(-301*time.Second)): apis.AuthExpired,
+		apis.SignMeta([]byte("secret"), meta, now.Add(301*time.Second)):  apis.AuthExpired,
+		// the issued at is signed
+		"1700000100." + mac: apis.AuthInvalidSignature,
+		mac:                 apis.AuthInvalidCredential,
+		"now." + mac:        apis.AuthInvalidCredential,
+	} {
+		if got := authCode(auth.Authenticate(meta, credential)); got != code {
+			t.Fatalf("credential %q got code %q, want %q", credential, got, code)
+		}
+	}
+}
+
+// counts the authentications
+type countAuthenticator struct {
+	count int
+}
+
+func (auth *countAuthenticator) Authenticate(_ []byte, _ string) error {
+	auth.count++
+	return nil
+}
+
+type testConn struct {
+	meta []byte
+}
+
+func (conn *testConn) ClientID() uint64     { return 1 }
+func (conn *testConn) Meta() []byte         { return conn.meta }
+func (conn *testConn) LocalAddr() net.Addr  { return &net.TCPAddr{} }
+func (conn *testConn) RemoteAddr() net.Addr { return &net.TCPAddr{} }
+func (conn *testConn) Side() geminio.Side   { return geminio.RecipientSide }
+
+func TestEdgeDelegateAuthenticateOnce(t *testing.T) {
+	auth := &countAuthenticator{}
+	em := &edgeManager{conf: &config.Configuration{}, auth: auth}
+
+	ed := &edgeDelegate{edgeManager: em}
+	meta := apis.EncodeCredential([]byte("meta"), "token")
+	if _, err := ed.GetClientID(0, meta); err != nil {
+		t.Fatal(err)
+	}
+	if err := ed.ConnOnline(&testConn{meta: meta}); err != nil {
+		t.Fatal(err)
+	}
+	if auth.count != 1 {
+		t.Fatalf("authenticated %d times, want once", auth.count)
+	}
+	// another meta is authenticated again
+	if err := ed.ConnOnline(&testConn{meta: apis.EncodeCredential([]byte("other"), "token")}); err != nil {
+		t.Fatal(err)
+	}
+	if auth.count != 2 {
+		t.Fatalf("authenticated %d times, want twice", auth.count)
+	}
+}
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signJWT(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	input := b64(header) + "." + b64(payload)
+	var (
+		sig []byte
+		err error
+	)
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		sig = ed25519.Sign(k, []byte(input))
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256([]byte(input))
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k, digest[:])
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(input))
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input + "." + b64(sig)
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	jwks := map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+		{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": b64(edKey.Public().(ed25519.PublicKey))},
+	}}
+	data, _ := json.Marshal(jwks)
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	auth, err := NewEdgeAuthenticator(&config.EdgeAuth{Enable: true, Mode: AuthModeJWT,
+		JWT: config.JWT{JWKS: file, Issuer: "frontier", Audience: "edges"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().Unix()
+	claims := map[string]any{"iss": "frontier", "aud": []string{"edges"}, "exp": now + 60}
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	cases := []struct {
+		name       string
+		credential string
+		code       apis.AuthCode
+	}{
+		{"rsa", signJWT(t, "RS256", "rsa", rsaKey, claims), ""},
+		{"ec", signJWT(t, "ES256", "ec", ecKey, claims), ""},
+		{"ed25519", signJWT(t, "EdDSA", "ed", edKey, claims), ""},
+		{"missing", "", apis.AuthMissingCredential},
+		{"malformed", "abc", apis.AuthInvalidCredential},
+		{"unknown kid", signJWT(t, "ES256", "other", otherKey, claims), apis.AuthUnknownKey},
+		{"wrong key", signJWT(t, "ES256", "ec", otherKey, claims), apis.AuthInvalidSignature},
+		{"alg mismatch", signJWT(t, "RS256", "ec", rsaKey, claims), apis.AuthInvalidSignature},
+		{"expired", signJWT(t, "EdDSA", "ed", edKey, map[string]any{"iss": "frontier", "aud": "edges", "exp": now - 120}), apis.AuthExpired},
+		{"not yet", signJWT(t, "EdDSA", "ed", edKey, map[string]any{"iss": "frontier", "aud": "edges", "nbf": now + 120}), apis.AuthInvalidClaims},
+		{"issuer", signJWT(t, "EdDSA", "ed", edKey, map[string]any{"iss": "other", "aud": "edges"}), apis.AuthInvalidClaims},
+		{"audience", signJWT(t, "EdDSA", "ed", edKey, map[string]any{"iss": "frontier", "aud": "others"}), apis.AuthInvalidClaims},
+	}
+	for _, c := range cases {
+		if got := authCode(auth.Authenticate(nil, c.credential)); got != c.code {
+			t.Fatalf("%s got code %q, want %q", c.name, got, c.code)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package edgebound
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/frontier/pkg/frontier/config"
+	"github.com/singchia/frontier/pkg/frontier/label"
+	"github.com/singchia/geminio/delegate"
+	"github.com/soheilhy/cmux"
+	"k8s.io/klog/v2"
+)
+
+const handshakeTimeout = 10 * time.Second
+
+// certMapper maps fields of verified client certificates to edges
+type certMapper struct {
+	conf    *config.CertIdentity
+	pattern *regexp.Regexp
+}
+
+// certIdentity is derived from the client certificate of an edge
+type certIdentity struct {
+	edgeID uint64
+	hasID  bool
+	// the meta claimed by the edge must equal it if hasMeta
+	meta    string
+	hasMeta bool
+	labels  map[string]string
+}
+
+func newCertMapper(conf *config.Edgebound) (*certMapper, error) {
+	ci := &conf.CertIdentity
+	if !ci.Enable {
+		return nil, nil
+	}
+	if !conf.Listen.TLS.Enable || !conf.Listen.TLS.MTLS {
+		return nil, errors.New("cert identity requires mtls on edgebound")
+	}
+	fields := []string{ci.EdgeID, ci.Meta}
+	keys := map[string]string{}
+	for key, field := range ci.Labels {
+		fields = append(fields, field)
+		keys[key] = ""
+	}
+	if err := label.Validate(keys); err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		if field == "" {
+			continue
+		}
+		if err := checkCertField(field); err != nil {
+			return nil, err
+		}
+	}
+	mapper := &certMapper{conf: ci}
+	if ci.EdgeIDPattern != "" {
+		pattern, err := regexp.Compile(ci.EdgeIDPattern)
+		if err != nil {
+			return nil, err
+		}
+		if pattern.NumSubexp() < 1 {
+			return nil, errors.New("edge_id_pattern needs a group")
+		}
+		mapper.pattern = pattern
+	}
+	return mapper, nil
+}
+
+func checkCertField(field string) error {
+	switch field {
+	case "cn", "san_uri", "san_dns", "serial":
+		return nil
+	}
+	if oid, ok := strings.CutPrefix(field, "oid:"); ok {
+		_, err := parseOID(oid)
+		return err
+	}
+	return fmt.Errorf("unsupported certificate field: %s", field)
+}
+
+func parseOID(dotted string) (asn1.ObjectIdentifier, error) {
+	parts := strings.Split(dotted, ".")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("illegal oid: %s", dotted)
+	}
+	oid := make(asn1.ObjectIdentifier, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("illegal oid: %s", dotted)
+		}
+		oid[i] = n
+	}
+	return oid, nil
+}
+
+// certField returns the value of the field, ok is false if the certificate doesn't have it
+func certField(cert *x509.Certificate, field string) (string, bool) {
+	switch field {
+	case "cn":
+		return cert.Subject.CommonName, cert.Subject.CommonName != ""
+	case "san_uri":
+		if len(cert.URIs) == 0 {
+			return "", false
+		}
+		return cert.URIs[0].String(), true
+	case "san_dns":
+		if len(cert.DNSNames) == 0 {
+			return "", false
+		}
+		return cert.DNSNames[0], true
+	case "serial":
+		return cert.SerialNumber.String(), true
+	}
+	dotted, _ := strings.CutPrefix(field, "oid:")
+	oid, err := parseOID(dotted)
+	if err != nil {
+		return "", false
+	}
+	// subject attributes go first, then extensions
+	for _, name := range cert.Subject.Names {
+		if name.Type.Equal(oid) {
+			value, ok := name.Value.(string)
+			return value, ok
+		}
+	}
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oid) {
+			var value string
+			if _, err := asn1.Unmarshal(ext.Value, &value); err != nil {
+				// not a string, take the raw value
+				return string(ext.Value), true
+			}
+			return value, true
+		}
+	}
+	return "", false
+}
+
+func (mapper *certMapper) identity(cert *x509.Certificate) (*certIdentity, error) {
+	ident := &certIdentity{labels: map[string]string{}}
+	if field := mapper.conf.EdgeID; field != "" {
+		value, ok := certField(cert, field)
+		if !ok {
+			return nil, apis.NewAuthError(apis.AuthInvalidCertificate, "no "+field+" in certificate")
+		}
+		if mapper.pattern != nil {
+			matches := mapper.pattern.FindStringSubmatch(value)
+			if len(matches) < 2 {
+				return nil, apis.NewAuthError(apis.AuthInvalidCertificate, field+" mismatches edge_id_pattern")
+			}
+			value = matches[1]
+		}
+		edgeID, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || edgeID == 0 {
+			return nil, apis.NewAuthError(apis.AuthInvalidCertificate, "illegal edgeID in "+field)
+		}
+		ident.edgeID, ident.hasID = edgeID, true
+	}
+	if field := mapper.conf.Meta; field != "" {
+		value, ok := certField(cert, field)
+		if !ok {
+			return nil, apis.NewAuthError(apis.AuthInvalidCertificate, "no "+field+" in certificate")
+		}
+		ident.meta, ident.hasMeta = value, true
+	}
+	for key, field := range mapper.conf.Labels {
+		if value, ok := certField(cert, field); ok {
+			ident.labels[key] = value
+		}
+	}
+	// the same as the labels claimed by edges
+	if err := label.Validate(ident.labels); err != nil {
+		return nil, apis.NewAuthError(apis.AuthInvalidCertificate, err.Error())
+	}
+	return ident, nil
+}
+
+// peerCertificate returns the verified client certificate of the conn
+func peerCertificate(conn net.Conn) (*x509.Certificate, error) {
+	if mc, ok := conn.(*cmux.MuxConn); ok {
+		conn = mc.Conn
+	}
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return nil, apis.NewAuthError(apis.AuthMissingCertificate, "not a tls connection")
+	}
+	// the handshake is usually done by cmux or not yet started
+	tlsConn.SetDeadline(time.Now().Add(handshakeTimeout))
+	err := tlsConn.Handshake()
+	tlsConn.SetDeadline(time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	certs := tlsConn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, apis.NewAuthError(apis.AuthMissingCertificate, "")
+	}
+	return certs[0], nil
+}
+
+// certDelegate checks the edgeID and meta claimed by the edge against its certificate
+type certDelegate struct {
+	*edgeDelegate
+	ident *certIdentity
+	// the edge is rejected with it while connecting
+	err error
+}
+
+func (cd *certDelegate) GetClientID(wantedID uint64, meta []byte) (uint64, error) {
+	if cd.err != nil {
+		return 0, cd.err
+	}
+	if err := cd.checkMeta(stripMeta(meta)); err != nil {
+		return 0, err
+	}
+	if !cd.ident.hasID {
+		return cd.edgeDelegate.GetClientID(wantedID, meta)
+	}
+	if wantedID != 0 && wantedID != cd.ident.edgeID {
+		return 0, cd.mismatch(wantedID)
+	}
+	// the other authenticator still applies
+	meta, err := cd.authenticate(meta)
+	if err != nil {
+		return 0, err
+	}
+	klog.V(2).Infof("edge get edgeID: %d from certificate, meta: %s", cd.ident.edgeID, string(meta))
+	return cd.ident.edgeID, nil
+}
+
+func (cd *certDelegate) ConnOnline(d delegate.ConnDescriber) error {
+	if cd.err != nil {
+		return cd.err
+	}
+	if cd.ident.hasID && d.ClientID() != cd.ident.edgeID {
+		return cd.mismatch(d.ClientID())
+	}
+	if err := cd.checkMeta(stripMeta(d.Meta())); err != nil {
+		return err
+	}
+	return cd.edgeDelegate.ConnOnline(d)
+}
+
+func (cd *certDelegate) mismatch(edgeID uint64) error {
+	klog.V(1).Infof("edge claimed edgeID: %d mismatches certificate edgeID: %d", edgeID, cd.ident.edgeID)
+	authRejected.WithLabelValues(string(apis.AuthIdentityMismatch)).Inc()
+	return apis.NewAuthError(apis.AuthIdentityMismatch, "edgeID mismatches certificate")
+}
+
+func (cd *certDelegate) checkMeta(meta []byte) error {
+	return cd.ident.checkMeta(meta)
+}
+
+// checkMeta checks the meta without the credential and labels
+func (ident *certIdentity) checkMeta(meta []byte) error {
+	if ident.hasMeta && string(meta) != ident.meta {
+		authRejected.WithLabelValues(string(apis.AuthIdentityMismatch)).Inc()
+		return apis.NewAuthError(apis.AuthIdentityMismatch, "meta mismatches certificate")
+	}
+	return nil
+}
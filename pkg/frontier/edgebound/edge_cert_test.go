@@ -0,0 +1,123 @@
+package edgebound
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	gconfig "github.com/singchia/frontier/pkg/config"
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/frontier/pkg/frontier/config"
+	"github.com/singchia/frontier/pkg/frontier/label"
+)
+
+var oidSite = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 55555, 1}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key}
+}
+
+func (ca *testCA) issue(t *testing.T, tmpl *x509.Certificate) *x509.Certificate {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tmpl.NotBefore = time.Now().Add(-time.Hour)
+	tmpl.NotAfter = time.Now().Add(time.Hour)
+	tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return cert
+}
+
+func TestCertField(t *testing.T) {
+	ca := newTestCA(t)
+	uri, _ := url.Parse("spiffe://frontier/edge/10086")
+	site, _ := asn1.Marshal("fra1")
+	cert := ca.issue(t, &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject: pkix.Name{CommonName: "10086",
+			ExtraNames: []pkix.AttributeTypeAndValue{{Type: asn1.ObjectIdentifier{2, 5, 4, 11}, Value: "sensors"}}},
+		URIs:            []*url.URL{uri},
+		ExtraExtensions: []pkix.Extension{{Id: oidSite, Value: site}},
+	})
+
+	for field, want := range map[string]string{
+		"cn":                      "10086",
+		"san_uri":                 "spiffe://frontier/edge/10086",
+		"serial":                  "42",
+		"oid:2.5.4.11":            "sensors",
+		"oid:1.3.6.1.4.1.55555.1": "fra1",
+	} {
+		if got, ok := certField(cert, field); !ok || got != want {
+			t.Fatalf("field %s got %q, want %q", field, got, want)
+		}
+	}
+	if _, ok := certField(cert, "san_dns"); ok {
+		t.Fatal("san_dns should be absent")
+	}
+
+	mapper, err := newCertMapper(&config.Edgebound{
+		Listen: gconfig.Listen{TLS: gconfig.TLS{Enable: true, MTLS: true}},
+		CertIdentity: config.CertIdentity{Enable: true, EdgeID: "san_uri", EdgeIDPattern: `/edge/(\d+)$`,
+			Labels: map[string]string{"site": "oid:1.3.6.1.4.1.55555.1"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ident, err := mapper.identity(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ident.edgeID != 10086 || ident.labels["site"] != "fra1" {
+		t.Fatalf("identity got edgeID %d, labels %v", ident.edgeID, ident.labels)
+	}
+
+	// label values from certificates are validated like the claimed ones
+	mapper, err = newCertMapper(&config.Edgebound{
+		Listen:       gconfig.Listen{TLS: gconfig.TLS{Enable: true, MTLS: true}},
+		CertIdentity: config.CertIdentity{Enable: true, Labels: map[string]string{"spiffe": "san_uri"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = mapper.identity(cert); authCode(err) != apis.AuthInvalidCertificate {
+		t.Fatalf("identity with illegal label value err: %v", err)
+	}
+	// and the keys are checked with the config
+	_, err = newCertMapper(&config.Edgebound{
+		Listen:       gconfig.Listen{TLS: gconfig.TLS{Enable: true, MTLS: true}},
+		CertIdentity: config.CertIdentity{Enable: true, Labels: map[string]string{"si te": "cn"}},
+	})
+	if !errors.Is(err, label.ErrIllegalLabel) {
+		t.Fatalf("cert mapper with illegal label key err: %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package edgebound
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/geminio"
+	"github.com/singchia/geminio/options"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// interval to check whether the edges are idle while draining
+	drainCheckInterval = 100 * time.Millisecond
+	// max time to wait for the edge to take the reconnect notice
+	reconnectTimeout = 5 * time.Second
+)
+
+// Drain stops accepting edges, and closes each online edge once its rpcs and streams are done,
+// the edges are told to reconnect to addr before closing, it returns when no edge is online or ctx is done
+func (em *edgeManager) Drain(ctx context.Context, addr string) error {
+	em.draining.Store(true)
+	klog.V(0).Infof("edge manager draining, edges: %d, addr: %s", em.CountEdges(), addr)
+
+	data, err := json.Marshal(&apis.Reconnect{Addr: addr})
+	if err != nil {
+		return err
+	}
+	// key: edgeID, edges told to reconnect and being closed
+	closing := map[uint64]struct{}{}
+	ticker := time.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		edges := em.ListEdges()
+		if len(edges) == 0 {
+			klog.V(0).Infof("edge manager drained")
+			return nil
+		}
+		for _, edge := range edges {
+			edgeID := edge.ClientID()
+			if _, ok := closing[edgeID]; ok {
+				continue
+			}
+			if !em.idle(edgeID) {
+				continue
+			}
+			closing[edgeID] = struct{}{}
+			// new rpcs, messages and streams are refused from now on, and retried by the edge after reconnected
+			undrain := em.exchange.DrainEdge(edgeID)
+			go em.reconnect(ctx, edge, data, undrain)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			klog.V(0).Infof("edge manager drain deadline exceeded, edges left: %d", len(edges))
+			return ctx.Err()
+		}
+	}
+}
+
+func (em *edgeManager) idle(edgeID uint64) bool {
+	return em.exchange.EdgeInflight(edgeID) == 0 && len(em.ListStreams(edgeID)) == 0
+}
+
+// reconnect tells the edge to reconnect and closes it once the rpcs and streams taken before draining are done,
+// the edges not serving the notice are closed as well
+func (em *edgeManager) reconnect(ctx context.Context, edge geminio.End, data []byte, undrain func()) {
+	defer undrain()
+
+	edgeID := edge.ClientID()
+	if ok, _ := em.HasEdgeRPC(edgeID, apis.RPCReconnect); ok {
+		opt := options.Call()
+		opt.SetTimeout(reconnectTimeout)
+		_, err := edge.Call(context.TODO(), apis.RPCReconnect, edge.NewRequest(data), opt)
+		if err != nil {
+			klog.V(2).Infof("edge manager drain, tell edge to reconnect err: %s, edgeID: %d", err, edgeID)
+		}
+	}
+	ticker := time.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+	for !em.idle(edgeID) {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			klog.V(2).Infof("edge manager drain deadline exceeded, close busy edgeID: %d", edgeID)
+			edge.Close()
+			return
+		}
+	}
+	edge.Close()
+}
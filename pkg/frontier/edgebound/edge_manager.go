@@ -7,6 +7,7 @@ import (
 	"net"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/jumboframes/armorigo/rproxy"
 	"github.com/jumboframes/armorigo/synchub"
@@ -25,8 +26,8 @@ import (
 )
 
 func NewEdgebound(conf *config.Configuration, repo apis.Repo, informer apis.EdgeInformer,
-	exchange apis.Exchange, tmr timer.Timer) (apis.Edgebound, error) {
-	return newEdgeManager(conf, repo, informer, exchange, tmr)
+	exchange apis.Exchange, tmr timer.Timer, opts ...EdgeboundOption) (apis.Edgebound, error) {
+	return newEdgeManager(conf, repo, informer, exchange, tmr, opts...)
 }
 
 type edgeManager struct {
@@ -35,6 +36,14 @@ type edgeManager struct {
 
 	informer apis.EdgeInformer
 	exchange apis.Exchange
+	// nil means edges are not authenticated
+	auth apis.EdgeAuthenticator
+	// nil means client certificates are not mapped to edges
+	certs *certMapper
+	// admission control before handshaking
+	adm *admission
+	// no edge is accepted while draining
+	draining atomic.Bool
 
 	// edgeID allocator
 	idFactory id.IDFactory
@@ -61,9 +70,20 @@ type edgeManager struct {
 
 // support for tls, mtls and tcp listening
 func newEdgeManager(conf *config.Configuration, repo apis.Repo, informer apis.EdgeInformer,
-	exchange apis.Exchange, tmr timer.Timer) (*edgeManager, error) {
+	exchange apis.Exchange, tmr timer.Timer, opts ...EdgeboundOption) (*edgeManager, error) {
 	listen := &conf.Edgebound.Listen
 
+	auth, err := NewEdgeAuthenticator(&conf.Edgebound.Auth)
+	if err != nil {
+		klog.Errorf("edge manager new authenticator err: %s", err)
+		return nil, err
+	}
+	certs, err := newCertMapper(&conf.Edgebound)
+	if err != nil {
+		klog.Errorf("edge manager new cert mapper err: %s", err)
+		return nil, err
+	}
+
 	em := &edgeManager{
 		conf:                  conf,
 		tmr:                   tmr,
@@ -76,6 +96,12 @@ func newEdgeManager(conf *config.Configuration, repo apis.Repo, informer apis.Ed
 		idFactory: id.DefaultIncIDCounter,
 		informer:  informer,
 		exchange:  exchange,
+		auth:      auth,
+		certs:     certs,
+		adm:       newAdmission(&conf.Edgebound.Admission),
+	}
+	for _, opt := range opts {
+		opt(em)
 	}
 	if misc.IsNil(informer) {
 		em.informer = nil
@@ -124,6 +150,14 @@ func (em *edgeManager) Serve() error {
 		go em.rp.Proxy(context.TODO())
 	}
 
+	if em.adm.backlog != nil {
+		// the backlog is closed after accepting ends, then the handshakers quit
+		defer close(em.adm.backlog)
+		for i := 0; i < em.conf.Edgebound.Admission.MaxHandshakes; i++ {
+			go em.handshakes()
+		}
+	}
+
 	for {
 		conn, err := em.geminioLn.Accept()
 		if err != nil {
@@ -133,7 +167,9 @@ func (em *edgeManager) Serve() error {
 			}
 			break
 		}
-		go em.handleConn(conn)
+		if em.admit(conn) {
+			em.dispatch(conn)
+		}
 	}
 	return nil
 }
@@ -142,7 +178,31 @@ func (em *edgeManager) handleConn(conn net.Conn) error {
 	// options for geminio End
 	opt := server.NewEndOptions()
 	opt.SetTimer(em.tmr)
-	opt.SetDelegate(em)
+	var ident *certIdentity
+	ed := &edgeDelegate{edgeManager: em}
+	if em.certs != nil {
+		cd := &certDelegate{edgeDelegate: ed}
+		cert, err := peerCertificate(conn)
+		if err == nil {
+			cd.ident, err = em.certs.identity(cert)
+		}
+		if err != nil {
+			ae, ok := apis.ParseAuthError(err)
+			if !ok {
+				klog.V(1).Infof("edge manager tls handshake err: %s, addr: %s", err, conn.RemoteAddr())
+				conn.Close()
+				return err
+			}
+			// reject while connecting, so the edge gets the reason
+			authRejected.WithLabelValues(string(ae.Code)).Inc()
+			klog.V(1).Infof("edge certificate rejected, err: %s, addr: %s", err, conn.RemoteAddr())
+			cd.err = err
+		}
+		ident = cd.ident
+		opt.SetDelegate(cd)
+	} else {
+		opt.SetDelegate(ed)
+	}
 	// stream handler
 	opt.SetAcceptStreamFunc(em.acceptStream)
 	opt.SetClosedStreamFunc(em.closedStream)
@@ -152,6 +212,16 @@ func (em *edgeManager) handleConn(conn net.Conn) error {
 		klog.Warningf("edge manager geminio server new end err: %s, addr: %s", err, conn.RemoteAddr())
 		return err
 	}
+	// the credential, labels and envelope version are not kept in the meta of edges, the labels are checked while authenticating
+	meta, envelope := apis.DecodeMetaEnvelope(end.Meta())
+	meta, _ = apis.DecodeCredential(meta)
+	meta, labels, _ := apis.DecodeLabels(meta)
+	ee := &edgeEnd{End: end, meta: meta, labels: labels, envelope: envelope, ident: ident}
+	if ident != nil && len(ident.labels) != 0 {
+		// the labels from the certificate override the claimed ones
+		ee.labels = mergeLabels(labels, ident.labels)
+	}
+	end = ee
 
 	// handle online event for end
 	if err = em.online(end); err != nil {
@@ -171,6 +241,17 @@ func (em *edgeManager) GetEdgeByID(edgeID uint64) geminio.End {
 	return em.edges[edgeID]
 }
 
+func (em *edgeManager) HasEdgeRPC(edgeID uint64, rpc string) (bool, error) {
+	_, err := em.repo.GetEdgeRPC(edgeID, rpc)
+	if err != nil {
+		if err == apis.ErrRecordNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 func (em *edgeManager) ListEdges() []geminio.End {
 	ends := []geminio.End{}
 	em.mtx.RLock()
@@ -0,0 +1,94 @@
+package edgebound
+
+import (
+	"sync"
+
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/geminio"
+	"k8s.io/klog/v2"
+)
+
+// edgeEnd hides the credential, labels and envelope version from the meta, carries the labels claimed by the edge
+// and derived from its certificate, and the meta updated by the edge after online
+type edgeEnd struct {
+	geminio.End
+	metaMtx sync.RWMutex
+	meta    []byte
+	labels  map[string]string
+	// envelope version known by the edge, 0 means the edge gets custom as it's sent
+	envelope int
+	// nil if client certificates are not mapped to edges
+	ident *certIdentity
+	// subscribed topics, to limit them
+	topicMtx sync.Mutex
+	topics   map[string]struct{}
+}
+
+func (end *edgeEnd) Meta() []byte {
+	end.metaMtx.RLock()
+	defer end.metaMtx.RUnlock()
+	return end.meta
+}
+
+func (end *edgeEnd) setMeta(meta []byte) {
+	end.metaMtx.Lock()
+	end.meta = meta
+	end.metaMtx.Unlock()
+}
+
+func (end *edgeEnd) Labels() map[string]string {
+	return end.labels
+}
+
+func (end *edgeEnd) Envelope() int {
+	return end.envelope
+}
+
+// stripMeta strips the credential, labels and envelope version claimed by the edge from meta
+func stripMeta(meta []byte) []byte {
+	meta, _ = apis.DecodeMetaEnvelope(meta)
+	meta, _ = apis.DecodeCredential(meta)
+	meta, _, _ = apis.DecodeLabels(meta)
+	return meta
+}
+
+func mergeLabels(claimed, trusted map[string]string) map[string]string {
+	labels := make(map[string]string, len(claimed)+len(trusted))
+	for key, value := range claimed {
+		labels[key] = value
+	}
+	for key, value := range trusted {
+		labels[key] = value
+	}
+	return labels
+}
+
+// UpdateEdgeMeta replaces the meta of the online edge, in the repo and the edge itself,
+// the meta is authenticated as connecting, and the labels carried are ignored
+func (em *edgeManager) UpdateEdgeMeta(edgeID uint64, meta []byte) ([]byte, error) {
+	em.mtx.RLock()
+	end, ok := em.edges[edgeID]
+	em.mtx.RUnlock()
+	if !ok {
+		return nil, apis.ErrEdgeNotOnline
+	}
+	ee, ok := end.(*edgeEnd)
+	if ok && ee.ident != nil && ee.ident.hasMeta {
+		klog.V(1).Infof("edge update meta rejected, meta is bound to certificate, edgeID: %d", edgeID)
+		authRejected.WithLabelValues(string(apis.AuthIdentityMismatch)).Inc()
+		return nil, apis.NewAuthError(apis.AuthIdentityMismatch, "meta is bound to certificate")
+	}
+	meta, err := em.authenticate(meta)
+	if err != nil {
+		return nil, err
+	}
+	if err = em.repo.UpdateEdgeMeta(edgeID, string(meta)); err != nil {
+		klog.Errorf("edge update meta, repo update err: %s, edgeID: %d", err, edgeID)
+		return nil, err
+	}
+	if ok {
+		ee.setMeta(meta)
+	}
+	klog.V(2).Infof("edge update meta, edgeID: %d, meta: %s", edgeID, string(meta))
+	return meta, nil
+}
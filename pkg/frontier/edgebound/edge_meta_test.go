@@ -0,0 +1,89 @@
+package edgebound
+
+import (
+	"testing"
+	"time"
+
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/frontier/pkg/frontier/config"
+	"github.com/singchia/frontier/pkg/frontier/repo"
+	"github.com/singchia/frontier/pkg/frontier/repo/model"
+	"github.com/singchia/geminio"
+)
+
+func TestUpdateEdgeMeta(t *testing.T) {
+	secret := []byte("secret")
+	conf := &config.Configuration{}
+	repo, err := repo.NewRepo(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+
+	const edgeID, boundID = 1001, 1002
+	for _, id := range []uint64{edgeID, boundID} {
+		if err := repo.CreateEdge(&model.Edge{EdgeID: id, Meta: "device-1", Addr: "127.0.0.1:1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ee := &edgeEnd{meta: []byte("device-1")}
+	em := &edgeManager{
+		repo: repo,
+		auth: &hmacAuthenticator{secret: secret, maxSkew: time.Minute, now: time.Now},
+		edges: map[uint64]geminio.End{
+			edgeID: ee,
+			// the meta is derived from the certificate
+			boundID: &edgeEnd{meta: []byte("device-1"), ident: &certIdentity{meta: "device-1", hasMeta: true}},
+		},
+	}
+
+	signed := func(meta []byte) []byte {
+		return apis.EncodeCredential(meta, apis.SignMeta(secret, meta, time.Now()))
+	}
+	nested := apis.EncodeEnvelope(apis.EncodeCredential([]byte("device-2"), "inner"), apis.Headers{apis.HeaderCredential: "outer"})
+	rejected := []struct {
+		name   string
+		edgeID uint64
+		meta   []byte
+		code   apis.AuthCode
+	}{
+		{"unsigned", edgeID, []byte("device-2"), apis.AuthMissingCredential},
+		{"wrong secret", edgeID, apis.EncodeCredential([]byte("device-2"), apis.SignMeta([]byte("wrong"), []byte("device-2"), time.Now())), apis.AuthInvalidSignature},
+		{"signature of other meta", edgeID, apis.EncodeCredential([]byte("device-2"), apis.SignMeta(secret, []byte("device-1"), time.Now())), apis.AuthInvalidSignature},
+		{"stale signature", edgeID, apis.EncodeCredential([]byte("device-2"), apis.SignMeta(secret, []byte("device-2"), time.Now().Add(-time.Hour))), apis.AuthExpired},
+		{"nested credential", edgeID, nested, apis.AuthInvalidCredential},
+		{"bound to certificate", boundID, signed([]byte("device-2")), apis.AuthIdentityMismatch},
+		{"same as certificate", boundID, signed([]byte("device-1")), apis.AuthIdentityMismatch},
+	}
+	for _, c := range rejected {
+		_, err := em.UpdateEdgeMeta(c.edgeID, c.meta)
+		if got := authCode(err); got != c.code {
+			t.Errorf("%s got code %q, want %q, err: %v", c.name, got, c.code, err)
+		}
+	}
+	for _, id := range []uint64{edgeID, boundID} {
+		me, err := repo.GetEdge(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if me.Meta != "device-1" {
+			t.Fatalf("rejected meta persisted: %q", me.Meta)
+		}
+	}
+
+	// the credential and labels are stripped before persisted
+	meta, err := em.UpdateEdgeMeta(edgeID, signed(apis.EncodeLabels([]byte("device-2"), map[string]string{"site": "ams1"})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(meta) != "device-2" || string(ee.Meta()) != "device-2" {
+		t.Fatalf("unexpected meta: %q, edge meta: %q", meta, ee.Meta())
+	}
+	me, err := repo.GetEdge(edgeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if me.Meta != "device-2" {
+		t.Fatalf("unexpected persisted meta: %q", me.Meta)
+	}
+}
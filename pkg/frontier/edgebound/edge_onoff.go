@@ -1,6 +1,7 @@
 package edgebound
 
 import (
+	"bytes"
 	"errors"
 	"net"
 	"strconv"
@@ -65,11 +66,29 @@ func (em *edgeManager) online(end geminio.End) error {
 		klog.Errorf("edge online, repo create err: %s, edgeID: %d", err, end.ClientID())
 		return err
 	}
+	if ee, ok := end.(*edgeEnd); ok {
+		for key, value := range ee.Labels() {
+			label := &model.EdgeLabel{
+				EdgeID:     end.ClientID(),
+				Key:        key,
+				Value:      value,
+				CreateTime: edge.CreateTime,
+			}
+			if err := em.repo.CreateEdgeLabel(label); err != nil {
+				klog.Errorf("edge online, repo create label err: %s, edgeID: %d, key: %s", err, end.ClientID(), key)
+				return err
+			}
+		}
+	}
 
 	// inform others
 	if em.informer != nil {
 		em.informer.EdgeOnline(end.ClientID(), end.Meta(), end.RemoteAddr())
 	}
+	// deliver messages queued while offline
+	if em.exchange != nil {
+		go em.exchange.FlushOutbox(end)
+	}
 
 	return nil
 }
@@ -84,6 +103,8 @@ func (em *edgeManager) offline(edgeID uint64, meta []byte, addr net.Addr) error
 		end := value.(geminio.End)
 		if end.RemoteAddr().String() == addr.String() {
 			legacy = true
+			// the meta may be updated after online
+			meta = end.Meta()
 			delete(em.edges, edgeID)
 			klog.V(2).Infof("edge offline, edgeID: %d, remote addr: %s", edgeID, end.RemoteAddr().String())
 		} else {
@@ -120,6 +141,14 @@ func (em *edgeManager) offline(edgeID uint64, meta []byte, addr net.Addr) error
 		klog.Errorf("edge offline, repo delete edge rpcs err: %s, edgeID: %d", err, edgeID)
 		return err
 	}
+	if err := em.repo.DeleteEdgeTopics(edgeID); err != nil {
+		klog.Errorf("edge offline, repo delete edge topics err: %s, edgeID: %d", err, edgeID)
+		return err
+	}
+	if err := em.repo.DeleteEdgeLabels(edgeID); err != nil {
+		klog.Errorf("edge offline, repo delete edge labels err: %s, edgeID: %d", err, edgeID)
+		return err
+	}
 
 	// inform others
 	if em.informer != nil {
@@ -132,10 +161,47 @@ func (em *edgeManager) offline(edgeID uint64, meta []byte, addr net.Addr) error
 	return nil
 }
 
-// delegations for all ends from edgebound, called by geminio
-func (em *edgeManager) ConnOnline(d delegate.ConnDescriber) error {
+// edgeDelegate is the delegate of an edge connection, the edge is authenticated once
+// by GetClientID or ConnOnline, whichever comes first
+type edgeDelegate struct {
+	*edgeManager
+	// the meta authenticated and the meta stripped from it
+	authed, meta []byte
+}
+
+func (ed *edgeDelegate) authenticate(meta []byte) ([]byte, error) {
+	if ed.authed != nil && bytes.Equal(ed.authed, meta) {
+		return ed.meta, nil
+	}
+	stripped, err := ed.edgeManager.authenticate(meta)
+	if err != nil {
+		return stripped, err
+	}
+	ed.authed, ed.meta = meta, stripped
+	return stripped, nil
+}
+
+func (ed *edgeDelegate) GetClientID(_ uint64, meta []byte) (uint64, error) {
+	// authenticate before the edgeID is acquired or allocated
+	meta, err := ed.authenticate(meta)
+	if err != nil {
+		return 0, err
+	}
+	return ed.getClientID(meta)
+}
+
+func (ed *edgeDelegate) ConnOnline(d delegate.ConnDescriber) error {
+	// in case the client doesn't acquire the edgeID through GetClientID
+	meta, err := ed.authenticate(d.Meta())
+	if err != nil {
+		return err
+	}
+	return ed.connOnline(d, meta)
+}
+
+// connOnline tells the services the edge with the authenticated meta is online
+func (em *edgeManager) connOnline(d delegate.ConnDescriber, meta []byte) error {
 	edgeID := d.ClientID()
-	meta := d.Meta()
 	addr := d.RemoteAddr()
 
 	// exchange to service
@@ -149,9 +215,10 @@ func (em *edgeManager) ConnOnline(d delegate.ConnDescriber) error {
 	return nil
 }
 
+// delegations for all ends from edgebound, called by geminio
 func (em *edgeManager) ConnOffline(d delegate.ConnDescriber) error {
 	edgeID := d.ClientID()
-	meta := d.Meta()
+	meta := stripMeta(d.Meta())
 	addr := d.RemoteAddr()
 
 	klog.V(2).Infof("edge offline, edgeID: %d, meta: %s, addr: %s", edgeID, string(meta), addr)
@@ -167,11 +234,11 @@ func (em *edgeManager) ConnOffline(d delegate.ConnDescriber) error {
 
 func (em *edgeManager) Heartbeat(d delegate.ConnDescriber) error {
 	edgeID := d.ClientID()
-	meta := string(d.Meta())
+	meta := stripMeta(d.Meta())
 	addr := d.RemoteAddr()
 	klog.V(3).Infof("edge heartbeat, edgeID: %d, meta: %s, addr: %s", edgeID, string(meta), addr)
 	if em.informer != nil {
-		em.informer.EdgeHeartbeat(edgeID, d.Meta(), addr)
+		em.informer.EdgeHeartbeat(edgeID, meta, addr)
 	}
 	return nil
 }
@@ -191,7 +258,8 @@ func (em *edgeManager) RemoteRegistration(rpc string, edgeID, streamID uint64) {
 	}
 }
 
-func (em *edgeManager) GetClientID(_ uint64, meta []byte) (uint64, error) {
+// getClientID acquires or allocates the edgeID of the authenticated meta
+func (em *edgeManager) getClientID(meta []byte) (uint64, error) {
 	var (
 		edgeID uint64
 		err    error
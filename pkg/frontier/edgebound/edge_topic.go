@@ -0,0 +1,120 @@
+package edgebound
+
+import (
+	"time"
+
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/frontier/pkg/frontier/repo/model"
+	"k8s.io/klog/v2"
+)
+
+const (
+	defaultMaxTopics      = 1024
+	defaultMaxTopicLength = 256
+)
+
+func (em *edgeManager) maxTopics() int {
+	if em.conf.Edgebound.Subscription.MaxTopics > 0 {
+		return em.conf.Edgebound.Subscription.MaxTopics
+	}
+	return defaultMaxTopics
+}
+
+func (em *edgeManager) maxTopicLength() int {
+	if em.conf.Edgebound.Subscription.MaxTopicLength > 0 {
+		return em.conf.Edgebound.Subscription.MaxTopicLength
+	}
+	return defaultMaxTopicLength
+}
+
+func (em *edgeManager) getEdgeEnd(edgeID uint64) (*edgeEnd, error) {
+	em.mtx.RLock()
+	end, ok := em.edges[edgeID]
+	em.mtx.RUnlock()
+	if !ok {
+		return nil, apis.ErrEdgeNotOnline
+	}
+	ee, ok := end.(*edgeEnd)
+	if !ok {
+		return nil, apis.ErrEdgeNotOnline
+	}
+	return ee, nil
+}
+
+func (em *edgeManager) SubscribeTopics(edgeID uint64, topics []string) error {
+	for _, topic := range topics {
+		if len(topic) > em.maxTopicLength() {
+			klog.V(2).Infof("edge subscribe, topic too long, edgeID: %d, length: %d", edgeID, len(topic))
+			return apis.ErrTopicTooLong
+		}
+	}
+	ee, err := em.getEdgeEnd(edgeID)
+	if err != nil {
+		return err
+	}
+	ee.topicMtx.Lock()
+	defer ee.topicMtx.Unlock()
+	if ee.topics == nil {
+		ee.topics = map[string]struct{}{}
+	}
+	added := map[string]struct{}{}
+	for _, topic := range topics {
+		if _, ok := ee.topics[topic]; !ok {
+			added[topic] = struct{}{}
+		}
+	}
+	if len(ee.topics)+len(added) > em.maxTopics() {
+		klog.V(2).Infof("edge subscribe, too many topics, edgeID: %d, subscribed: %d, adding: %d", edgeID, len(ee.topics), len(added))
+		return apis.ErrTooManyTopics
+	}
+
+	for _, topic := range topics {
+		et := &model.EdgeTopic{
+			Topic:      topic,
+			EdgeID:     edgeID,
+			CreateTime: time.Now().Unix(),
+		}
+		if err := em.repo.CreateEdgeTopic(et); err != nil {
+			klog.Errorf("edge subscribe, create edge topic err: %s, edgeID: %d, topic: %s", err, edgeID, topic)
+			return err
+		}
+		ee.topics[topic] = struct{}{}
+	}
+	klog.V(2).Infof("edge subscribe, edgeID: %d, topics: %v", edgeID, topics)
+	return nil
+}
+
+func (em *edgeManager) UnsubscribeTopics(edgeID uint64, topics []string) error {
+	ee, err := em.getEdgeEnd(edgeID)
+	if err != nil {
+		return err
+	}
+	ee.topicMtx.Lock()
+	defer ee.topicMtx.Unlock()
+
+	for _, topic := range topics {
+		if err := em.repo.DeleteEdgeTopic(edgeID, topic); err != nil {
+			klog.Errorf("edge unsubscribe, delete edge topic err: %s, edgeID: %d, topic: %s", err, edgeID, topic)
+			return err
+		}
+		delete(ee.topics, topic)
+	}
+	klog.V(2).Infof("edge unsubscribe, edgeID: %d, topics: %v", edgeID, topics)
+	return nil
+}
+
+// GetEdgeIDsByTopic returns the edges subscribed to the topic, empty if none
+func (em *edgeManager) GetEdgeIDsByTopic(topic string) ([]uint64, error) {
+	ets, err := em.repo.GetEdgeTopics(topic)
+	if err != nil {
+		if err == apis.ErrRecordNotFound {
+			return []uint64{}, nil
+		}
+		return nil, err
+	}
+	edgeIDs := make([]uint64, 0, len(ets))
+	for _, et := range ets {
+		edgeIDs = append(edgeIDs, et.EdgeID)
+	}
+	return edgeIDs, nil
+}
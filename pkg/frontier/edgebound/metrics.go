@@ -0,0 +1,28 @@
+package edgebound
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var authRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "frontier",
+	Subsystem: "edgebound",
+	Name:      "auth_rejected_total",
+	Help:      "Edges rejected by the authenticator.",
+}, []string{"code"})
+
+var admissionRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "frontier",
+	Subsystem: "edgebound",
+	Name:      "admission_rejected_total",
+	Help:      "Connections closed by admission control before handshaking.",
+}, []string{"reason"})
+
+var handshaking = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: "frontier",
+	Subsystem: "edgebound",
+	Name:      "handshakes",
+	Help:      "Connections admitted and not online yet, including the backlog.",
+})
+
+func init() {
+	prometheus.MustRegister(authRejected, admissionRejected, handshaking)
+}
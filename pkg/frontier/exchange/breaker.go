@@ -0,0 +1,187 @@
+package exchange
+
+import (
+	"sync"
+	"time"
+
+	"github.com/singchia/frontier/pkg/frontier/config"
+	"github.com/singchia/geminio"
+	"k8s.io/klog/v2"
+)
+
+const (
+	defaultBreakerWindow      = 10 * time.Second
+	defaultBreakerMinRequests = 20
+	defaultBreakerErrorRate   = 50
+	defaultBreakerCooldown    = 5 * time.Second
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// breaker of one service instance, it opens when the failed calls in the window exceed the error rate,
+// and after the cooldown lets one call probe, the instance is closed again if the probe succeeds
+type breaker struct {
+	mtx      sync.Mutex
+	state    breakerState
+	start    time.Time // of the window
+	requests int
+	failures int
+	// when opened or the last probe was let through
+	since time.Time
+}
+
+// breakers of all service instances, it implements apis.Breaker
+type breakers struct {
+	window      time.Duration
+	minRequests int
+	errorRate   int
+	slowCall    time.Duration
+	cooldown    time.Duration
+	// key: serviceID; value: *breaker
+	instances sync.Map
+}
+
+func newBreakers(conf *config.Breaker) *breakers {
+	bs := &breakers{
+		window:      time.Duration(conf.Window) * time.Second,
+		minRequests: conf.MinRequests,
+		errorRate:   conf.ErrorRate,
+		slowCall:    time.Duration(conf.SlowCall) * time.Millisecond,
+		cooldown:    time.Duration(conf.Cooldown) * time.Second,
+	}
+	if bs.window <= 0 {
+		bs.window = defaultBreakerWindow
+	}
+	if bs.minRequests <= 0 {
+		bs.minRequests = defaultBreakerMinRequests
+	}
+	if bs.errorRate <= 0 {
+		bs.errorRate = defaultBreakerErrorRate
+	}
+	if bs.cooldown <= 0 {
+		bs.cooldown = defaultBreakerCooldown
+	}
+	return bs
+}
+
+func (bs *breakers) get(serviceID uint64) *breaker {
+	value, ok := bs.instances.Load(serviceID)
+	if !ok {
+		value, _ = bs.instances.LoadOrStore(serviceID, &breaker{start: time.Now()})
+	}
+	return value.(*breaker)
+}
+
+// Ready returns false if the instance is tripped and the cooldown isn't over
+func (bs *breakers) Ready(serviceID uint64) bool {
+	b := bs.get(serviceID)
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	switch b.state {
+	case breakerOpen, breakerHalfOpen:
+		return time.Since(b.since) >= bs.cooldown
+	}
+	return true
+}
+
+// Allow returns false if the instance is tripped, a half-open instance is allowed once per cooldown to probe,
+// call it only for the picked instance and then Record
+func (bs *breakers) Allow(serviceID uint64) bool {
+	b := bs.get(serviceID)
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	switch b.state {
+	case breakerOpen, breakerHalfOpen:
+		if time.Since(b.since) < bs.cooldown {
+			return false
+		}
+		klog.V(2).Infof("breaker half open, serviceID: %d", serviceID)
+		b.state = breakerHalfOpen
+		b.since = time.Now()
+	}
+	return true
+}
+
+// Record the result of a call, only failures of the instance and slow calls are counted,
+// errors returned by the service itself aren't
+func (bs *breakers) Record(serviceID uint64, err error, latency time.Duration) {
+	failed := (err != nil && failoverable(err)) || (bs.slowCall > 0 && latency > bs.slowCall)
+	b := bs.get(serviceID)
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	now := time.Now()
+	switch b.state {
+	case breakerOpen:
+		// late results of calls before opened
+		return
+	case breakerHalfOpen:
+		if failed {
+			klog.V(1).Infof("breaker probe failed, serviceID: %d, err: %v, latency: %s", serviceID, err, latency)
+			b.state = breakerOpen
+			b.since = now
+			breakerTripped.Inc()
+			return
+		}
+		klog.V(1).Infof("breaker closed, serviceID: %d", serviceID)
+		b.state = breakerClosed
+		b.start, b.requests, b.failures = now, 0, 0
+		return
+	}
+	if now.Sub(b.start) > bs.window {
+		b.start, b.requests, b.failures = now, 0, 0
+	}
+	b.requests++
+	if failed {
+		b.failures++
+	}
+	if b.requests >= bs.minRequests && b.failures*100 >= bs.errorRate*b.requests {
+		klog.V(1).Infof("breaker opened, serviceID: %d, requests: %d, failures: %d", serviceID, b.requests, b.failures)
+		b.state = breakerOpen
+		b.since = now
+		breakerTripped.Inc()
+	}
+}
+
+// ready returns the instances not tripped, the states are not changed
+func (bs *breakers) ready(svcs []geminio.End) []geminio.End {
+	if bs == nil {
+		return svcs
+	}
+	ret := make([]geminio.End, 0, len(svcs))
+	for _, svc := range svcs {
+		if bs.Ready(svc.ClientID()) {
+			ret = append(ret, svc)
+		}
+	}
+	return ret
+}
+
+func (bs *breakers) allow(serviceID uint64) bool {
+	if bs == nil {
+		return true
+	}
+	return bs.Allow(serviceID)
+}
+
+func (bs *breakers) record(serviceID uint64, err error, latency time.Duration) {
+	if bs == nil {
+		return
+	}
+	bs.Record(serviceID, err, latency)
+}
+
+func (bs *breakers) del(serviceID uint64) {
+	if bs == nil {
+		return
+	}
+	bs.instances.Delete(serviceID)
+}
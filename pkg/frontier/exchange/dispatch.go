@@ -0,0 +1,165 @@
+package exchange
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultEdgeInflight  = 32
+	defaultTopicInflight = 128
+	defaultBacklog       = 1024
+	// a lane without tasks and in-flights for this long exits
+	laneIdle = time.Second
+)
+
+// lane runs tasks of one key in order
+type lane struct {
+	tasks chan func(*lane)
+	// in-flight slots for tasks which finish asynchronously
+	inflight chan struct{}
+}
+
+func (l *lane) acquire() {
+	l.inflight <- struct{}{}
+}
+
+func (l *lane) release() {
+	<-l.inflight
+}
+
+// dispatcher dispatches tasks to lanes by key, tasks with the same key run in order,
+// and a slow key never blocks the others
+type dispatcher[K comparable] struct {
+	backlog  int
+	inflight int
+
+	mtx    sync.Mutex
+	lanes  map[K]*lane
+	closed bool
+}
+
+func newDispatcher[K comparable](backlog, inflight int) *dispatcher[K] {
+	return &dispatcher[K]{
+		backlog:  backlog,
+		inflight: inflight,
+		lanes:    map[K]*lane{},
+	}
+}
+
+// dispatch returns false if the lane's backlog is full or the dispatcher is closed
+func (d *dispatcher[K]) dispatch(key K, task func(*lane)) bool {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if d.closed {
+		return false
+	}
+	l, ok := d.lanes[key]
+	if !ok {
+		l = &lane{
+			tasks:    make(chan func(*lane), d.backlog),
+			inflight: make(chan struct{}, d.inflight),
+		}
+		d.lanes[key] = l
+		go d.run(key, l)
+	}
+	select {
+	case l.tasks <- task:
+		return true
+	default:
+		return false
+	}
+}
+
+func (d *dispatcher[K]) run(key K, l *lane) {
+	idle := time.NewTimer(laneIdle)
+	defer idle.Stop()
+
+	for {
+		select {
+		case task, ok := <-l.tasks:
+			if !ok {
+				return
+			}
+			task(l)
+			idle.Reset(laneIdle)
+		case <-idle.C:
+			d.mtx.Lock()
+			if len(l.tasks) == 0 && len(l.inflight) == 0 {
+				delete(d.lanes, key)
+				d.mtx.Unlock()
+				return
+			}
+			d.mtx.Unlock()
+			idle.Reset(laneIdle)
+		}
+	}
+}
+
+// close lets lanes finish the queued tasks and exit
+func (d *dispatcher[K]) close() {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	d.closed = true
+	for key, l := range d.lanes {
+		close(l.tasks)
+		delete(d.lanes, key)
+	}
+}
+
+func (ex *exchange) backlog() int {
+	if ex.conf.Exchange.Forward.Backlog > 0 {
+		return ex.conf.Exchange.Forward.Backlog
+	}
+	return defaultBacklog
+}
+
+func (ex *exchange) edgeInflight() int {
+	if ex.conf.Exchange.Forward.EdgeInflight > 0 {
+		return ex.conf.Exchange.Forward.EdgeInflight
+	}
+	return defaultEdgeInflight
+}
+
+// topicSlots are the in-flight slots shared by all edges producing to the topic,
+// topics are named by edges, so the slots are kept only while producing
+type topicSlots struct {
+	inflight chan struct{}
+	// producings holding or waiting for a slot
+	refs int
+}
+
+func (ex *exchange) topicInflight() int {
+	if ex.conf.Exchange.Forward.TopicInflight > 0 {
+		return ex.conf.Exchange.Forward.TopicInflight
+	}
+	return defaultTopicInflight
+}
+
+// acquireTopic blocks until an in-flight slot of the topic is free
+func (ex *exchange) acquireTopic(topic string) *topicSlots {
+	ex.topicMtx.Lock()
+	slots, ok := ex.topics[topic]
+	if !ok {
+		slots = &topicSlots{inflight: make(chan struct{}, ex.topicInflight())}
+		ex.topics[topic] = slots
+	}
+	slots.refs++
+	ex.topicMtx.Unlock()
+
+	slots.inflight <- struct{}{}
+	return slots
+}
+
+func (ex *exchange) releaseTopic(topic string, slots *topicSlots) {
+	<-slots.inflight
+
+	ex.topicMtx.Lock()
+	slots.refs--
+	if slots.refs == 0 {
+		delete(ex.topics, topic)
+	}
+	ex.topicMtx.Unlock()
+}
@@ -0,0 +1,49 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/geminio"
+	"k8s.io/klog/v2"
+)
+
+// edgeRPCRegistered returns false only if the repo tells the rpc isn't registered by the edge,
+// rpcs are forwarded as before if the repo can't tell
+func (ex *exchange) edgeRPCRegistered(edgeID uint64, method string) bool {
+	ok, err := ex.Edgebound.HasEdgeRPC(edgeID, method)
+	if err != nil {
+		klog.V(4).Infof("edge rpc registered, lookup err: %s, edgeID: %d, method: %s", err, edgeID, method)
+		return true
+	}
+	return ok
+}
+
+// edgeSupports tells service whether the edge registered the method
+func (ex *exchange) edgeSupports(_ context.Context, serviceID uint64, r1 geminio.Request, r2 geminio.Response) {
+	es := &apis.EdgeSupports{}
+	err := json.Unmarshal(r1.Data(), es)
+	if err != nil {
+		klog.Errorf("service edge supports, json unmarshal err: %s, serviceID: %d", err, serviceID)
+		r2.SetError(err)
+		return
+	}
+	if ex.Edgebound.GetEdgeByID(es.EdgeID) == nil {
+		r2.SetError(apis.ErrEdgeNotOnline)
+		return
+	}
+	ok, err := ex.Edgebound.HasEdgeRPC(es.EdgeID, es.Method)
+	if err != nil {
+		klog.V(2).Infof("service edge supports, serviceID: %d, edgeID: %d, method: %s, lookup err: %s", serviceID, es.EdgeID, es.Method, err)
+		r2.SetError(err)
+		return
+	}
+	data, err := json.Marshal(&apis.EdgeSupportsResult{Supported: ok})
+	if err != nil {
+		klog.Errorf("service edge supports, json marshal err: %s, serviceID: %d", err, serviceID)
+		r2.SetError(err)
+		return
+	}
+	r2.SetData(data)
+}
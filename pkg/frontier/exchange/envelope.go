@@ -0,0 +1,86 @@
+package exchange
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"strconv"
+
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/geminio"
+)
+
+// stamp sets headers owned by frontier
+func (ex *exchange) stamp(ctx context.Context, headers apis.Headers) {
+	if ex.conf.Daemon.FrontierID != "" {
+		headers[apis.HeaderFrontierID] = ex.conf.Daemon.FrontierID
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		headers[apis.HeaderDeadline] = strconv.FormatInt(deadline.UnixMilli(), 10)
+	}
+}
+
+// serviceCustom carries the edgeID and headers to the service,
+// the service which doesn't know envelopes gets the old edgeID tail
+func (ex *exchange) serviceCustom(ctx context.Context, svc geminio.End, custom []byte, edgeID uint64, headers apis.Headers) []byte {
+	if envelope(svc) < 1 {
+		tail := make([]byte, 8)
+		binary.BigEndian.PutUint64(tail, edgeID)
+		return append(custom[:len(custom):len(custom)], tail...)
+	}
+	out := apis.Headers{}
+	for key, value := range headers {
+		out[key] = value
+	}
+	out.SetUint64(apis.HeaderEdgeID, edgeID)
+	ex.stamp(ctx, out)
+	return apis.EncodeEnvelope(custom, out)
+}
+
+// edgeCustom carries headers to the edge, the envelope is left out if nothing but the edgeID,
+// so edges don't know envelopes get the same custom as before
+func (ex *exchange) edgeCustom(ctx context.Context, custom []byte, headers apis.Headers) []byte {
+	delete(headers, apis.HeaderEdgeID)
+	if len(headers) == 0 {
+		return custom
+	}
+	ex.stamp(ctx, headers)
+	return apis.EncodeEnvelope(custom, headers)
+}
+
+// peerCustom carries the source edgeID and headers to the peer edge,
+// the peer edge which doesn't know envelopes gets the same custom as before
+func (ex *exchange) peerCustom(ctx context.Context, peer geminio.End, custom []byte, srcEdgeID uint64) []byte {
+	if edgeEnvelope(peer) < 1 {
+		return custom
+	}
+	custom, headers, ok := apis.DecodeEnvelope(custom)
+	if !ok {
+		headers = apis.Headers{}
+	}
+	// the source edge is set by frontier only
+	headers.SetUint64(apis.HeaderEdgeID, srcEdgeID)
+	ex.stamp(ctx, headers)
+	return apis.EncodeEnvelope(custom, headers)
+}
+
+// edges from edgebound carry the envelope version they know
+type enveloped interface {
+	Envelope() int
+}
+
+func edgeEnvelope(edge geminio.End) int {
+	if ee, ok := edge.(enveloped); ok {
+		return ee.Envelope()
+	}
+	return 0
+}
+
+// envelope returns the envelope version known by the service
+func envelope(svc geminio.End) int {
+	meta := &apis.Meta{}
+	if err := json.Unmarshal(svc.Meta(), meta); err != nil {
+		return 0
+	}
+	return meta.Envelope
+}
@@ -1,6 +1,9 @@
 package exchange
 
 import (
+	"sync"
+	"time"
+
 	"github.com/singchia/frontier/pkg/frontier/apis"
 	"github.com/singchia/frontier/pkg/frontier/config"
 )
@@ -11,18 +14,67 @@ type exchange struct {
 	Edgebound    apis.Edgebound
 	Servicebound apis.Servicebound
 	MQM          apis.MQM
+
+	// nil if outbox is disabled
+	outbox *outbox
+	// nil if breaker is disabled
+	breakers *breakers
+	// in-flight producings of topics, dropped once the topic is idle
+	topics   map[string]*topicSlots
+	topicMtx sync.Mutex
+	// key: edgeID or serviceID; value: *rawConn
+	edgeRaws    sync.Map
+	serviceRaws sync.Map
+	// key: edgeID, serviceID or topic; value: *limiter
+	edgeLimits    sync.Map
+	serviceLimits sync.Map
+	topicLimits   sync.Map
+	// closed to stop sweeping topicLimits
+	done chan struct{}
+	// key: edgeID; value: apis.RateLimit set by the control plane
+	edgeOverrides sync.Map
+	// key: edgePair; value: time.Time the allowed edge to edge expires at
+	edgeToEdges sync.Map
+	// 0 means edge to edge is asked every time
+	edgeToEdgeTTL time.Duration
+	// key: edgeID; value: count of in-flight rpcs and messages, for draining
+	inflights map[uint64]int
+	// key: edgeID; edges refusing new rpcs, messages and streams before closed
+	drainings   map[uint64]struct{}
+	inflightMtx sync.Mutex
 }
 
-func NewExchange(conf *config.Configuration, mqm apis.MQM) apis.Exchange {
+func NewExchange(conf *config.Configuration, mqm apis.MQM) (apis.Exchange, error) {
 	return newExchange(conf, mqm)
 }
 
-func newExchange(conf *config.Configuration, mqm apis.MQM) *exchange {
+func newExchange(conf *config.Configuration, mqm apis.MQM) (*exchange, error) {
 	exchange := &exchange{
-		conf: conf,
-		MQM:  mqm,
+		conf:      conf,
+		MQM:       mqm,
+		inflights: map[uint64]int{},
+		drainings: map[uint64]struct{}{},
+		topics:    map[string]*topicSlots{},
+		done:      make(chan struct{}),
+	}
+	if conf.Exchange.Outbox.Enable {
+		outbox, err := newOutbox(&conf.Exchange.Outbox)
+		if err != nil {
+			return nil, err
+		}
+		exchange.outbox = outbox
+	}
+	if conf.Exchange.Breaker.Enable {
+		exchange.breakers = newBreakers(&conf.Exchange.Breaker)
+	}
+	if limit := conf.Exchange.RateLimit.Topic; limit.Messages > 0 || limit.Bytes > 0 {
+		go exchange.sweepTopicLimits(exchange.done)
 	}
-	return exchange
+	if ttl := conf.Exchange.EdgeToEdgeCacheTTL; ttl > 0 {
+		exchange.edgeToEdgeTTL = time.Duration(ttl) * time.Millisecond
+		go exchange.sweepEdgeToEdges(exchange.done)
+	}
+	return exchange, nil
 }
 
 func (ex *exchange) AddEdgebound(edgebound apis.Edgebound) {
@@ -32,3 +84,11 @@ func (ex *exchange) AddEdgebound(edgebound apis.Edgebound) {
 func (ex *exchange) AddServicebound(servicebound apis.Servicebound) {
 	ex.Servicebound = servicebound
 }
+
+func (ex *exchange) Close() error {
+	close(ex.done)
+	if ex.outbox != nil {
+		return ex.outbox.close()
+	}
+	return nil
+}
@@ -2,9 +2,26 @@ package exchange
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"flag"
+	"fmt"
 	"io"
+	"math/big"
 	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -12,12 +29,17 @@ import (
 	"github.com/singchia/frontier/api/dataplane/v1/edge"
 	"github.com/singchia/frontier/api/dataplane/v1/service"
 	gconfig "github.com/singchia/frontier/pkg/config"
+	"github.com/singchia/frontier/pkg/frontier/apis"
 	"github.com/singchia/frontier/pkg/frontier/config"
 	"github.com/singchia/frontier/pkg/frontier/edgebound"
+	"github.com/singchia/frontier/pkg/frontier/label"
 	"github.com/singchia/frontier/pkg/frontier/mq"
 	"github.com/singchia/frontier/pkg/frontier/repo"
+	"github.com/singchia/frontier/pkg/frontier/repo/query"
 	"github.com/singchia/frontier/pkg/frontier/servicebound"
 	"github.com/singchia/geminio"
+	"github.com/singchia/geminio/client"
+	"github.com/singchia/geminio/options"
 	"github.com/singchia/go-timer/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -42,14 +64,15 @@ const (
 
 // exchangeHarness starts an in-process exchange + edgebound + servicebound.
 type exchangeHarness struct {
+	ex  apis.Exchange
 	eb  interface{ Close() error }
-	sb  interface{ Close() error }
+	sb  apis.Servicebound
 	r   interface{ Close() error }
 	mqm interface{ Close() error }
 	tmr timer.Timer
 }
 
-func newHarness(t *testing.T) *exchangeHarness {
+func newHarness(t *testing.T, opts ...func(*config.Configuration)) *exchangeHarness {
 	t.Helper()
 	conf := &config.Configuration{
 		Edgebound: config.Edgebound{
@@ -60,6 +83,9 @@ func newHarness(t *testing.T) *exchangeHarness {
 			Listen: gconfig.Listen{Network: testNetwork, Addr: serviceboundAddr},
 		},
 	}
+	for _, opt := range opts {
+		opt(conf)
+	}
 	r, err := repo.NewRepo(conf)
 	require.NoError(t, err)
 
@@ -67,7 +93,8 @@ func newHarness(t *testing.T) *exchangeHarness {
 	require.NoError(t, err)
 
 	tmr := timer.NewTimer()
-	ex := NewExchange(conf, mqm)
+	ex, err := NewExchange(conf, mqm)
+	require.NoError(t, err)
 
 	sb, err := servicebound.NewServicebound(conf, r, nil, ex, mqm, tmr)
 	require.NoError(t, err)
@@ -79,7 +106,7 @@ func newHarness(t *testing.T) *exchangeHarness {
 	go eb.Serve()
 	time.Sleep(30 * time.Millisecond)
 
-	h := &exchangeHarness{eb: eb, sb: sb, r: r, mqm: mqm, tmr: tmr}
+	h := &exchangeHarness{ex: ex, eb: eb, sb: sb, r: r, mqm: mqm, tmr: tmr}
 	t.Cleanup(func() {
 		eb.Close()
 		sb.Close()
@@ -318,3 +345,1988 @@ func TestExchangeEdgeOnlineOffline(t *testing.T) {
 		t.Fatal("timed out waiting for EdgeOffline event")
 	}
 }
+
+// UNIT-EXCH-008: Message from Service multicast to listed Edges and broadcast by meta
+func TestExchangeMulticastToEdges(t *testing.T) {
+	newHarness(t)
+
+	received := make(chan uint64, 8)
+	edges := []edge.Edge{}
+	for _, meta := range []string{"fw-1.0", "fw-1.0", "fw-2.0"} {
+		e, err := edge.NewEdge(edgeDial(), edge.OptionEdgeMeta([]byte(meta)))
+		require.NoError(t, err)
+		defer e.Close()
+		edges = append(edges, e)
+		go func() {
+			for {
+				msg, err := e.Receive(context.TODO())
+				if err != nil {
+					return
+				}
+				received <- e.EdgeID()
+				msg.Done()
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("multicaster"))
+	require.NoError(t, err)
+	defer svc.Close()
+
+	// multicast with an offline edge
+	const offline = uint64(1<<63 + 1)
+	msg := svc.NewMessage([]byte("config"))
+	deliveries, err := svc.Multicast(context.TODO(), []uint64{edges[0].EdgeID(), edges[2].EdgeID(), offline}, msg)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 3)
+	for _, delivery := range deliveries {
+		if delivery.EdgeID == offline {
+			require.Error(t, delivery.Error)
+			continue
+		}
+		assert.NoError(t, delivery.Error)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-received:
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out waiting for multicast")
+		}
+	}
+
+	// broadcast to edges on fw-1.0
+	msg = svc.NewMessage([]byte("firmware"))
+	deliveries, err = svc.Broadcast(context.TODO(), &service.EdgeSelector{Meta: "fw-1"}, msg)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	for _, delivery := range deliveries {
+		assert.NoError(t, delivery.Error)
+		assert.NotEqual(t, edges[2].EdgeID(), delivery.EdgeID)
+	}
+}
+
+// UNIT-EXCH-009: RPC, Message and Stream from Edge forwarded to peer Edge under policy
+func TestExchangeEdgeToEdge(t *testing.T) {
+	newHarness(t)
+
+	e1, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e1.Close()
+	e2, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e2.Close()
+	require.NoError(t, e2.Register(context.TODO(), "echo", func(_ context.Context, req geminio.Request, resp geminio.Response) {
+		resp.SetData(req.Data())
+	}))
+	time.Sleep(20 * time.Millisecond)
+
+	// denied without policy service
+	_, err = e1.CallEdge(context.TODO(), e2.EdgeID(), "echo", e1.NewRequest([]byte("ping")))
+	require.Error(t, err)
+
+	// only e1 -> e2 is allowed
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("policy"))
+	require.NoError(t, err)
+	defer svc.Close()
+	require.NoError(t, svc.RegisterEdgeToEdge(context.TODO(), func(srcEdgeID, dstEdgeID uint64) error {
+		if srcEdgeID == e1.EdgeID() && dstEdgeID == e2.EdgeID() {
+			return nil
+		}
+		return errors.New("denied")
+	}))
+	time.Sleep(20 * time.Millisecond)
+
+	// rpc
+	resp, err := e1.CallEdge(context.TODO(), e2.EdgeID(), "echo", e1.NewRequest([]byte("ping")))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ping"), resp.Data())
+	_, err = e2.CallEdge(context.TODO(), e1.EdgeID(), "echo", e2.NewRequest([]byte("ping")))
+	require.Error(t, err)
+
+	// message
+	received := make(chan geminio.Message, 1)
+	go func() {
+		msg, err := e2.Receive(context.TODO())
+		if err != nil {
+			return
+		}
+		msg.Done()
+		received <- msg
+	}()
+	require.NoError(t, e1.PublishEdge(context.TODO(), e2.EdgeID(), "hello", e1.NewMessage([]byte("hi"))))
+	select {
+	case msg := <-received:
+		assert.Equal(t, "hello", msg.Topic())
+		assert.Equal(t, []byte("hi"), msg.Data())
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for edge to edge message")
+	}
+
+	// stream
+	st, err := e1.OpenEdgeStream(e2.EdgeID())
+	require.NoError(t, err)
+	defer st.Close()
+	accepted, err := e2.AcceptStream()
+	require.NoError(t, err)
+	defer accepted.Close()
+	srcEdgeID, _, ok := apis.ParseEdgeTarget(accepted.Peer())
+	require.True(t, ok)
+	assert.Equal(t, e1.EdgeID(), srcEdgeID)
+}
+
+// UNIT-EXCH-010: Message from Service queued for offline Edge and flushed in order when online
+func TestExchangeOutboxToOfflineEdge(t *testing.T) {
+	newHarness(t, func(conf *config.Configuration) {
+		conf.Exchange.Outbox = config.Outbox{Enable: true, MaxDepth: 3}
+	})
+
+	const edgeID = uint64(10086)
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("outbox"))
+	require.NoError(t, err)
+	defer svc.Close()
+	require.NoError(t, svc.RegisterGetEdgeID(context.TODO(), func(meta []byte) (uint64, error) {
+		return edgeID, nil
+	}))
+	time.Sleep(20 * time.Millisecond)
+
+	// deliver now or fail
+	require.Error(t, svc.Publish(context.TODO(), edgeID, svc.NewMessage([]byte("now"))))
+	// queue until online
+	for _, data := range []string{"1", "2", "3"} {
+		require.NoError(t, svc.PublishQueued(context.TODO(), edgeID, svc.NewMessage([]byte(data))))
+	}
+	// exceeds max depth
+	require.Error(t, svc.PublishQueued(context.TODO(), edgeID, svc.NewMessage([]byte("4"))))
+
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+
+	for _, data := range []string{"1", "2", "3"} {
+		ctx, cancel := context.WithTimeout(context.TODO(), 3*time.Second)
+		msg, err := e.Receive(ctx)
+		cancel()
+		require.NoError(t, err)
+		assert.Equal(t, []byte(data), msg.Data())
+		msg.Done()
+	}
+
+	// the edge is online, delivered now
+	received := make(chan []byte, 1)
+	go func() {
+		msg, err := e.Receive(context.TODO())
+		if err != nil {
+			return
+		}
+		msg.Done()
+		received <- msg.Data()
+	}()
+	require.NoError(t, svc.PublishQueued(context.TODO(), edgeID, svc.NewMessage([]byte("5"))))
+	select {
+	case data := <-received:
+		assert.Equal(t, []byte("5"), data)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for queued publish")
+	}
+}
+
+// UNIT-EXCH-011: RPC forwarding honors the caller's deadline and per-method overrides
+func TestExchangeForwardRPCTimeout(t *testing.T) {
+	newHarness(t, func(conf *config.Configuration) {
+		conf.Exchange.Timeout = config.Timeout{
+			Default: 100,
+			Methods: map[string]int{"probe": 100},
+		}
+	})
+
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("timeout-svc"))
+	require.NoError(t, err)
+	defer svc.Close()
+	slow := func(_ context.Context, req geminio.Request, resp geminio.Response) {
+		time.Sleep(300 * time.Millisecond)
+		resp.SetData(req.Data())
+	}
+	require.NoError(t, svc.Register(context.TODO(), "firmware", slow))
+	require.NoError(t, svc.Register(context.TODO(), "probe", slow))
+	time.Sleep(20 * time.Millisecond)
+
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+
+	// the caller's deadline is longer than the default
+	ctx, cancel := context.WithTimeout(context.TODO(), 2*time.Second)
+	defer cancel()
+	resp, err := e.Call(ctx, "firmware", e.NewRequest([]byte("upgrade")))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("upgrade"), resp.Data())
+
+	// the override caps the caller's deadline
+	start := time.Now()
+	_, err = e.Call(ctx, "probe", e.NewRequest([]byte("ping")))
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 300*time.Millisecond)
+}
+
+// UNIT-EXCH-012: RPC fails over to another service instance for opted in methods
+func TestExchangeForwardRPCFailover(t *testing.T) {
+	newHarness(t, func(conf *config.Configuration) {
+		conf.Exchange.HashBy = "random"
+		conf.Exchange.Timeout.Methods = map[string]int{"get": 100, "set": 100}
+		conf.Exchange.Failover = config.Failover{
+			MaxAttempts: 2,
+			Backoff:     10,
+			Methods:     []string{"get"},
+		}
+	})
+
+	var hung int32
+	stuck := func(_ context.Context, req geminio.Request, resp geminio.Response) {
+		atomic.AddInt32(&hung, 1)
+		time.Sleep(300 * time.Millisecond)
+	}
+	echo := func(_ context.Context, req geminio.Request, resp geminio.Response) {
+		resp.SetData(req.Data())
+	}
+	bad, err := service.NewService(svcDial(), service.OptionServiceName("failover-svc"))
+	require.NoError(t, err)
+	defer bad.Close()
+	require.NoError(t, bad.Register(context.TODO(), "get", stuck))
+	require.NoError(t, bad.Register(context.TODO(), "set", stuck))
+
+	good, err := service.NewService(svcDial(), service.OptionServiceName("failover-svc"))
+	require.NoError(t, err)
+	defer good.Close()
+	require.NoError(t, good.Register(context.TODO(), "get", echo))
+	require.NoError(t, good.Register(context.TODO(), "set", echo))
+	time.Sleep(20 * time.Millisecond)
+
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+
+	// the opted in method always succeeds
+	for i := 0; i < 10; i++ {
+		resp, err := e.Call(context.TODO(), "get", e.NewRequest([]byte("ping")))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("ping"), resp.Data())
+	}
+	assert.NotZero(t, atomic.LoadInt32(&hung))
+
+	// the others get the error from the hashed instance
+	failed := 0
+	for i := 0; i < 10; i++ {
+		_, err := e.Call(context.TODO(), "set", e.NewRequest([]byte("ping")))
+		if err != nil {
+			failed++
+		}
+	}
+	assert.NotZero(t, failed)
+}
+
+// UNIT-EXCH-013: Message from Edge forwarded to the most specific wildcard subscriber
+func TestExchangeForwardMessageToWildcardService(t *testing.T) {
+	newHarness(t)
+
+	receive := func(topics []string) (chan string, func() error) {
+		svc, err := service.NewService(svcDial(),
+			service.OptionServiceName("wildcard-svc"),
+			service.OptionServiceReceiveTopics(topics),
+		)
+		require.NoError(t, err)
+		received := make(chan string, 4)
+		go func() {
+			for {
+				msg, err := svc.Receive(context.TODO())
+				if err != nil {
+					return
+				}
+				received <- msg.Topic()
+				msg.Done()
+			}
+		}()
+		return received, svc.Close
+	}
+	temp, closeTemp := receive([]string{"telemetry/+/+/temp"})
+	defer closeTemp()
+	all, closeAll := receive([]string{"telemetry/#"})
+	defer closeAll()
+	time.Sleep(20 * time.Millisecond)
+
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+
+	require.NoError(t, e.Publish(context.TODO(), "telemetry/site1/dev1/temp", e.NewMessage([]byte("25"))))
+	require.NoError(t, e.Publish(context.TODO(), "telemetry/site1/dev1/humidity", e.NewMessage([]byte("60"))))
+
+	for _, tt := range []struct {
+		received chan string
+		want     string
+	}{
+		{temp, "telemetry/site1/dev1/temp"},
+		{all, "telemetry/site1/dev1/humidity"},
+	} {
+		select {
+		case topic := <-tt.received:
+			assert.Equal(t, tt.want, topic)
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out")
+		}
+	}
+}
+
+// UNIT-EXCH-014: Message from Edge delivered to each service group once
+func TestExchangeForwardMessageToServiceGroups(t *testing.T) {
+	newHarness(t)
+
+	const topic = "telemetry"
+	var audit, analytics int32
+	receive := func(group string, count *int32) {
+		svc, err := service.NewService(svcDial(),
+			service.OptionServiceName(group),
+			service.OptionServiceReceiveTopics([]string{topic}),
+			service.OptionServiceGroup(group),
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() { svc.Close() })
+		go func() {
+			for {
+				msg, err := svc.Receive(context.TODO())
+				if err != nil {
+					return
+				}
+				atomic.AddInt32(count, 1)
+				msg.Done()
+			}
+		}()
+	}
+	receive("audit", &audit)
+	receive("analytics", &analytics)
+	receive("analytics", &analytics)
+	time.Sleep(20 * time.Millisecond)
+
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, e.Publish(context.TODO(), topic, e.NewMessage([]byte("25"))))
+	}
+	assert.Equal(t, int32(4), atomic.LoadInt32(&audit))
+	assert.Equal(t, int32(4), atomic.LoadInt32(&analytics))
+}
+
+// UNIT-EXCH-015: Raw bytes forwarded between Edge and Service on the main connection
+func TestExchangeForwardRaw(t *testing.T) {
+	newHarness(t)
+
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("raw-svc"))
+	require.NoError(t, err)
+	defer svc.Close()
+	time.Sleep(20 * time.Millisecond)
+
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+
+	// more than the window, the service reads after the edge starts writing
+	payload := make([]byte, 1024*1024)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+	written := make(chan error, 1)
+	go func() {
+		_, err := e.WriteRaw("raw-svc", payload)
+		written <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	got := []byte{}
+	for len(got) < len(payload) {
+		edgeID, data, err := svc.ReadRaw()
+		require.NoError(t, err)
+		assert.Equal(t, e.EdgeID(), edgeID)
+		got = append(got, data...)
+	}
+	assert.Equal(t, payload, got)
+	require.NoError(t, <-written)
+
+	// and back to the edge
+	_, err = svc.WriteRaw(e.EdgeID(), []byte("ack"))
+	require.NoError(t, err)
+	name, data, err := e.ReadRaw()
+	require.NoError(t, err)
+	assert.Equal(t, "raw-svc", name)
+	assert.Equal(t, []byte("ack"), data)
+}
+
+// UNIT-EXCH-016: Stream open failures returned to the opener with typed errors
+func TestExchangeStreamOpenError(t *testing.T) {
+	newHarness(t)
+
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("stream-svc"))
+	require.NoError(t, err)
+	defer svc.Close()
+	go func() {
+		st, err := svc.AcceptStream()
+		if err != nil {
+			return
+		}
+		io.Copy(st, st)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = e.OpenStream("no-such-svc")
+	assert.ErrorIs(t, err, apis.ErrServiceNotOnline)
+
+	_, err = svc.OpenStream(context.TODO(), e.EdgeID()+1)
+	assert.ErrorIs(t, err, apis.ErrEdgeNotOnline)
+
+	// the ack isn't seen by the stream data
+	st, err := e.OpenStream("stream-svc")
+	require.NoError(t, err)
+	defer st.Close()
+	_, err = st.Write([]byte("hello"))
+	require.NoError(t, err)
+	buf := make([]byte, 16)
+	n, err := st.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(buf[:n]))
+}
+
+// UNIT-EXCH-017: RPCs and messages over a stream carry the caller
+func TestExchangeStreamCaller(t *testing.T) {
+	newHarness(t)
+
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("caller-svc"))
+	require.NoError(t, err)
+	defer svc.Close()
+	type caller struct {
+		clientID, streamID uint64
+		custom             string
+	}
+	callers := make(chan caller, 2)
+	go func() {
+		st, err := svc.AcceptStream()
+		if err != nil {
+			return
+		}
+		st.Register(context.TODO(), "who", func(_ context.Context, req geminio.Request, rsp geminio.Response) {
+			callers <- caller{req.ClientID(), req.StreamID(), string(req.Custom())}
+			rsp.SetData(req.Data())
+		})
+		msg, err := st.Receive(context.TODO())
+		if err != nil {
+			return
+		}
+		callers <- caller{msg.ClientID(), msg.StreamID(), string(msg.Custom())}
+		msg.Done()
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+
+	st, err := e.OpenStream("caller-svc")
+	require.NoError(t, err)
+	defer st.Close()
+	time.Sleep(20 * time.Millisecond)
+
+	req := st.NewRequest([]byte("ping"))
+	req.SetCustom([]byte("rpc"))
+	rsp, err := st.Call(context.TODO(), "who", req)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ping"), rsp.Data())
+
+	msg := st.NewMessage([]byte("hello"))
+	msg.SetCustom([]byte("msg"))
+	require.NoError(t, st.Publish(context.TODO(), msg))
+
+	for _, custom := range []string{"rpc", "msg"} {
+		select {
+		case got := <-callers:
+			assert.Equal(t, e.EdgeID(), got.clientID)
+			assert.Equal(t, st.StreamID(), got.streamID)
+			assert.Equal(t, custom, got.custom)
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out waiting for the caller")
+		}
+	}
+}
+
+// UNIT-EXCH-018: Headers carried in the envelope, and the old edgeID tail translated
+func TestExchangeHeaders(t *testing.T) {
+	newHarness(t)
+
+	svc, err := service.NewService(svcDial(),
+		service.OptionServiceName("hdr-svc"),
+		service.OptionServiceReceiveTopics([]string{"hdr"}))
+	require.NoError(t, err)
+	defer svc.Close()
+	require.NoError(t, svc.Register(context.TODO(), "who", func(_ context.Context, req geminio.Request, rsp geminio.Response) {
+		headers := apis.GetHeaders(req)
+		_, hasDeadline := headers.Uint64(apis.HeaderDeadline)
+		rsp.SetData([]byte(fmt.Sprintf("%s|%d|%s|%s|%s|%v", req.Custom(), req.ClientID(),
+			headers.Get(apis.HeaderEdgeID), headers.Get("x-user"), headers.Get(apis.HeaderTraceParent), hasDeadline)))
+	}))
+
+	// a service doesn't know envelopes
+	meta, _ := json.Marshal(&apis.Meta{Service: "old-svc"})
+	eopts := client.NewEndOptions()
+	eopts.SetMeta(meta)
+	old, err := client.NewEndWithDialer(client.Dialer(svcDial()), eopts)
+	require.NoError(t, err)
+	defer old.Close()
+	require.NoError(t, old.Register(context.TODO(), "old", func(_ context.Context, req geminio.Request, rsp geminio.Response) {
+		custom := req.Custom()
+		rsp.SetData([]byte(fmt.Sprintf("%s|%d", custom[:len(custom)-8], binary.BigEndian.Uint64(custom[len(custom)-8:]))))
+	}))
+	time.Sleep(20 * time.Millisecond)
+
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+	require.NoError(t, e.Register(context.TODO(), "hi", func(_ context.Context, req geminio.Request, rsp geminio.Response) {
+		rsp.SetData([]byte(fmt.Sprintf("%s|%s", req.Custom(), apis.GetHeaders(req).Get("x-svc"))))
+	}))
+	time.Sleep(20 * time.Millisecond)
+	edgeID := e.EdgeID()
+
+	// edge -> service
+	req := e.NewRequest(nil)
+	req.SetCustom([]byte("c"))
+	apis.SetHeaders(req, apis.Headers{"x-user": "u", apis.HeaderTraceParent: "00-trace-span-01"})
+	ctx, cancel := context.WithTimeout(context.TODO(), 3*time.Second)
+	defer cancel()
+	rsp, err := e.Call(ctx, "who", req)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("c|%d|%d|u|00-trace-span-01|true", edgeID, edgeID), string(rsp.Data()))
+
+	// edge -> service message
+	msg := e.NewMessage([]byte("m"))
+	msg.SetCustom([]byte("c"))
+	apis.SetHeaders(msg, apis.Headers{"x-user": "u"})
+	received := make(chan geminio.Message, 1)
+	go func() {
+		if got, err := svc.Receive(context.TODO()); err == nil {
+			got.Done()
+			received <- got
+		}
+	}()
+	require.NoError(t, e.Publish(context.TODO(), "hdr", msg))
+	got := <-received
+	assert.Equal(t, []byte("c"), got.Custom())
+	assert.Equal(t, edgeID, got.ClientID())
+	assert.Equal(t, "u", apis.GetHeaders(got).Get("x-user"))
+
+	// edge -> old service
+	req = e.NewRequest(nil)
+	req.SetCustom([]byte("c"))
+	rsp, err = e.Call(context.TODO(), "old", req)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("c|%d", edgeID), string(rsp.Data()))
+
+	// service -> edge
+	req = svc.NewRequest(nil)
+	req.SetCustom([]byte("s"))
+	apis.SetHeaders(req, apis.Headers{"x-svc": "v"})
+	rsp, err = svc.Call(context.TODO(), edgeID, "hi", req)
+	require.NoError(t, err)
+	assert.Equal(t, "s|v", string(rsp.Data()))
+	assert.Equal(t, edgeID, rsp.ClientID())
+
+	// old service -> edge, with the old tail
+	tail := make([]byte, 8)
+	binary.BigEndian.PutUint64(tail, edgeID)
+	req = old.NewRequest(nil)
+	req.SetCustom(append([]byte("o"), tail...))
+	rsp, err = old.Call(context.TODO(), "hi", req)
+	require.NoError(t, err)
+	assert.Equal(t, "o|", string(rsp.Data()))
+}
+
+// UNIT-EXCH-019: Messages and RPCs beyond the rate limits rejected, and the edge limit overridden
+func TestExchangeRateLimit(t *testing.T) {
+	h := newHarness(t, func(conf *config.Configuration) {
+		conf.Exchange.RateLimit = config.RateLimit{
+			Edge:    config.Limit{Messages: 2, RPCs: 2},
+			Service: config.Limit{RPCs: 2},
+		}
+	})
+
+	svc, err := service.NewService(svcDial(),
+		service.OptionServiceName("limit-svc"),
+		service.OptionServiceReceiveTopics([]string{"limit"}))
+	require.NoError(t, err)
+	defer svc.Close()
+	require.NoError(t, svc.Register(context.TODO(), "echo", func(_ context.Context, req geminio.Request, rsp geminio.Response) {
+		rsp.SetData(req.Data())
+	}))
+	go func() {
+		for {
+			msg, err := svc.Receive(context.TODO())
+			if err != nil {
+				return
+			}
+			msg.Done()
+		}
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+	require.NoError(t, e.Register(context.TODO(), "echo", func(_ context.Context, req geminio.Request, rsp geminio.Response) {
+		rsp.SetData(req.Data())
+	}))
+	time.Sleep(20 * time.Millisecond)
+
+	limited := func(call func() error) int {
+		n := 0
+		for i := 0; i < 5; i++ {
+			if err := call(); err != nil {
+				assert.Equal(t, apis.ErrRateLimited.Error(), err.Error())
+				n++
+			}
+		}
+		return n
+	}
+	edgeCall := func() error {
+		_, err := e.Call(context.TODO(), "echo", e.NewRequest([]byte("ping")))
+		return err
+	}
+	// edge rpcs and messages
+	assert.NotZero(t, limited(edgeCall))
+	assert.NotZero(t, limited(func() error {
+		return e.Publish(context.TODO(), "limit", e.NewMessage([]byte("m")))
+	}))
+	// service rpcs
+	assert.NotZero(t, limited(func() error {
+		_, err := svc.Call(context.TODO(), e.EdgeID(), "echo", svc.NewRequest([]byte("ping")))
+		return err
+	}))
+
+	// override the edge
+	h.ex.SetEdgeRateLimit(e.EdgeID(), &apis.RateLimit{RPCs: 100})
+	assert.Equal(t, 100, h.ex.GetEdgeRateLimit(e.EdgeID()).RPCs)
+	assert.Zero(t, limited(edgeCall))
+
+	// restore the configured
+	h.ex.SetEdgeRateLimit(e.EdgeID(), nil)
+	assert.Equal(t, 2, h.ex.GetEdgeRateLimit(e.EdgeID()).RPCs)
+	assert.NotZero(t, limited(edgeCall))
+}
+
+// UNIT-EXCH-020: Failing service instance tripped and skipped, and closed again after a successful probe
+func TestExchangeBreaker(t *testing.T) {
+	newHarness(t, func(conf *config.Configuration) {
+		conf.Exchange.Timeout.Methods = map[string]int{"get": 100}
+		conf.Exchange.Breaker = config.Breaker{
+			Enable:      true,
+			MinRequests: 2,
+			ErrorRate:   50,
+			Cooldown:    1,
+		}
+	})
+
+	var stuck int32 = 1
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("breaker-svc"))
+	require.NoError(t, err)
+	defer svc.Close()
+	require.NoError(t, svc.Register(context.TODO(), "get", func(_ context.Context, req geminio.Request, rsp geminio.Response) {
+		if atomic.LoadInt32(&stuck) == 1 {
+			time.Sleep(300 * time.Millisecond)
+		}
+		rsp.SetData(req.Data())
+	}))
+	time.Sleep(20 * time.Millisecond)
+
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+
+	// timed out and tripped
+	for i := 0; i < 2; i++ {
+		_, err := e.Call(context.TODO(), "get", e.NewRequest([]byte("ping")))
+		require.Error(t, err)
+	}
+	// skipped without waiting
+	start := time.Now()
+	_, err = e.Call(context.TODO(), "get", e.NewRequest([]byte("ping")))
+	require.Error(t, err)
+	assert.Equal(t, apis.ErrCircuitOpen.Error(), err.Error())
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+	// recovered and probed after the cooldown
+	atomic.StoreInt32(&stuck, 0)
+	time.Sleep(1100 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		rsp, err := e.Call(context.TODO(), "get", e.NewRequest([]byte("ping")))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("ping"), rsp.Data())
+	}
+}
+
+// UNIT-EXCH-021: RPC to a method the Edge never registered fails fast, and the support is queried
+func TestExchangeEdgeRPCNotRegistered(t *testing.T) {
+	newHarness(t, func(conf *config.Configuration) {
+		conf.Exchange.Timeout.Methods = map[string]int{"missing": 3000}
+	})
+
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+	require.NoError(t, e.Register(context.TODO(), "greet", func(_ context.Context, req geminio.Request, resp geminio.Response) {
+		resp.SetData([]byte("hello-from-edge"))
+	}))
+	time.Sleep(20 * time.Millisecond)
+
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("supports-caller"))
+	require.NoError(t, err)
+	defer svc.Close()
+
+	ok, err := svc.EdgeSupports(context.TODO(), e.EdgeID(), "greet")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = svc.EdgeSupports(context.TODO(), e.EdgeID(), "missing")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, err = svc.EdgeSupports(context.TODO(), e.EdgeID()+1, "greet")
+	require.Error(t, err)
+	assert.Equal(t, apis.ErrEdgeNotOnline.Error(), err.Error())
+
+	// rejected without waiting on the edge
+	start := time.Now()
+	_, err = svc.Call(context.TODO(), e.EdgeID(), "missing", svc.NewRequest([]byte("")))
+	require.Error(t, err)
+	assert.Equal(t, apis.ErrRPCNotRegistered.Error(), err.Error())
+	assert.Less(t, time.Since(start), time.Second)
+
+	resp, err := svc.Call(context.TODO(), e.EdgeID(), "greet", svc.NewRequest([]byte("")))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello-from-edge"), resp.Data())
+}
+
+// UNIT-EXCH-022: Message published by topic from Service delivered to the subscribed Edges
+func TestExchangePublishTopic(t *testing.T) {
+	newHarness(t)
+
+	received := make(chan string, 4)
+	newSubscriber := func(topics ...string) edge.Edge {
+		e, err := edge.NewEdge(edgeDial())
+		require.NoError(t, err)
+		if len(topics) != 0 {
+			require.NoError(t, e.Subscribe(context.TODO(), topics...))
+		}
+		go func() {
+			for {
+				msg, err := e.Receive(context.TODO())
+				if err != nil {
+					return
+				}
+				received <- msg.Topic() + ":" + strconv.FormatUint(e.EdgeID(), 10)
+				msg.Done()
+			}
+		}()
+		return e
+	}
+	e1 := newSubscriber("news", "alerts")
+	defer e1.Close()
+	e2 := newSubscriber("news")
+	defer e2.Close()
+	e3 := newSubscriber()
+	defer e3.Close()
+
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("topic-pub"))
+	require.NoError(t, err)
+	defer svc.Close()
+
+	deliveries, err := svc.PublishTopic(context.TODO(), "news", svc.NewMessage([]byte("headline")))
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	for _, delivery := range deliveries {
+		assert.NoError(t, delivery.Error)
+	}
+	got := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case r := <-received:
+			got[r] = true
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out")
+		}
+	}
+	assert.True(t, got["news:"+strconv.FormatUint(e1.EdgeID(), 10)])
+	assert.True(t, got["news:"+strconv.FormatUint(e2.EdgeID(), 10)])
+
+	// unsubscribed edge skipped
+	require.NoError(t, e1.Unsubscribe(context.TODO(), "news"))
+	deliveries, err = svc.PublishTopic(context.TODO(), "news", svc.NewMessage([]byte("headline")))
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, e2.EdgeID(), deliveries[0].EdgeID)
+
+	// no subscriber
+	deliveries, err = svc.PublishTopic(context.TODO(), "none", svc.NewMessage([]byte("headline")))
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 0)
+}
+
+// UNIT-EXCH-023: Topics subscribed and unsubscribed by Service at runtime without reconnecting
+func TestExchangeServiceSubscribe(t *testing.T) {
+	h := newHarness(t)
+
+	svc, err := service.NewService(svcDial(),
+		service.OptionServiceName("sub-svc"),
+		service.OptionServiceReceiveTopics([]string{"news"}),
+	)
+	require.NoError(t, err)
+	defer svc.Close()
+
+	received := make(chan string, 4)
+	go func() {
+		for {
+			msg, err := svc.Receive(context.TODO())
+			if err != nil {
+				return
+			}
+			received <- msg.Topic()
+			msg.Done()
+		}
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+
+	// not received yet
+	require.Error(t, e.Publish(context.TODO(), "alerts", e.NewMessage([]byte("fire"))))
+
+	require.NoError(t, svc.Subscribe(context.TODO(), "alerts"))
+	require.NoError(t, e.Publish(context.TODO(), "alerts", e.NewMessage([]byte("fire"))))
+	select {
+	case topic := <-received:
+		assert.Equal(t, "alerts", topic)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out")
+	}
+	svcs, err := h.sb.GetServicesByTopic("alerts")
+	require.NoError(t, err)
+	assert.Len(t, svcs, 1)
+
+	// the topic declared in meta unsubscribed too
+	require.NoError(t, svc.Unsubscribe(context.TODO(), "alerts", "news"))
+	require.Error(t, e.Publish(context.TODO(), "alerts", e.NewMessage([]byte("fire"))))
+	require.Error(t, e.Publish(context.TODO(), "news", e.NewMessage([]byte("headline"))))
+	_, err = h.sb.GetServicesByTopic("news")
+	assert.Error(t, err)
+}
+
+// UNIT-EXCH-024: Edge rejected with a reason code before the edgeID is acquired, and the credential isn't passed on
+func TestExchangeEdgeAuth(t *testing.T) {
+	secret := []byte("secret")
+	newHarness(t, func(conf *config.Configuration) {
+		conf.Edgebound.Auth = config.EdgeAuth{Enable: true, Mode: edgebound.AuthModeHMAC, Secret: string(secret)}
+	})
+
+	const edgeID = 10086
+	var acquired atomic.Int32
+	metas := make(chan []byte, 1)
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("auth"))
+	require.NoError(t, err)
+	defer svc.Close()
+	require.NoError(t, svc.RegisterGetEdgeID(context.TODO(), func(meta []byte) (uint64, error) {
+		acquired.Add(1)
+		return edgeID, nil
+	}))
+	require.NoError(t, svc.RegisterEdgeOnline(context.TODO(), func(_ uint64, meta []byte, _ net.Addr) error {
+		metas <- meta
+		return nil
+	}))
+	time.Sleep(20 * time.Millisecond)
+
+	meta := []byte("device-1")
+	_, err = edge.NewEdge(edgeDial(), edge.OptionEdgeMeta(meta))
+	require.Error(t, err)
+	ae, ok := apis.ParseAuthError(err)
+	require.True(t, ok, err.Error())
+	assert.Equal(t, apis.AuthMissingCredential, ae.Code)
+
+	_, err = edge.NewEdge(edgeDial(), edge.OptionEdgeMeta(meta), edge.OptionEdgeHMAC([]byte("wrong")))
+	require.Error(t, err)
+	ae, ok = apis.ParseAuthError(err)
+	require.True(t, ok, err.Error())
+	assert.Equal(t, apis.AuthInvalidSignature, ae.Code)
+	assert.Equal(t, int32(0), acquired.Load())
+
+	// edges with pre set edgeIDs are authenticated too
+	_, err = edge.NewEdge(edgeDial(), edge.OptionEdgeMeta(meta), edge.OptionEdgeID(edgeID))
+	require.Error(t, err)
+	ae, ok = apis.ParseAuthError(err)
+	require.True(t, ok, err.Error())
+	assert.Equal(t, apis.AuthMissingCredential, ae.Code)
+
+	e, err := edge.NewEdge(edgeDial(), edge.OptionEdgeMeta(meta), edge.OptionEdgeHMAC(secret))
+	require.NoError(t, err)
+	defer e.Close()
+	assert.Equal(t, uint64(edgeID), e.EdgeID())
+	select {
+	case got := <-metas:
+		assert.Equal(t, meta, got)
+	case <-time.After(time.Second):
+		t.Fatal("edge online not notified")
+	}
+
+	// the updated meta is signed by the sdk, an unsigned one is rejected
+	require.NoError(t, e.UpdateMeta(context.TODO(), []byte("device-2")))
+	data, _ := json.Marshal(&apis.MetaUpdate{Meta: []byte("device-3")})
+	_, err = e.Call(context.TODO(), apis.RPCUpdateMeta, e.NewRequest(data))
+	require.Error(t, err)
+	ae, ok = apis.ParseAuthError(err)
+	require.True(t, ok, err.Error())
+	assert.Equal(t, apis.AuthMissingCredential, ae.Code)
+}
+
+func issueCert(t *testing.T, tmpl, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl.NotBefore = time.Now().Add(-time.Hour)
+	tmpl.NotAfter = time.Now().Add(time.Hour)
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func writeCertKey(t *testing.T, dir, name string, cert *x509.Certificate, key *ecdsa.PrivateKey) gconfig.CertKey {
+	certKey := gconfig.CertKey{Cert: filepath.Join(dir, name+".pem"), Key: filepath.Join(dir, name+".key")}
+	require.NoError(t, os.WriteFile(certKey.Cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0600))
+	der, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(certKey.Key, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600))
+	return certKey
+}
+
+// UNIT-EXCH-025: EdgeID and labels derived from the mTLS client certificate, mismatched claims rejected
+func TestExchangeEdgeCertIdentity(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := issueCert(t, &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "ca"},
+		IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign}, nil, nil)
+	caFile := writeCertKey(t, dir, "ca", ca, caKey).Cert
+	server, serverKey := issueCert(t, &x509.Certificate{SerialNumber: big.NewInt(2), DNSNames: []string{"frontier"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}, ca, caKey)
+	serverCertKey := writeCertKey(t, dir, "server", server, serverKey)
+
+	h := newHarness(t, func(conf *config.Configuration) {
+		conf.Edgebound.Listen.TLS = gconfig.TLS{Enable: true, MTLS: true,
+			CACerts: []string{caFile}, Certs: []gconfig.CertKey{serverCertKey}}
+		conf.Edgebound.CertIdentity = config.CertIdentity{Enable: true, EdgeID: "san_uri", EdgeIDPattern: `/edge/(\d+)$`,
+			Meta: "san_dns", Labels: map[string]string{"serial": "serial"}}
+	})
+	uri, _ := url.Parse("spiffe://frontier/edge/10086")
+	client, clientKey := issueCert(t, &x509.Certificate{SerialNumber: big.NewInt(7), URIs: []*url.URL{uri},
+		DNSNames: []string{"device-1"}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}, ca, caKey)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	dial := func() (net.Conn, error) {
+		return tls.Dial(testNetwork, edgeboundAddr, &tls.Config{
+			RootCAs:    roots,
+			ServerName: "frontier",
+			Certificates: []tls.Certificate{{
+				Certificate: [][]byte{client.Raw},
+				PrivateKey:  clientKey,
+			}},
+		})
+	}
+
+	e, err := edge.NewEdge(dial, edge.OptionEdgeMeta([]byte("device-1")))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10086), e.EdgeID())
+	time.Sleep(20 * time.Millisecond)
+	end, ok := h.eb.(apis.Edgebound).GetEdgeByID(10086).(interface{ Labels() map[string]string })
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{"serial": "7"}, end.Labels())
+	e.Close()
+	time.Sleep(20 * time.Millisecond)
+
+	// claims mismatching the certificate
+	for _, opts := range [][]edge.EdgeOption{
+		{edge.OptionEdgeMeta([]byte("device-2"))},
+		{edge.OptionEdgeMeta([]byte("device-1")), edge.OptionEdgeID(10010)},
+	} {
+		_, err = edge.NewEdge(dial, opts...)
+		require.Error(t, err)
+		ae, ok := apis.ParseAuthError(err)
+		require.True(t, ok, err.Error())
+		assert.Equal(t, apis.AuthIdentityMismatch, ae.Code)
+	}
+}
+
+// UNIT-EXCH-026: Connections over the admission limits closed before handshaking
+func TestExchangeEdgeAdmission(t *testing.T) {
+	newHarness(t, func(conf *config.Configuration) {
+		conf.Edgebound.Admission = config.Admission{MaxEdges: 2, MaxHandshakes: 1, ConnRate: 2}
+	})
+
+	e1, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e1.Close()
+	e2, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	// max edges
+	start := time.Now()
+	_, err = edge.NewEdge(edgeDial())
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// the rejected by max edges take no token, the refilled fraction lets one more in
+	e2.Close()
+	time.Sleep(20 * time.Millisecond)
+	e3, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	e3.Close()
+	time.Sleep(20 * time.Millisecond)
+	// exceeds the rate of the source
+	_, err = edge.NewEdge(edgeDial())
+	require.Error(t, err)
+
+	// refilled
+	time.Sleep(time.Second)
+	e4, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	e4.Close()
+}
+
+// UNIT-EXCH-027: Draining rejects new Edges, and closes the online ones after their RPCs are done with the redirect addr
+func TestExchangeEdgeDrain(t *testing.T) {
+	h := newHarness(t)
+	eb := h.eb.(apis.Edgebound)
+
+	redirected := make(chan string, 1)
+	e, err := edge.NewEdge(edgeDial(), edge.OptionEdgeRedirect(func(addr string) (net.Conn, error) {
+		select {
+		case redirected <- addr:
+		default:
+		}
+		return nil, errors.New("unreachable")
+	}))
+	require.NoError(t, err)
+	defer e.Close()
+	require.NoError(t, e.Register(context.TODO(), "slow", func(_ context.Context, req geminio.Request, resp geminio.Response) {
+		time.Sleep(500 * time.Millisecond)
+		resp.SetData(req.Data())
+	}))
+	time.Sleep(20 * time.Millisecond)
+	edgeID := e.EdgeID()
+
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("drain-svc"))
+	require.NoError(t, err)
+	defer svc.Close()
+	called := make(chan error, 1)
+	go func() {
+		_, err := svc.Call(context.TODO(), edgeID, "slow", svc.NewRequest([]byte("ping")))
+		called <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, h.ex.EdgeInflight(edgeID))
+
+	drained := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+		defer cancel()
+		drained <- eb.Drain(ctx, "10.0.0.2:30012")
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// no new edge accepted
+	_, err = edge.NewNoRetryEdge(edgeDial())
+	require.Error(t, err)
+	// kept until the rpc is done
+	assert.NotNil(t, eb.GetEdgeByID(edgeID))
+	require.NoError(t, <-called)
+
+	select {
+	case err := <-drained:
+		require.NoError(t, err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("drain not done")
+	}
+	assert.Nil(t, eb.GetEdgeByID(edgeID))
+	// the edge reconnects to the addr told
+	select {
+	case addr := <-redirected:
+		assert.Equal(t, "10.0.0.2:30012", addr)
+	case <-time.After(6 * time.Second):
+		t.Fatal("edge not redirected")
+	}
+}
+
+// UNIT-EXCH-028: Meta updated by Edge on the live connection persisted and notified, and updated again after reconnected
+func TestExchangeEdgeMetaUpdate(t *testing.T) {
+	h := newHarness(t)
+	eb := h.eb.(apis.Edgebound)
+	r := h.r.(apis.Repo)
+
+	onlines := make(chan []byte, 2)
+	updates := make(chan *apis.OnEdgeMetaUpdate, 2)
+	offlines := make(chan []byte, 1)
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("meta-svc"))
+	require.NoError(t, err)
+	defer svc.Close()
+	require.NoError(t, svc.RegisterEdgeOnline(context.TODO(), func(_ uint64, meta []byte, _ net.Addr) error {
+		onlines <- meta
+		return nil
+	}))
+	require.NoError(t, svc.RegisterEdgeMetaUpdate(context.TODO(), func(edgeID uint64, meta []byte, addr net.Addr) error {
+		updates <- &apis.OnEdgeMetaUpdate{EdgeID: edgeID, Meta: meta, Str: addr.String()}
+		return nil
+	}))
+	require.NoError(t, svc.RegisterEdgeOffline(context.TODO(), func(_ uint64, meta []byte, _ net.Addr) error {
+		offlines <- meta
+		return nil
+	}))
+	time.Sleep(20 * time.Millisecond)
+
+	e, err := edge.NewEdge(edgeDial(), edge.OptionEdgeMeta([]byte("firmware=1.0")))
+	require.NoError(t, err)
+	defer e.Close()
+	assert.Equal(t, []byte("firmware=1.0"), <-onlines)
+	edgeID := e.EdgeID()
+
+	require.NoError(t, e.UpdateMeta(context.TODO(), []byte("firmware=2.0")))
+	select {
+	case update := <-updates:
+		assert.Equal(t, edgeID, update.EdgeID)
+		assert.Equal(t, []byte("firmware=2.0"), update.Meta)
+		assert.NotEmpty(t, update.Str)
+	case <-time.After(time.Second):
+		t.Fatal("edge meta update not notified")
+	}
+	me, err := r.GetEdge(edgeID)
+	require.NoError(t, err)
+	assert.Equal(t, "firmware=2.0", me.Meta)
+	assert.Equal(t, []byte("firmware=2.0"), eb.GetEdgeByID(edgeID).Meta())
+
+	// the offline event carries the updated meta, and the reconnected edge updates it again
+	require.NoError(t, eb.DelEdgeByID(edgeID))
+	select {
+	case meta := <-offlines:
+		assert.Equal(t, []byte("firmware=2.0"), meta)
+	case <-time.After(time.Second):
+		t.Fatal("edge offline not notified")
+	}
+	select {
+	case update := <-updates:
+		assert.Equal(t, []byte("firmware=2.0"), update.Meta)
+		// the edgeID is allocated again without the id service
+		edgeID = update.EdgeID
+	case <-time.After(6 * time.Second):
+		t.Fatal("edge meta not updated again after reconnected")
+	}
+	me, err = r.GetEdge(edgeID)
+	require.NoError(t, err)
+	assert.Equal(t, "firmware=2.0", me.Meta)
+}
+
+// UNIT-EXCH-029: Labels of Edges indexed in the repo and selected by Broadcast and queries, and removed once offline
+func TestExchangeEdgeLabels(t *testing.T) {
+	h := newHarness(t)
+	r := h.r.(apis.Repo)
+
+	_, err := edge.NewEdge(edgeDial(), edge.OptionEdgeLabels(map[string]string{"site": "fra 1"}))
+	require.ErrorIs(t, err, label.ErrIllegalLabel)
+
+	labels := []map[string]string{
+		{"site": "fra1", "model": "x200"},
+		{"site": "fra1", "model": "x300", "canary": ""},
+		{"site": "ams1", "model": "x200"},
+	}
+	edges := make([]edge.Edge, len(labels))
+	received := make(chan uint64, len(labels))
+	for i := range labels {
+		e, err := edge.NewEdge(edgeDial(), edge.OptionEdgeLabels(labels[i]))
+		require.NoError(t, err)
+		defer e.Close()
+		edges[i] = e
+		go func() {
+			for {
+				msg, err := e.Receive(context.TODO())
+				if err != nil {
+					return
+				}
+				received <- e.EdgeID()
+				msg.Done()
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	got, err := r.GetEdgeLabels(edges[1].EdgeID())
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, "canary", got[0].Key)
+
+	selector, err := label.ParseSelector("model in (x200,x300),site!=ams1")
+	require.NoError(t, err)
+	mes, err := r.ListEdges(&query.EdgeQuery{Selector: selector})
+	require.NoError(t, err)
+	require.Len(t, mes, 2)
+
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("labeler"))
+	require.NoError(t, err)
+	defer svc.Close()
+
+	msg := svc.NewMessage([]byte("rollout"))
+	deliveries, err := svc.Broadcast(context.TODO(), &service.EdgeSelector{Labels: "site=fra1,!canary"}, msg)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, edges[0].EdgeID(), deliveries[0].EdgeID)
+	assert.NoError(t, deliveries[0].Error)
+	select {
+	case edgeID := <-received:
+		assert.Equal(t, edges[0].EdgeID(), edgeID)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for broadcast")
+	}
+
+	_, err = svc.Broadcast(context.TODO(), &service.EdgeSelector{Labels: "site in fra1"}, svc.NewMessage([]byte("rollout")))
+	require.Error(t, err)
+
+	// labels removed with the offline edge
+	edgeID := edges[2].EdgeID()
+	require.NoError(t, edges[2].Close())
+	require.Eventually(t, func() bool {
+		got, err := r.GetEdgeLabels(edgeID)
+		return err == nil && len(got) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+// UNIT-EXCH-030: Only the picked half-open instance is probed, picking doesn't change the states
+func TestExchangeBreakerProbe(t *testing.T) {
+	bs := newBreakers(&config.Breaker{Enable: true, MinRequests: 1, ErrorRate: 50, Cooldown: 1})
+	bs.cooldown = 50 * time.Millisecond
+	for _, serviceID := range []uint64{1, 2} {
+		require.True(t, bs.Allow(serviceID))
+		bs.Record(serviceID, io.EOF, time.Millisecond)
+		assert.False(t, bs.Ready(serviceID))
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	// candidates checked again and again are still ready
+	for i := 0; i < 3; i++ {
+		assert.True(t, bs.Ready(1))
+		assert.True(t, bs.Ready(2))
+	}
+	// the probe of the picked one is claimed once
+	require.True(t, bs.Allow(1))
+	assert.False(t, bs.Allow(1))
+	assert.False(t, bs.Ready(1))
+	// the other one still has its probe
+	assert.True(t, bs.Ready(2))
+
+	bs.Record(1, nil, time.Millisecond)
+	assert.True(t, bs.Ready(1))
+	assert.True(t, bs.Allow(1))
+	assert.True(t, bs.Allow(1))
+}
+
+// UNIT-EXCH-031: In-flight slots of topics named by Edges dropped once the producings are done
+func TestExchangeTopicSlots(t *testing.T) {
+	h := newHarness(t)
+	ex := h.ex.(*exchange)
+
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+	for i := 0; i < 32; i++ {
+		err := e.Publish(context.TODO(), "random-"+strconv.Itoa(i), e.NewMessage([]byte("x")))
+		require.Error(t, err)
+	}
+	ex.topicMtx.Lock()
+	assert.Empty(t, ex.topics)
+	ex.topicMtx.Unlock()
+}
+
+// UNIT-EXCH-032: Rejected calls take no tokens, and topic limiters kept only if enabled and not idle
+func TestExchangeTopicLimits(t *testing.T) {
+	l := newLimiter(apis.RateLimit{Messages: 2, Bytes: 10})
+	// in debt of the bytes
+	require.True(t, l.allow(kindMessage, 15))
+	// rejected by bytes, the messages are not taken
+	for i := 0; i < 3; i++ {
+		assert.False(t, l.allow(kindMessage, 1))
+	}
+	assert.True(t, l.messages.Take(1))
+
+	count := func(ex *exchange) int {
+		n := 0
+		ex.topicLimits.Range(func(_, _ interface{}) bool {
+			n++
+			return true
+		})
+		return n
+	}
+	// disabled
+	ex, err := newExchange(&config.Configuration{}, nil)
+	require.NoError(t, err)
+	defer ex.Close()
+	for i := 0; i < 8; i++ {
+		assert.True(t, ex.allowTopic("random-"+strconv.Itoa(i), 1))
+	}
+	assert.Zero(t, count(ex))
+
+	conf := &config.Configuration{}
+	conf.Exchange.RateLimit.Topic = config.TopicLimit{Messages: 1000}
+	ex, err = newExchange(conf, nil)
+	require.NoError(t, err)
+	defer ex.Close()
+	for i := 0; i < 8; i++ {
+		assert.True(t, ex.allowTopic("random-"+strconv.Itoa(i), 1))
+	}
+	assert.Equal(t, 8, count(ex))
+	// refilled in a few milliseconds
+	time.Sleep(20 * time.Millisecond)
+	ex.dropIdleTopicLimits()
+	assert.Zero(t, count(ex))
+}
+
+// UNIT-EXCH-033: Groups subscribed by overlapping wildcards each get a copy, and the failed group doesn't fail the delivered
+func TestExchangeForwardMessageToWildcardGroups(t *testing.T) {
+	newHarness(t)
+
+	var failing atomic.Bool
+	receive := func(group, topic string, fail bool) chan string {
+		svc, err := service.NewService(svcDial(),
+			service.OptionServiceName(group),
+			service.OptionServiceReceiveTopics([]string{topic}),
+			service.OptionServiceGroup(group),
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() { svc.Close() })
+		received := make(chan string, 4)
+		go func() {
+			for {
+				msg, err := svc.Receive(context.TODO())
+				if err != nil {
+					return
+				}
+				received <- msg.Topic()
+				if fail && failing.Load() {
+					msg.Error(errors.New("disk full"))
+					continue
+				}
+				msg.Done()
+			}
+		}()
+		return received
+	}
+	audit := receive("audit", "alarm/#", true)
+	analytics := receive("analytics", "alarm/+", false)
+	time.Sleep(20 * time.Millisecond)
+
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+
+	expect := func(received chan string) {
+		select {
+		case topic := <-received:
+			assert.Equal(t, "alarm/fire", topic)
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out")
+		}
+	}
+	require.NoError(t, e.Publish(context.TODO(), "alarm/fire", e.NewMessage([]byte("1"))))
+	expect(audit)
+	expect(analytics)
+
+	failing.Store(true)
+	require.NoError(t, e.Publish(context.TODO(), "alarm/fire", e.NewMessage([]byte("2"))))
+	expect(audit)
+	expect(analytics)
+}
+
+// UNIT-EXCH-034: RPCs and messages from or to a draining Edge refused, and served again after undrained
+func TestExchangeDrainingEdge(t *testing.T) {
+	h := newHarness(t)
+
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("draining-svc"))
+	require.NoError(t, err)
+	defer svc.Close()
+	require.NoError(t, svc.Register(context.TODO(), "echo", func(_ context.Context, req geminio.Request, resp geminio.Response) {
+		resp.SetData(req.Data())
+	}))
+	time.Sleep(20 * time.Millisecond)
+
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+	require.NoError(t, e.Register(context.TODO(), "greet", func(_ context.Context, req geminio.Request, resp geminio.Response) {
+		resp.SetData([]byte("hello"))
+	}))
+	received := make(chan []byte, 1)
+	go func() {
+		for {
+			msg, err := e.Receive(context.TODO())
+			if err != nil {
+				return
+			}
+			received <- msg.Data()
+			msg.Done()
+		}
+	}()
+	time.Sleep(20 * time.Millisecond)
+	edgeID := e.EdgeID()
+
+	undrain := h.ex.DrainEdge(edgeID)
+	_, err = e.Call(context.TODO(), "echo", e.NewRequest([]byte("ping")))
+	require.Error(t, err)
+	assert.Equal(t, apis.ErrEdgeDraining.Error(), err.Error())
+	_, err = svc.Call(context.TODO(), edgeID, "greet", svc.NewRequest(nil))
+	require.Error(t, err)
+	assert.Equal(t, apis.ErrEdgeDraining.Error(), err.Error())
+	err = svc.Publish(context.TODO(), edgeID, svc.NewMessage([]byte("push")))
+	require.Error(t, err)
+	assert.Equal(t, apis.ErrEdgeDraining.Error(), err.Error())
+	assert.Equal(t, 0, h.ex.EdgeInflight(edgeID))
+
+	undrain()
+	rsp, err := e.Call(context.TODO(), "echo", e.NewRequest([]byte("ping")))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ping"), rsp.Data())
+	_, err = svc.Call(context.TODO(), edgeID, "greet", svc.NewRequest(nil))
+	require.NoError(t, err)
+	require.NoError(t, svc.Publish(context.TODO(), edgeID, svc.NewMessage([]byte("push"))))
+	select {
+	case data := <-received:
+		assert.Equal(t, []byte("push"), data)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out")
+	}
+}
+
+// UNIT-EXCH-035: Raw bytes to an offline Service or Edge fail the next write instead of dropped silently
+func TestExchangeForwardRawOffline(t *testing.T) {
+	newHarness(t)
+
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("raw-svc"))
+	require.NoError(t, err)
+	defer svc.Close()
+	time.Sleep(20 * time.Millisecond)
+
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+
+	_, err = e.WriteRaw("nowhere-svc", []byte("lost"))
+	require.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+	_, err = e.WriteRaw("nowhere-svc", []byte("lost"))
+	require.Error(t, err)
+	assert.Equal(t, apis.ErrServiceNotOnline.Error(), err.Error())
+
+	_, err = svc.WriteRaw(e.EdgeID()+1, []byte("lost"))
+	require.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+	_, err = svc.WriteRaw(e.EdgeID()+1, []byte("lost"))
+	require.Error(t, err)
+	assert.Equal(t, apis.ErrEdgeNotOnline.Error(), err.Error())
+
+	// the online ones are not affected
+	_, err = svc.WriteRaw(e.EdgeID(), []byte("ack"))
+	require.NoError(t, err)
+	name, data, err := e.ReadRaw()
+	require.NoError(t, err)
+	assert.Equal(t, "raw-svc", name)
+	assert.Equal(t, []byte("ack"), data)
+}
+
+// UNIT-EXCH-036: Topics subscribed by an Edge limited in count and length
+func TestExchangeSubscribeLimits(t *testing.T) {
+	newHarness(t, func(conf *config.Configuration) {
+		conf.Edgebound.Subscription = config.Subscription{MaxTopics: 2, MaxTopicLength: 8}
+	})
+
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+
+	err = e.Subscribe(context.TODO(), "too-long-topic")
+	require.Error(t, err)
+	assert.Equal(t, apis.ErrTopicTooLong.Error(), err.Error())
+
+	require.NoError(t, e.Subscribe(context.TODO(), "news", "alerts"))
+	// subscribed again doesn't count
+	require.NoError(t, e.Subscribe(context.TODO(), "news"))
+	err = e.Subscribe(context.TODO(), "sports")
+	require.Error(t, err)
+	assert.Equal(t, apis.ErrTooManyTopics.Error(), err.Error())
+
+	// unsubscribed ones free the room
+	require.NoError(t, e.Unsubscribe(context.TODO(), "alerts"))
+	require.NoError(t, e.Subscribe(context.TODO(), "sports"))
+}
+
+// UNIT-EXCH-037: HMAC signatures out of the max skew rejected, and renewed by the Edge at every reconnect
+func TestExchangeHMACReconnect(t *testing.T) {
+	secret := []byte("secret")
+	h := newHarness(t, func(conf *config.Configuration) {
+		conf.Edgebound.Auth = config.EdgeAuth{Enable: true, Mode: edgebound.AuthModeHMAC, Secret: string(secret), MaxSkew: 1}
+	})
+
+	onlines := make(chan uint64, 2)
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("hmac-svc"))
+	require.NoError(t, err)
+	defer svc.Close()
+	require.NoError(t, svc.RegisterEdgeOnline(context.TODO(), func(edgeID uint64, _ []byte, _ net.Addr) error {
+		onlines <- edgeID
+		return nil
+	}))
+	time.Sleep(20 * time.Millisecond)
+
+	meta := []byte("device-1")
+	stale := apis.EncodeCredential(meta, apis.SignMeta(secret, meta, time.Now().Add(-time.Minute)))
+	_, err = edge.NewNoRetryEdge(edgeDial(), edge.OptionEdgeMeta(stale))
+	require.Error(t, err)
+	ae, ok := apis.ParseAuthError(err)
+	require.True(t, ok, err.Error())
+	assert.Equal(t, apis.AuthExpired, ae.Code)
+
+	e, err := edge.NewEdge(edgeDial(), edge.OptionEdgeMeta(meta), edge.OptionEdgeHMAC(secret))
+	require.NoError(t, err)
+	defer e.Close()
+	edgeID := <-onlines
+
+	// the first signature is out of the skew by the reconnect
+	time.Sleep(2100 * time.Millisecond)
+	require.NoError(t, h.eb.(apis.Edgebound).DelEdgeByID(edgeID))
+	select {
+	case <-onlines:
+	case <-time.After(6 * time.Second):
+		t.Fatal("edge not online again after reconnected")
+	}
+}
+
+// UNIT-EXCH-038: Edge to edge allowed by the policy service cached within the ttl, denials asked every time
+func TestExchangeEdgeToEdgeCache(t *testing.T) {
+	newHarness(t, func(conf *config.Configuration) {
+		conf.Exchange.EdgeToEdgeCacheTTL = 200
+	})
+
+	e1, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e1.Close()
+	e2, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e2.Close()
+	require.NoError(t, e2.Register(context.TODO(), "echo", func(_ context.Context, req geminio.Request, resp geminio.Response) {
+		resp.SetData(req.Data())
+	}))
+
+	var asked atomic.Int32
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("policy"))
+	require.NoError(t, err)
+	defer svc.Close()
+	require.NoError(t, svc.RegisterEdgeToEdge(context.TODO(), func(srcEdgeID, dstEdgeID uint64) error {
+		asked.Add(1)
+		if srcEdgeID == e1.EdgeID() && dstEdgeID == e2.EdgeID() {
+			return nil
+		}
+		return errors.New("denied")
+	}))
+	time.Sleep(20 * time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		_, err = e1.CallEdge(context.TODO(), e2.EdgeID(), "echo", e1.NewRequest([]byte("ping")))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), asked.Load())
+	for i := 0; i < 2; i++ {
+		_, err = e2.CallEdge(context.TODO(), e1.EdgeID(), "echo", e2.NewRequest([]byte("ping")))
+		require.Error(t, err)
+	}
+	assert.Equal(t, int32(3), asked.Load())
+
+	// asked again after expired
+	time.Sleep(250 * time.Millisecond)
+	_, err = e1.CallEdge(context.TODO(), e2.EdgeID(), "echo", e1.NewRequest([]byte("ping")))
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), asked.Load())
+}
+
+// UNIT-EXCH-039: Message from Service to an offline Edge fails alone, the later ones still forwarded
+func TestExchangeForwardMessageAfterOffline(t *testing.T) {
+	newHarness(t)
+
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("msg-svc"))
+	require.NoError(t, err)
+	defer svc.Close()
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+	time.Sleep(20 * time.Millisecond)
+
+	err = svc.Publish(context.TODO(), 10086, svc.NewMessage([]byte("lost")))
+	require.Error(t, err)
+	assert.Equal(t, apis.ErrEdgeNotOnline.Error(), err.Error())
+
+	received := make(chan geminio.Message, 1)
+	go func() {
+		msg, err := e.Receive(context.TODO())
+		if err != nil {
+			return
+		}
+		msg.Done()
+		received <- msg
+	}()
+	// the receiving loop of frontier used to quit at the offline edge
+	ctx, cancel := context.WithTimeout(context.TODO(), 3*time.Second)
+	defer cancel()
+	require.NoError(t, svc.Publish(ctx, e.EdgeID(), svc.NewMessage([]byte("hello"))))
+	select {
+	case msg := <-received:
+		assert.Equal(t, []byte("hello"), msg.Data())
+	case <-time.After(3 * time.Second):
+		t.Fatal("message after the offline edge not forwarded")
+	}
+}
+
+// UNIT-EXCH-040: RPCs and messages over a stream between old sdks keep the custom as is,
+// and the edge accepting the stream told the caller by the stream meta
+func TestExchangeStreamCallerOldSDK(t *testing.T) {
+	newHarness(t)
+
+	// a service and an edge don't know envelopes
+	meta, _ := json.Marshal(&apis.Meta{Service: "old-stream-svc"})
+	sopts := client.NewEndOptions()
+	sopts.SetMeta(meta)
+	oldSvc, err := client.NewEndWithDialer(client.Dialer(svcDial()), sopts)
+	require.NoError(t, err)
+	defer oldSvc.Close()
+	oldEdge, err := client.NewEndWithDialer(client.Dialer(edgeDial()), client.NewEndOptions())
+	require.NoError(t, err)
+	defer oldEdge.Close()
+	time.Sleep(20 * time.Millisecond)
+
+	customs := make(chan []byte, 2)
+	go func() {
+		st, err := oldSvc.AcceptStream()
+		if err != nil {
+			return
+		}
+		st.Register(context.TODO(), "echo", func(_ context.Context, req geminio.Request, rsp geminio.Response) {
+			customs <- req.Custom()
+		})
+		msg, err := st.Receive(context.TODO())
+		if err != nil {
+			return
+		}
+		customs <- msg.Custom()
+		msg.Done()
+	}()
+	opt := options.OpenStream()
+	opt.SetPeer("old-stream-svc")
+	st, err := oldEdge.OpenStream(opt)
+	require.NoError(t, err)
+	defer st.Close()
+	time.Sleep(20 * time.Millisecond)
+
+	req := st.NewRequest([]byte("ping"))
+	req.SetCustom([]byte("rpc"))
+	_, err = st.Call(context.TODO(), "echo", req)
+	require.NoError(t, err)
+	msg := st.NewMessage([]byte("hello"))
+	msg.SetCustom([]byte("msg"))
+	require.NoError(t, st.Publish(context.TODO(), msg))
+	for _, custom := range []string{"rpc", "msg"} {
+		select {
+		case got := <-customs:
+			assert.Equal(t, []byte(custom), got)
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out waiting for the custom")
+		}
+	}
+
+	// a service opens a stream to an edge
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("caller-svc"))
+	require.NoError(t, err)
+	defer svc.Close()
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+	type caller struct {
+		clientID, streamID uint64
+		custom             string
+	}
+	callers := make(chan caller, 1)
+	go func() {
+		st, err := e.AcceptStream()
+		if err != nil {
+			return
+		}
+		st.Register(context.TODO(), "who", func(_ context.Context, req geminio.Request, rsp geminio.Response) {
+			callers <- caller{req.ClientID(), req.StreamID(), string(req.Custom())}
+		})
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	sst, err := svc.OpenStream(context.TODO(), e.EdgeID())
+	require.NoError(t, err)
+	defer sst.Close()
+	time.Sleep(20 * time.Millisecond)
+	req = sst.NewRequest([]byte("ping"))
+	req.SetCustom([]byte("rpc"))
+	_, err = sst.Call(context.TODO(), "who", req)
+	require.NoError(t, err)
+	select {
+	case got := <-callers:
+		assert.Equal(t, sst.ClientID(), got.clientID)
+		assert.Equal(t, sst.StreamID(), got.streamID)
+		assert.Equal(t, "rpc", got.custom)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for the caller")
+	}
+}
+
+// UNIT-EXCH-041: Streams opened from or to a draining Edge refused with the typed error
+func TestExchangeStreamDrainingEdge(t *testing.T) {
+	h := newHarness(t)
+
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("draining-svc"))
+	require.NoError(t, err)
+	defer svc.Close()
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+	time.Sleep(20 * time.Millisecond)
+
+	undrain := h.ex.DrainEdge(e.EdgeID())
+	_, err = svc.OpenStream(context.TODO(), e.EdgeID())
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, apis.ErrEdgeDraining), err.Error())
+	_, err = e.OpenStream("draining-svc")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, apis.ErrEdgeDraining), err.Error())
+
+	undrain()
+	go func() {
+		if st, err := e.AcceptStream(); err == nil {
+			st.Close()
+		}
+	}()
+	st, err := svc.OpenStream(context.TODO(), e.EdgeID())
+	require.NoError(t, err)
+	st.Close()
+}
+
+// UNIT-EXCH-042: Queued publish to an online Edge rejected or timed out returned to Service, not queued
+func TestExchangeOutboxOnlineFailed(t *testing.T) {
+	h := newHarness(t, func(conf *config.Configuration) {
+		conf.Exchange.Outbox = config.Outbox{Enable: true, MaxDepth: 3}
+		conf.Exchange.Timeout = config.Timeout{Default: 200}
+	})
+	ex := h.ex.(*exchange)
+
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("outbox"))
+	require.NoError(t, err)
+	defer svc.Close()
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+	time.Sleep(20 * time.Millisecond)
+	edgeID := e.EdgeID()
+
+	go func() {
+		for {
+			msg, err := e.Receive(context.TODO())
+			if err != nil {
+				return
+			}
+			// the timed out one is received but not acked
+			if string(msg.Data()) == "reject" {
+				msg.Error(errors.New("rejected"))
+			}
+		}
+	}()
+	for _, data := range []string{"reject", "timeout"} {
+		err = svc.PublishQueued(context.TODO(), edgeID, svc.NewMessage([]byte(data)))
+		require.Error(t, err, data)
+		assert.True(t, ex.outbox.empty(edgeID), data)
+	}
+}
+
+// UNIT-EXCH-043: RPC and Message from Edge come to the peer Edge with the source edgeID,
+// and to the peer Edge of old sdk with the custom as is
+func TestExchangeEdgeToEdgeSource(t *testing.T) {
+	newHarness(t)
+
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("policy"))
+	require.NoError(t, err)
+	defer svc.Close()
+	require.NoError(t, svc.RegisterEdgeToEdge(context.TODO(), func(srcEdgeID, dstEdgeID uint64) error {
+		return nil
+	}))
+	e1, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e1.Close()
+	e2, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e2.Close()
+	// an edge doesn't know envelopes
+	oldEdge, err := client.NewEndWithDialer(client.Dialer(edgeDial()), client.NewEndOptions())
+	require.NoError(t, err)
+	defer oldEdge.Close()
+
+	type source struct {
+		clientID uint64
+		custom   string
+	}
+	sources := make(chan source, 4)
+	register := func(end interface {
+		Register(context.Context, string, geminio.RPC) error
+	}) {
+		require.NoError(t, end.Register(context.TODO(), "echo", func(_ context.Context, req geminio.Request, rsp geminio.Response) {
+			sources <- source{req.ClientID(), string(req.Custom())}
+		}))
+	}
+	register(e2)
+	register(oldEdge)
+	time.Sleep(20 * time.Millisecond)
+
+	receive := func(end interface {
+		Receive(context.Context) (geminio.Message, error)
+	}) {
+		msg, err := end.Receive(context.TODO())
+		if err != nil {
+			return
+		}
+		msg.Done()
+		sources <- source{msg.ClientID(), string(msg.Custom())}
+	}
+	expect := func(clientID uint64, custom string) {
+		t.Helper()
+		select {
+		case got := <-sources:
+			assert.Equal(t, source{clientID, custom}, got)
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out waiting for the source")
+		}
+	}
+
+	// the edgeID set by the source edge is overridden
+	req := e1.NewRequest([]byte("ping"))
+	req.SetCustom([]byte("rpc"))
+	apis.SetHeaders(req, apis.Headers{apis.HeaderEdgeID: "10086"})
+	_, err = e1.CallEdge(context.TODO(), e2.EdgeID(), "echo", req)
+	require.NoError(t, err)
+	expect(e1.EdgeID(), "rpc")
+	go receive(e2)
+	msg := e1.NewMessage([]byte("hi"))
+	msg.SetCustom([]byte("msg"))
+	require.NoError(t, e1.PublishEdge(context.TODO(), e2.EdgeID(), "hello", msg))
+	expect(e1.EdgeID(), "msg")
+
+	// the old sdk gets the custom byte identical
+	req = e1.NewRequest([]byte("ping"))
+	req.SetCustom([]byte("rpc"))
+	_, err = e1.CallEdge(context.TODO(), oldEdge.ClientID(), "echo", req)
+	require.NoError(t, err)
+	expect(oldEdge.ClientID(), "rpc")
+	go receive(oldEdge)
+	msg = e1.NewMessage([]byte("hi"))
+	msg.SetCustom([]byte("msg"))
+	require.NoError(t, e1.PublishEdge(context.TODO(), oldEdge.ClientID(), "hello", msg))
+	expect(oldEdge.ClientID(), "msg")
+}
+
+// UNIT-EXCH-044: Edge to edge asked every time without the cache ttl
+func TestExchangeEdgeToEdgeNoCache(t *testing.T) {
+	newHarness(t)
+
+	e1, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e1.Close()
+	e2, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e2.Close()
+	require.NoError(t, e2.Register(context.TODO(), "echo", func(_ context.Context, req geminio.Request, resp geminio.Response) {
+		resp.SetData(req.Data())
+	}))
+
+	var asked atomic.Int32
+	svc, err := service.NewService(svcDial(), service.OptionServiceName("policy"))
+	require.NoError(t, err)
+	defer svc.Close()
+	require.NoError(t, svc.RegisterEdgeToEdge(context.TODO(), func(srcEdgeID, dstEdgeID uint64) error {
+		asked.Add(1)
+		return nil
+	}))
+	time.Sleep(20 * time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		_, err = e1.CallEdge(context.TODO(), e2.EdgeID(), "echo", e1.NewRequest([]byte("ping")))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(3), asked.Load())
+}
+
+// UNIT-EXCH-045: Messages from an Edge produced in order across topics
+func TestExchangeEdgeMessageOrder(t *testing.T) {
+	newHarness(t)
+
+	events := make(chan string, 2)
+	slow, err := service.NewService(svcDial(), service.OptionServiceName("slow-svc"), service.OptionServiceReceiveTopics([]string{"slow-topic"}))
+	require.NoError(t, err)
+	defer slow.Close()
+	go func() {
+		msg, err := slow.Receive(context.TODO())
+		if err != nil {
+			return
+		}
+		time.Sleep(200 * time.Millisecond)
+		events <- "slow"
+		msg.Done()
+	}()
+	fast, err := service.NewService(svcDial(), service.OptionServiceName("fast-svc"), service.OptionServiceReceiveTopics([]string{"fast-topic"}))
+	require.NoError(t, err)
+	defer fast.Close()
+	go func() {
+		msg, err := fast.Receive(context.TODO())
+		if err != nil {
+			return
+		}
+		events <- "fast"
+		msg.Done()
+	}()
+	e, err := edge.NewEdge(edgeDial())
+	require.NoError(t, err)
+	defer e.Close()
+	time.Sleep(20 * time.Millisecond)
+
+	// the second message waits for the first one though to another topic
+	ch := make(chan *geminio.Publish, 2)
+	_, err = e.PublishAsync(context.TODO(), "slow-topic", e.NewMessage([]byte("1")), ch)
+	require.NoError(t, err)
+	_, err = e.PublishAsync(context.TODO(), "fast-topic", e.NewMessage([]byte("2")), ch)
+	require.NoError(t, err)
+	for _, want := range []string{"slow", "fast"} {
+		select {
+		case got := <-events:
+			assert.Equal(t, want, got)
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out waiting for the message")
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package exchange
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jumboframes/armorigo/synchub"
+	"github.com/singchia/frontier/pkg/frontier/apis"
+)
+
+// attempts returns how many service instances to try for the method
+func (ex *exchange) attempts(method string, count int) int {
+	conf := ex.conf.Exchange.Failover
+	if conf.MaxAttempts <= 1 {
+		return 1
+	}
+	for _, elem := range conf.Methods {
+		if elem == "*" || elem == method {
+			if conf.MaxAttempts < count {
+				return conf.MaxAttempts
+			}
+			return count
+		}
+	}
+	return 1
+}
+
+// backoff before the attempt, the first failover waits Backoff and then doubles
+func (ex *exchange) backoff(attempt int) time.Duration {
+	backoff := ex.conf.Exchange.Failover.Backoff
+	if backoff <= 0 || attempt <= 0 {
+		return 0
+	}
+	return time.Duration(backoff) * time.Millisecond << (attempt - 1)
+}
+
+// failoverable tells whether the instance failed rather than the method, errors returned
+// by the service itself are delivered to the edge as they are
+func failoverable(err error) bool {
+	if err == io.EOF ||
+		errors.Is(err, synchub.ErrSyncTimeout) ||
+		errors.Is(err, synchub.ErrSyncHubClosed) ||
+		errors.Is(err, synchub.ErrSyncHubForceClosed) {
+		return true
+	}
+	return strings.Contains(err.Error(), apis.ErrStrUseOfClosedConnection)
+}
@@ -3,6 +3,7 @@ package exchange
 import (
 	"context"
 	"encoding/binary"
+	"fmt"
 	"io"
 	"time"
 
@@ -14,8 +15,8 @@ import (
 )
 
 func (ex *exchange) ForwardToEdge(meta *apis.Meta, end geminio.End) {
-	// raw, TODO now we didn't supply raw RW to user
-	// ex.forwardRawToEdge(end)
+	// raw
+	ex.forwardRawToEdge(meta.Service, end)
 
 	// message
 	ex.forwardMessageToEdge(end)
@@ -23,22 +24,40 @@ func (ex *exchange) ForwardToEdge(meta *apis.Meta, end geminio.End) {
 	ex.forwardRPCToEdge(end)
 }
 
-func (ex *exchange) forwardRawToEdge(end geminio.End) {
-	//drop the io, actually we won't be here
-	go func() {
-		klog.V(3).Infof("exchange forward raw to edge, discard for now, serviceID: %d", end.ClientID())
-		io.Copy(io.Discard, end)
-	}()
-}
-
 func (ex *exchange) forwardRPCToEdge(end geminio.End) {
 	// we hijack all rpcs and forward them to edge
 	end.Hijack(func(ctx context.Context, method string, r1 geminio.Request, r2 geminio.Response) {
 		serviceID := end.ClientID()
-		// get target edgeID
-		custom := r1.Custom()
-		edgeID := binary.BigEndian.Uint64(custom[len(custom)-8:])
-		r1.SetCustom(custom[:len(custom)-8])
+		if !ex.allowService(serviceID, kindRPC, len(r1.Data())) {
+			r2.SetError(apis.ErrRateLimited)
+			return
+		}
+		// rpcs served by frontier itself
+		switch method {
+		case apis.RPCMulticast:
+			ex.multicast(ctx, serviceID, r1, r2)
+			return
+		case apis.RPCPublishQueued:
+			ex.publishQueued(ctx, serviceID, r1, r2)
+			return
+		case apis.RPCEdgeSupports:
+			ex.edgeSupports(ctx, serviceID, r1, r2)
+			return
+		case apis.RPCSubscribe:
+			ex.serviceSubscribe(end, r1, r2, true)
+			return
+		case apis.RPCUnsubscribe:
+			ex.serviceSubscribe(end, r1, r2, false)
+			return
+		}
+		// get target edgeID, from the envelope or the old tail
+		custom, headers, _ := apis.DecodeServiceCustom(r1.Custom())
+		edgeID, ok := headers.Uint64(apis.HeaderEdgeID)
+		if !ok {
+			klog.V(1).Infof("service forward rpc, serviceID: %d, call without edgeID", serviceID)
+			r2.SetError(apis.ErrIllegalEdgeID)
+			return
+		}
 
 		// get edge
 		edge := ex.Edgebound.GetEdgeByID(edgeID)
@@ -47,13 +66,26 @@ func (ex *exchange) forwardRPCToEdge(end geminio.End) {
 			r2.SetError(apis.ErrEdgeNotOnline)
 			return
 		}
+		// fail fast rather than wait on the edge
+		if !ex.edgeRPCRegistered(edgeID, method) {
+			klog.V(2).Infof("service forward rpc, serviceID: %d, call edgeID: %d, method: %s not registered", serviceID, edgeID, method)
+			r2.SetError(apis.ErrRPCNotRegistered)
+			return
+		}
+		done, ok := ex.inflight(edgeID)
+		if !ok {
+			klog.V(2).Infof("service forward rpc, serviceID: %d, call edgeID: %d, is draining", serviceID, edgeID)
+			r2.SetError(apis.ErrEdgeDraining)
+			return
+		}
+		defer done()
 		// call edge
 		ropt := options.NewRequest()
-		ropt.SetCustom(r1.Custom())
+		ropt.SetCustom(ex.edgeCustom(ctx, custom, headers))
 		r3 := edge.NewRequest(r1.Data(), ropt)
 		// call option
 		copt := options.Call()
-		copt.SetTimeout(30 * time.Second)
+		copt.SetTimeout(ex.rpcTimeout(ctx, method))
 		r4, err := edge.Call(ctx, method, r3, copt)
 		if err != nil {
 			klog.V(2).Infof("service forward rpc, serviceID: %d, call edgeID: %d, err: %s", serviceID, edgeID, err)
@@ -78,53 +110,105 @@ func (ex *exchange) forwardRPCToEdge(end geminio.End) {
 
 func (ex *exchange) forwardMessageToEdge(end geminio.End) {
 	serviceID := end.ClientID()
+	// messages to the same edge are published in order and pipelined, a slow edge won't block others
+	dp := newDispatcher[uint64](ex.backlog(), ex.edgeInflight())
 	go func() {
+		defer dp.close()
+
 		for {
 			msg, err := end.Receive(context.TODO())
 			if err != nil {
 				if err == io.EOF {
 					klog.V(2).Infof("service forward message, serviceID: %d, receive EOF", serviceID)
+					ex.serviceLimits.Delete(serviceID)
+					ex.breakers.del(serviceID)
 					return
 				}
 				klog.Errorf("service forward message, serviceID: %d, receive err: %s", serviceID, err)
 				continue
 			}
+			if !ex.allowService(serviceID, kindMessage, len(msg.Data())) {
+				msg.Error(apis.ErrRateLimited)
+				continue
+			}
 			klog.V(2).Infof("service forward message, receive msg: %s from: %d", string(msg.Data()), end.ClientID())
-			// get target edgeID
-			custom := msg.Custom()
-			edgeID := binary.BigEndian.Uint64(custom[len(custom)-8:])
-			msg.SetCustom(custom[:len(custom)-8])
-
-			// get edge
-			edge := ex.Edgebound.GetEdgeByID(edgeID)
-			if edge == nil {
-				klog.V(1).Infof("service forward message, serviceID: %d, the edge: %d is not online", serviceID, edgeID)
-				msg.Error(apis.ErrEdgeNotOnline)
-				return
+			// get target edgeID, from the envelope or the old tail
+			custom, headers, _ := apis.DecodeServiceCustom(msg.Custom())
+			edgeID, ok := headers.Uint64(apis.HeaderEdgeID)
+			if !ok {
+				klog.V(1).Infof("service forward message, serviceID: %d, publish without edgeID", serviceID)
+				msg.Error(apis.ErrIllegalEdgeID)
+				continue
 			}
-			// publish in sync, TODO publish in async
-			mopt := options.NewMessage()
-			mopt.SetCustom(msg.Custom())
-			mopt.SetTopic(msg.Topic())
-			mopt.SetCnss(msg.Cnss())
-			newmsg := edge.NewMessage(msg.Data(), mopt)
-			// publish option
-			popt := options.Publish()
-			popt.SetTimeout(30 * time.Second)
-			err = edge.Publish(context.TODO(), newmsg, popt)
-			if err != nil {
-				klog.V(2).Infof("service forward message, serviceID: %d, publish edge: %d err: %s", serviceID, edgeID, err)
-				msg.Error(err)
-				return
+			msg.SetCustom(ex.edgeCustom(context.TODO(), custom, headers))
+
+			ok = dp.dispatch(edgeID, func(l *lane) {
+				ex.publishToEdge(serviceID, edgeID, msg, l)
+			})
+			if !ok {
+				klog.V(1).Infof("service forward message, serviceID: %d, the edge: %d backlog full", serviceID, edgeID)
+				msg.Error(apis.ErrBacklogFull)
 			}
-			msg.Done()
 		}
 	}()
 }
 
+// publishToEdge returns after the message is sent, and acks the origin when the edge acks
+func (ex *exchange) publishToEdge(serviceID, edgeID uint64, msg geminio.Message, l *lane) {
+	// get edge
+	edge := ex.Edgebound.GetEdgeByID(edgeID)
+	if edge == nil {
+		klog.V(1).Infof("service forward message, serviceID: %d, the edge: %d is not online", serviceID, edgeID)
+		msg.Error(apis.ErrEdgeNotOnline)
+		return
+	}
+	done, ok := ex.inflight(edgeID)
+	if !ok {
+		klog.V(2).Infof("service forward message, serviceID: %d, the edge: %d is draining", serviceID, edgeID)
+		msg.Error(apis.ErrEdgeDraining)
+		return
+	}
+	mopt := options.NewMessage()
+	mopt.SetCustom(msg.Custom())
+	mopt.SetTopic(msg.Topic())
+	mopt.SetCnss(msg.Cnss())
+	newmsg := edge.NewMessage(msg.Data(), mopt)
+	// publish option
+	popt := options.Publish()
+	popt.SetTimeout(ex.messageTimeout(context.TODO(), msg.Topic(), msg))
+
+	l.acquire()
+	pub, err := edge.PublishAsync(context.TODO(), newmsg, nil, popt)
+	if err != nil {
+		l.release()
+		done()
+		klog.V(2).Infof("service forward message, serviceID: %d, publish edge: %d err: %s", serviceID, edgeID, err)
+		msg.Error(err)
+		return
+	}
+	if pub == nil {
+		// at most once, no ack from edge
+		l.release()
+		done()
+		msg.Done()
+		return
+	}
+	go func() {
+		<-pub.Done
+		l.release()
+		done()
+		if pub.Error != nil {
+			klog.V(2).Infof("service forward message, serviceID: %d, publish edge: %d err: %s", serviceID, edgeID, pub.Error)
+			msg.Error(pub.Error)
+			return
+		}
+		msg.Done()
+	}()
+}
+
 func (ex *exchange) ForwardToService(end geminio.End) {
-	// raw, TODO now we didn't supply raw RW to user
-	// ex.forwardRawToService(end)
+	// raw
+	ex.forwardRawToService(end)
 
 	// message
 	ex.forwardMessageToService(end)
@@ -132,21 +216,39 @@ func (ex *exchange) ForwardToService(end geminio.End) {
 	ex.forwardRPCToService(end)
 }
 
-// raw io from edge, and forward to service
-func (ex *exchange) forwardRawToService(end geminio.End) {
-	//drop the io, actually we won't be here
-	go func() {
-		klog.V(3).Infof("exchange forward raw to service, discard for now, edgeID: %d", end.ClientID())
-		io.Copy(io.Discard, end)
-	}()
-}
-
 // rpc from edge, and forward to service
 func (ex *exchange) forwardRPCToService(end geminio.End) {
 	edgeID := end.ClientID()
 	addr := end.RemoteAddr()
 	// we hijack all rpcs and forward them to service
 	end.Hijack(func(ctx context.Context, method string, r1 geminio.Request, r2 geminio.Response) {
+		done, ok := ex.inflight(edgeID)
+		if !ok {
+			r2.SetError(apis.ErrEdgeDraining)
+			return
+		}
+		defer done()
+		if !ex.allowEdge(edgeID, kindRPC, len(r1.Data())) {
+			r2.SetError(apis.ErrRateLimited)
+			return
+		}
+		// rpcs served by frontier itself
+		switch method {
+		case apis.RPCSubscribe:
+			ex.edgeSubscribe(edgeID, r1, r2, true)
+			return
+		case apis.RPCUnsubscribe:
+			ex.edgeSubscribe(edgeID, r1, r2, false)
+			return
+		case apis.RPCUpdateMeta:
+			ex.edgeUpdateMeta(edgeID, addr, r1, r2)
+			return
+		}
+		// rpcs to peer edge
+		if dstEdgeID, peerMethod, ok := apis.ParseEdgeTarget(method); ok {
+			ex.forwardRPCToPeer(ctx, edgeID, dstEdgeID, peerMethod, r1, r2)
+			return
+		}
 		// get service
 		svcs, err := ex.Servicebound.GetServicesByRPC(method)
 		if err != nil {
@@ -154,31 +256,64 @@ func (ex *exchange) forwardRPCToService(end geminio.End) {
 			r2.SetError(err)
 			return
 		}
-		index := misc.Hash(ex.conf.Exchange.HashBy, len(svcs), edgeID, addr)
-		svc := svcs[index]
-		serviceID := svc.ClientID()
-		// we record the edgeID to service
-		tail := make([]byte, 8)
-		binary.BigEndian.PutUint64(tail, edgeID)
-		custom := r1.Custom()
-		if custom == nil {
-			custom = tail
-		} else {
-			custom = append(custom, tail...)
+		// skip the tripped instances
+		svcs = ex.breakers.ready(svcs)
+		if len(svcs) == 0 {
+			klog.V(2).Infof("exchange forward rpc to service, all instances tripped, method: %s, edgeID: %d", method, edgeID)
+			r2.SetError(apis.ErrCircuitOpen)
+			return
 		}
+		index := misc.Hash(ex.conf.Exchange.HashBy, misc.EndNodes(ex.conf.Exchange.HashBy, svcs), edgeID, addr)
+		// headers from the edge
+		custom, headers, _ := apis.DecodeEnvelope(r1.Custom())
 
-		// call
-		ropt := options.NewRequest()
-		ropt.SetCustom(custom)
-		r3 := svc.NewRequest(r1.Data(), ropt)
-		// call option
-		copt := options.Call()
-		copt.SetTimeout(30 * time.Second)
-		r4, err := svc.Call(ctx, method, r3, copt)
+		// try the hashed instance first, and then the next ones if the method opts in failover
+		attempts := ex.attempts(method, len(svcs))
+		var (
+			r4        geminio.Response
+			serviceID uint64
+			tried     int
+		)
+		for attempt := 0; attempt < attempts; attempt++ {
+			if attempt > 0 {
+				select {
+				case <-time.After(ex.backoff(attempt)):
+				case <-ctx.Done():
+				}
+				if ctx.Err() != nil {
+					break
+				}
+			}
+			svc := svcs[(index+attempt)%len(svcs)]
+			serviceID = svc.ClientID()
+			// the probe to the half-open instance is taken by another call
+			if !ex.breakers.allow(serviceID) {
+				err = apis.ErrCircuitOpen
+				continue
+			}
+			tried++
+			// call, we record the edgeID to service
+			ropt := options.NewRequest()
+			ropt.SetCustom(ex.serviceCustom(ctx, svc, custom, edgeID, headers))
+			r3 := svc.NewRequest(r1.Data(), ropt)
+			// call option
+			copt := options.Call()
+			copt.SetTimeout(ex.rpcTimeout(ctx, method))
+			start := time.Now()
+			r4, err = svc.Call(ctx, method, r3, copt)
+			ex.breakers.record(serviceID, err, time.Since(start))
+			if err == nil || !failoverable(err) {
+				break
+			}
+			klog.V(2).Infof("edge forward rpc to service, call service: %d err: %s, edgeID: %d, attempt: %d", serviceID, err, edgeID, tried)
+		}
 		if err != nil {
 			if err != apis.ErrRPCNotOnline {
 				klog.Errorf("edge forward rpc to service, call service: %d err: %s, edgeID: %d", serviceID, err, edgeID)
 			}
+			if tried > 1 && failoverable(err) {
+				err = fmt.Errorf("%w, attempts: %d, last err: %s", apis.ErrFailoverExceeded, tried, err)
+			}
 			r2.SetError(err)
 			return
 		}
@@ -192,31 +327,79 @@ func (ex *exchange) forwardRPCToService(end geminio.End) {
 // message from edge, and forward to topic owner
 func (ex *exchange) forwardMessageToService(end geminio.End) {
 	edgeID := end.ClientID()
+	// messages from the edge are produced in order, and receiving won't wait for producing
+	dp := newDispatcher[uint64](ex.backlog(), 0)
 	go func() {
+		defer dp.close()
+
 		for {
 			msg, err := end.Receive(context.TODO())
 			if err != nil {
 				if err == io.EOF {
 					klog.V(3).Infof("edge forward message, edgeID: %d, receive EOF", edgeID)
+					ex.edgeLimits.Delete(edgeID)
 					return
 				}
 				klog.Errorf("edge forward message, receive err: %s, edgeID: %d, ", err, edgeID)
 				continue
 			}
-			topic := msg.Topic()
-			// TODO separate async and sync produce
-			err = ex.MQM.Produce(topic, msg.Data(),
-				apis.WithOrigin(msg),
-				apis.WithEdgeID(edgeID),
-				apis.WithAddr(end.RemoteAddr()))
-			if err != nil {
-				if err != apis.ErrTopicNotOnline {
-					klog.Errorf("edge forward message, produce err: %s, edgeID: %d", err, edgeID)
-				}
-				msg.Error(err)
+			if !ex.allowEdge(edgeID, kindMessage, len(msg.Data())) {
+				msg.Error(apis.ErrRateLimited)
 				continue
 			}
-			msg.Done()
+			done, ok := ex.inflight(edgeID)
+			if !ok {
+				msg.Error(apis.ErrEdgeDraining)
+				continue
+			}
+			topic := msg.Topic()
+			ok = dp.dispatch(edgeID, func(_ *lane) {
+				defer done()
+				ex.produce(end, topic, msg)
+			})
+			if !ok {
+				done()
+				klog.V(1).Infof("edge forward message, edgeID: %d, topic: %s, backlog full", edgeID, topic)
+				msg.Error(apis.ErrBacklogFull)
+			}
 		}
 	}()
 }
+
+func (ex *exchange) produce(end geminio.End, topic string, msg geminio.Message) {
+	edgeID := end.ClientID()
+	// messages to peer edge
+	if dstEdgeID, peerTopic, ok := apis.ParseEdgeTarget(topic); ok {
+		err := ex.forwardMessageToPeer(edgeID, dstEdgeID, peerTopic, msg)
+		if err != nil {
+			msg.Error(err)
+			return
+		}
+		msg.Done()
+		return
+	}
+
+	if !ex.allowTopic(topic, len(msg.Data())) {
+		msg.Error(apis.ErrRateLimited)
+		return
+	}
+	opts := []apis.OptionProduce{
+		apis.WithOrigin(msg),
+		apis.WithEdgeID(edgeID),
+		apis.WithAddr(end.RemoteAddr()),
+	}
+	if ex.breakers != nil {
+		opts = append(opts, apis.WithBreaker(ex.breakers))
+	}
+	slots := ex.acquireTopic(topic)
+	err := ex.MQM.Produce(topic, msg.Data(), opts...)
+	ex.releaseTopic(topic, slots)
+	if err != nil {
+		if err != apis.ErrTopicNotOnline && err != apis.ErrCircuitOpen {
+			klog.Errorf("edge forward message, produce err: %s, edgeID: %d", err, edgeID)
+		}
+		msg.Error(err)
+		return
+	}
+	msg.Done()
+}
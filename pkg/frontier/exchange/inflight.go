@@ -0,0 +1,45 @@
+package exchange
+
+// inflight counts a forwarded rpc or message from or to the edge until done is called,
+// ok is false if the edge is draining
+func (ex *exchange) inflight(edgeID uint64) (done func(), ok bool) {
+	ex.inflightMtx.Lock()
+	defer ex.inflightMtx.Unlock()
+	if _, draining := ex.drainings[edgeID]; draining {
+		return nil, false
+	}
+	ex.inflights[edgeID]++
+
+	return func() {
+		ex.inflightMtx.Lock()
+		defer ex.inflightMtx.Unlock()
+		if ex.inflights[edgeID]--; ex.inflights[edgeID] <= 0 {
+			delete(ex.inflights, edgeID)
+		}
+	}, true
+}
+
+func (ex *exchange) EdgeInflight(edgeID uint64) int {
+	ex.inflightMtx.Lock()
+	defer ex.inflightMtx.Unlock()
+	return ex.inflights[edgeID]
+}
+
+func (ex *exchange) DrainEdge(edgeID uint64) (undrain func()) {
+	ex.inflightMtx.Lock()
+	ex.drainings[edgeID] = struct{}{}
+	ex.inflightMtx.Unlock()
+
+	return func() {
+		ex.inflightMtx.Lock()
+		delete(ex.drainings, edgeID)
+		ex.inflightMtx.Unlock()
+	}
+}
+
+func (ex *exchange) edgeDraining(edgeID uint64) bool {
+	ex.inflightMtx.Lock()
+	defer ex.inflightMtx.Unlock()
+	_, ok := ex.drainings[edgeID]
+	return ok
+}
@@ -0,0 +1,21 @@
+package exchange
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var rateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "frontier",
+	Subsystem: "exchange",
+	Name:      "rate_limited_total",
+	Help:      "Messages and rpcs rejected by rate limits.",
+}, []string{"scope", "kind"})
+
+var breakerTripped = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "frontier",
+	Subsystem: "exchange",
+	Name:      "breaker_tripped_total",
+	Help:      "Service instances tripped by the breaker.",
+})
+
+func init() {
+	prometheus.MustRegister(rateLimited, breakerTripped)
+}
@@ -0,0 +1,141 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/frontier/pkg/frontier/label"
+	"github.com/singchia/geminio"
+	"github.com/singchia/geminio/options"
+	"k8s.io/klog/v2"
+)
+
+// max publishings in flight for one multicast
+const multicastConcurrency = 128
+
+// multicast fans a message from service out to edges
+func (ex *exchange) multicast(ctx context.Context, serviceID uint64, r1 geminio.Request, r2 geminio.Response) {
+	mc := &apis.Multicast{}
+	err := json.Unmarshal(r1.Data(), mc)
+	if err != nil {
+		klog.Errorf("service multicast, json unmarshal err: %s, serviceID: %d", err, serviceID)
+		r2.SetError(err)
+		return
+	}
+
+	deliveries, err := ex.fanout(ctx, serviceID, mc)
+	if err != nil {
+		klog.Errorf("service multicast, select edges err: %s, serviceID: %d", err, serviceID)
+		r2.SetError(err)
+		return
+	}
+	data, err := json.Marshal(&apis.MulticastResult{Deliveries: deliveries})
+	if err != nil {
+		klog.Errorf("service multicast, json marshal err: %s, serviceID: %d", err, serviceID)
+		r2.SetError(err)
+		return
+	}
+	r2.SetData(data)
+}
+
+func (ex *exchange) fanout(ctx context.Context, serviceID uint64, mc *apis.Multicast) ([]*apis.Delivery, error) {
+	edgeIDs, edges, err := ex.selectEdges(mc)
+	if err != nil {
+		return nil, err
+	}
+	deliveries := make([]*apis.Delivery, len(edgeIDs))
+
+	sem := make(chan struct{}, multicastConcurrency)
+	wg := new(sync.WaitGroup)
+	for i, edgeID := range edgeIDs {
+		delivery := &apis.Delivery{EdgeID: edgeID}
+		deliveries[i] = delivery
+
+		edge := edges[i]
+		if edge == nil {
+			delivery.Error = apis.ErrEdgeNotOnline.Error()
+			continue
+		}
+		done, ok := ex.inflight(edgeID)
+		if !ok {
+			delivery.Error = apis.ErrEdgeDraining.Error()
+			continue
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				done()
+				<-sem
+				wg.Done()
+			}()
+			mopt := options.NewMessage()
+			mopt.SetCustom(mc.Custom)
+			mopt.SetTopic(mc.Topic)
+			newmsg := edge.NewMessage(mc.Data, mopt)
+			// publish option
+			popt := options.Publish()
+			popt.SetTimeout(ex.messageTimeout(ctx, mc.Topic, nil))
+			err := edge.Publish(ctx, newmsg, popt)
+			if err != nil {
+				klog.V(2).Infof("service multicast, serviceID: %d, publish edge: %d err: %s", serviceID, edge.ClientID(), err)
+				delivery.Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+	klog.V(3).Infof("service multicast, serviceID: %d, fan out to %d edges", serviceID, len(deliveries))
+	return deliveries, nil
+}
+
+// edges from edgebound carry their labels
+type labeled interface {
+	Labels() map[string]string
+}
+
+func edgeLabels(edge geminio.End) map[string]string {
+	if le, ok := edge.(labeled); ok {
+		return le.Labels()
+	}
+	return nil
+}
+
+// selectEdges returns the targets and their ends, the end is nil if the edge isn't online
+func (ex *exchange) selectEdges(mc *apis.Multicast) ([]uint64, []geminio.End, error) {
+	edgeIDs := mc.EdgeIDs
+	if mc.Subscribed {
+		var err error
+		edgeIDs, err = ex.Edgebound.GetEdgeIDsByTopic(mc.Topic)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if mc.Subscribed || !mc.Broadcast {
+		edges := make([]geminio.End, len(edgeIDs))
+		for i, edgeID := range edgeIDs {
+			edges[i] = ex.Edgebound.GetEdgeByID(edgeID)
+		}
+		return edgeIDs, edges, nil
+	}
+
+	selector, err := label.ParseSelector(mc.Selector)
+	if err != nil {
+		return nil, nil, err
+	}
+	edgeIDs = []uint64{}
+	edges := []geminio.End{}
+	for _, edge := range ex.Edgebound.ListEdges() {
+		if mc.Meta != "" && !strings.HasPrefix(string(edge.Meta()), mc.Meta) {
+			continue
+		}
+		if len(selector) != 0 && !selector.Matches(edgeLabels(edge)) {
+			continue
+		}
+		edgeIDs = append(edgeIDs, edge.ClientID())
+		edges = append(edges, edge)
+	}
+	return edgeIDs, edges, nil
+}
@@ -5,10 +5,10 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"net"
-	"time"
 
 	"github.com/singchia/frontier/pkg/frontier/apis"
 	"github.com/singchia/frontier/pkg/frontier/misc"
+	"github.com/singchia/geminio"
 	"github.com/singchia/geminio/options"
 
 	"k8s.io/klog/v2"
@@ -29,7 +29,7 @@ func (ex *exchange) GetEdgeID(meta []byte) (uint64, error) {
 	// call service
 	req := svc.NewRequest(meta)
 	opt := options.Call()
-	opt.SetTimeout(30 * time.Second)
+	opt.SetTimeout(ex.rpcTimeout(context.TODO(), apis.RPCGetEdgeID))
 	rsp, err := svc.Call(context.TODO(), apis.RPCGetEdgeID, req, opt)
 	if err != nil {
 		klog.V(2).Infof("exchange call service: %d, get edgeID err: %s, meta: %s", svc.ClientID(), err, meta)
@@ -64,12 +64,12 @@ func (ex *exchange) EdgeOnline(edgeID uint64, meta []byte, addr net.Addr) error
 		return err
 	}
 
-	index := misc.Hash(ex.conf.Exchange.HashBy, len(svcs), edgeID, addr)
+	index := misc.Hash(ex.conf.Exchange.HashBy, misc.EndNodes(ex.conf.Exchange.HashBy, svcs), edgeID, addr)
 	svc := svcs[index]
 	// call service
 	req := svc.NewRequest(data)
 	opt := options.Call()
-	opt.SetTimeout(30 * time.Second)
+	opt.SetTimeout(ex.rpcTimeout(context.TODO(), apis.RPCEdgeOnline))
 	_, err = svc.Call(context.TODO(), apis.RPCEdgeOnline, req, opt)
 	if err != nil {
 		klog.V(2).Infof("exchange call service: %d, edge online err: %s, meta: %s, addr: %s", svc.ClientID(), err, meta, addr)
@@ -87,7 +87,7 @@ func (ex *exchange) EdgeOffline(edgeID uint64, meta []byte, addr net.Addr) error
 		}
 		return err
 	}
-	index := misc.Hash(ex.conf.Exchange.HashBy, len(svcs), edgeID, addr)
+	index := misc.Hash(ex.conf.Exchange.HashBy, misc.EndNodes(ex.conf.Exchange.HashBy, svcs), edgeID, addr)
 	svc := svcs[index]
 	// call service the edge offline event
 	event := &apis.OnEdgeOffline{
@@ -104,7 +104,7 @@ func (ex *exchange) EdgeOffline(edgeID uint64, meta []byte, addr net.Addr) error
 	// call service
 	req := svc.NewRequest(data)
 	opt := options.Call()
-	opt.SetTimeout(30 * time.Second)
+	opt.SetTimeout(ex.rpcTimeout(context.TODO(), apis.RPCEdgeOffline))
 	_, err = svc.Call(context.TODO(), apis.RPCEdgeOffline, req, opt)
 	if err != nil {
 		klog.V(2).Infof("exchange call service: %d, edge offline err: %s, meta: %s, addr: %s", svc.ClientID(), err, meta, addr)
@@ -112,3 +112,96 @@ func (ex *exchange) EdgeOffline(edgeID uint64, meta []byte, addr net.Addr) error
 	}
 	return nil
 }
+
+// edgeUpdateMeta persists the meta updated by the edge, and tells the service
+func (ex *exchange) edgeUpdateMeta(edgeID uint64, addr net.Addr, r1 geminio.Request, r2 geminio.Response) {
+	update := &apis.MetaUpdate{}
+	err := json.Unmarshal(r1.Data(), update)
+	if err != nil {
+		klog.Errorf("edge update meta, json unmarshal err: %s, edgeID: %d", err, edgeID)
+		r2.SetError(err)
+		return
+	}
+	meta, err := ex.Edgebound.UpdateEdgeMeta(edgeID, update.Meta)
+	if err != nil {
+		r2.SetError(err)
+		return
+	}
+	// the meta is updated already, the event is best effort
+	err = ex.edgeMetaUpdated(edgeID, meta, addr)
+	if err != nil && err != apis.ErrServiceNotOnline {
+		klog.Warningf("edge update meta, deliver event err: %s, edgeID: %d", err, edgeID)
+	}
+}
+
+func (ex *exchange) edgeMetaUpdated(edgeID uint64, meta []byte, addr net.Addr) error {
+	svcs, err := ex.Servicebound.GetServicesByRPC(apis.RPCEdgeMetaUpdate)
+	if err != nil {
+		klog.V(2).Infof("exchange edge meta update, get service err: %s, edgeID: %d, meta: %s, addr: %s", err, edgeID, string(meta), addr)
+		if err == apis.ErrRecordNotFound {
+			return apis.ErrServiceNotOnline
+		}
+		return err
+	}
+	index := misc.Hash(ex.conf.Exchange.HashBy, misc.EndNodes(ex.conf.Exchange.HashBy, svcs), edgeID, addr)
+	svc := svcs[index]
+	// call service the edge meta update event
+	event := &apis.OnEdgeMetaUpdate{
+		EdgeID: edgeID,
+		Meta:   meta,
+		Net:    addr.Network(),
+		Str:    addr.String(),
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		klog.Errorf("exchange edge meta update, json marshal err: %s, edgeID: %d, meta: %s, addr: %s", err, edgeID, string(meta), addr)
+		return err
+	}
+	// call service
+	req := svc.NewRequest(data)
+	opt := options.Call()
+	opt.SetTimeout(ex.rpcTimeout(context.TODO(), apis.RPCEdgeMetaUpdate))
+	_, err = svc.Call(context.TODO(), apis.RPCEdgeMetaUpdate, req, opt)
+	if err != nil {
+		klog.V(2).Infof("exchange call service: %d, edge meta update err: %s, meta: %s, addr: %s", svc.ClientID(), err, meta, addr)
+		return err
+	}
+	return nil
+}
+
+// edgeToEdge asks the policy service whether the src edge can reach the dst edge
+func (ex *exchange) edgeToEdge(srcEdgeID, dstEdgeID uint64) error {
+	svcs, err := ex.Servicebound.GetServicesByRPC(apis.RPCEdgeToEdge)
+	if err != nil {
+		if err == apis.ErrRecordNotFound {
+			if ex.conf.Exchange.EdgeToEdgeAllowWhenNoPolicyOn {
+				return nil
+			}
+			klog.V(2).Infof("exchange edge to edge, no policy service online, srcEdgeID: %d, dstEdgeID: %d", srcEdgeID, dstEdgeID)
+			return apis.ErrEdgeToEdgeDenied
+		}
+		klog.V(2).Infof("exchange edge to edge, get service err: %s, srcEdgeID: %d, dstEdgeID: %d", err, srcEdgeID, dstEdgeID)
+		return err
+	}
+	event := &apis.OnEdgeToEdge{
+		SrcEdgeID: srcEdgeID,
+		DstEdgeID: dstEdgeID,
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		klog.Errorf("exchange edge to edge, json marshal err: %s, srcEdgeID: %d, dstEdgeID: %d", err, srcEdgeID, dstEdgeID)
+		return err
+	}
+	index := misc.Hash(ex.conf.Exchange.HashBy, misc.EndNodes(ex.conf.Exchange.HashBy, svcs), srcEdgeID, nil)
+	svc := svcs[index]
+	// call service
+	req := svc.NewRequest(data)
+	opt := options.Call()
+	opt.SetTimeout(ex.rpcTimeout(context.TODO(), apis.RPCEdgeToEdge))
+	_, err = svc.Call(context.TODO(), apis.RPCEdgeToEdge, req, opt)
+	if err != nil {
+		klog.V(2).Infof("exchange call service: %d, edge to edge err: %s, srcEdgeID: %d, dstEdgeID: %d", svc.ClientID(), err, srcEdgeID, dstEdgeID)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,393 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/frontier/pkg/frontier/config"
+	"github.com/singchia/geminio"
+	"github.com/singchia/geminio/options"
+	"github.com/tidwall/buntdb"
+	"k8s.io/klog/v2"
+)
+
+// key: outbox:{edgeID}:{seq}, both are zero padded to keep the order
+const outboxPrefix = "outbox:"
+
+type outboxItem struct {
+	Topic  string `json:"topic,omitempty"`
+	Data   []byte `json:"data"`
+	Custom []byte `json:"custom,omitempty"`
+}
+
+// box is the accounting of one edge's queued messages
+type box struct {
+	depth    int
+	bytes    int
+	flushing bool
+	again    bool
+}
+
+// outbox stores messages to offline edges, the lock order is always db then mtx
+type outbox struct {
+	conf *config.Outbox
+	db   *buntdb.DB
+
+	mtx   sync.Mutex
+	seq   uint64
+	boxes map[uint64]*box
+}
+
+func newOutbox(conf *config.Outbox) (*outbox, error) {
+	path := conf.Path
+	if path == "" {
+		path = ":memory:"
+	}
+	db, err := buntdb.Open(path)
+	if err != nil {
+		klog.Errorf("outbox open buntdb err: %s, path: %s", err, path)
+		return nil, err
+	}
+	ob := &outbox{
+		conf:  conf,
+		db:    db,
+		boxes: map[uint64]*box{},
+	}
+	bconf := buntdb.Config{}
+	if err = db.ReadConfig(&bconf); err != nil {
+		db.Close()
+		return nil, err
+	}
+	bconf.OnExpiredSync = ob.expired
+	if err = db.SetConfig(bconf); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// rebuild the accounting from spool
+	err = db.View(func(tx *buntdb.Tx) error {
+		return tx.AscendKeys(outboxPrefix+"*", func(key, value string) bool {
+			edgeID, seq, ok := parseOutboxKey(key)
+			if !ok {
+				return true
+			}
+			b := ob.getBox(edgeID)
+			b.depth++
+			b.bytes += len(value)
+			if seq > ob.seq {
+				ob.seq = seq
+			}
+			return true
+		})
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	// seq keeps increasing across restarts
+	now := uint64(time.Now().UnixNano())
+	if now > ob.seq {
+		ob.seq = now
+	}
+	return ob, nil
+}
+
+func outboxKey(edgeID, seq uint64) string {
+	return fmt.Sprintf("%s%020d:%020d", outboxPrefix, edgeID, seq)
+}
+
+func parseOutboxKey(key string) (uint64, uint64, bool) {
+	id, seq, ok := strings.Cut(strings.TrimPrefix(key, outboxPrefix), ":")
+	if !ok {
+		return 0, 0, false
+	}
+	edgeID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	s, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return edgeID, s, true
+}
+
+// must be called with mtx held
+func (ob *outbox) getBox(edgeID uint64) *box {
+	b, ok := ob.boxes[edgeID]
+	if !ok {
+		b = &box{}
+		ob.boxes[edgeID] = b
+	}
+	return b
+}
+
+// must be called with mtx held
+func (ob *outbox) release(edgeID uint64, size int) {
+	b, ok := ob.boxes[edgeID]
+	if !ok {
+		return
+	}
+	b.depth--
+	b.bytes -= size
+	if b.depth <= 0 && !b.flushing {
+		delete(ob.boxes, edgeID)
+	}
+}
+
+// called by buntdb inside the deleting transaction
+func (ob *outbox) expired(key, value string, tx *buntdb.Tx) error {
+	if _, err := tx.Delete(key); err != nil && err != buntdb.ErrNotFound {
+		return err
+	}
+	edgeID, _, ok := parseOutboxKey(key)
+	if !ok {
+		return nil
+	}
+	klog.V(2).Infof("outbox message expired, edgeID: %d", edgeID)
+	ob.mtx.Lock()
+	ob.release(edgeID, len(value))
+	ob.mtx.Unlock()
+	return nil
+}
+
+func (ob *outbox) empty(edgeID uint64) bool {
+	ob.mtx.Lock()
+	defer ob.mtx.Unlock()
+	b, ok := ob.boxes[edgeID]
+	return !ok || b.depth <= 0
+}
+
+func (ob *outbox) enqueue(edgeID uint64, item *outboxItem) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	value := string(data)
+	var opts *buntdb.SetOptions
+	if ob.conf.TTL > 0 {
+		opts = &buntdb.SetOptions{Expires: true, TTL: time.Duration(ob.conf.TTL) * time.Second}
+	}
+	return ob.db.Update(func(tx *buntdb.Tx) error {
+		ob.mtx.Lock()
+		defer ob.mtx.Unlock()
+
+		b := ob.getBox(edgeID)
+		if (ob.conf.MaxDepth > 0 && b.depth >= ob.conf.MaxDepth) ||
+			(ob.conf.MaxBytes > 0 && b.bytes+len(value) > ob.conf.MaxBytes) {
+			if b.depth == 0 && !b.flushing {
+				delete(ob.boxes, edgeID)
+			}
+			return apis.ErrOutboxFull
+		}
+		ob.seq++
+		if _, _, err := tx.Set(outboxKey(edgeID, ob.seq), value, opts); err != nil {
+			return err
+		}
+		b.depth++
+		b.bytes += len(value)
+		return nil
+	})
+}
+
+// head returns the oldest message of the edge
+func (ob *outbox) head(edgeID uint64) (string, *outboxItem, error) {
+	var (
+		key   string
+		value string
+	)
+	err := ob.db.View(func(tx *buntdb.Tx) error {
+		return tx.AscendKeys(fmt.Sprintf("%s%020d:*", outboxPrefix, edgeID), func(k, v string) bool {
+			key, value = k, v
+			return false
+		})
+	})
+	if err != nil || key == "" {
+		return "", nil, err
+	}
+	item := &outboxItem{}
+	if err = json.Unmarshal([]byte(value), item); err != nil {
+		return "", nil, err
+	}
+	return key, item, nil
+}
+
+func (ob *outbox) remove(edgeID uint64, key string) error {
+	return ob.db.Update(func(tx *buntdb.Tx) error {
+		// the item may be collected by expiration already
+		value, err := tx.Get(key, true)
+		if err != nil {
+			if err == buntdb.ErrNotFound {
+				return nil
+			}
+			return err
+		}
+		if _, err = tx.Delete(key); err != nil && err != buntdb.ErrNotFound {
+			return err
+		}
+		ob.mtx.Lock()
+		ob.release(edgeID, len(value))
+		ob.mtx.Unlock()
+		return nil
+	})
+}
+
+// flush delivers the edge's messages in order, and stops at the first failure
+func (ob *outbox) flush(edgeID uint64, deliver func(*outboxItem) error) {
+	ob.mtx.Lock()
+	b, ok := ob.boxes[edgeID]
+	if !ok {
+		ob.mtx.Unlock()
+		return
+	}
+	if b.flushing {
+		// the flushing one will pick up the new messages
+		b.again = true
+		ob.mtx.Unlock()
+		return
+	}
+	b.flushing = true
+	ob.mtx.Unlock()
+
+	done := func() {
+		ob.mtx.Lock()
+		b.flushing = false
+		b.again = false
+		if b.depth <= 0 {
+			delete(ob.boxes, edgeID)
+		}
+		ob.mtx.Unlock()
+	}
+
+	for {
+		key, item, err := ob.head(edgeID)
+		if err != nil {
+			klog.Errorf("outbox flush, get head err: %s, edgeID: %d", err, edgeID)
+			done()
+			return
+		}
+		if item == nil {
+			ob.mtx.Lock()
+			if b.again {
+				b.again = false
+				ob.mtx.Unlock()
+				continue
+			}
+			ob.mtx.Unlock()
+			done()
+			return
+		}
+		if err = deliver(item); err != nil {
+			klog.V(2).Infof("outbox flush, deliver err: %s, edgeID: %d", err, edgeID)
+			ob.mtx.Lock()
+			if b.again {
+				// the edge may be online again during delivering
+				b.again = false
+				ob.mtx.Unlock()
+				continue
+			}
+			ob.mtx.Unlock()
+			done()
+			return
+		}
+		if err = ob.remove(edgeID, key); err != nil {
+			klog.Errorf("outbox flush, remove err: %s, edgeID: %d", err, edgeID)
+			done()
+			return
+		}
+	}
+}
+
+func (ob *outbox) close() error {
+	return ob.db.Close()
+}
+
+// publishQueued delivers the message now if the edge is online, or queues it until online
+func (ex *exchange) publishQueued(ctx context.Context, serviceID uint64, r1 geminio.Request, r2 geminio.Response) {
+	out := &apis.Outbound{}
+	err := json.Unmarshal(r1.Data(), out)
+	if err != nil {
+		klog.Errorf("service publish queued, json unmarshal err: %s, serviceID: %d", err, serviceID)
+		r2.SetError(err)
+		return
+	}
+	item := &outboxItem{
+		Topic:  out.Topic,
+		Data:   out.Data,
+		Custom: out.Custom,
+	}
+
+	edge := ex.Edgebound.GetEdgeByID(out.EdgeID)
+	if edge != nil && (ex.outbox == nil || ex.outbox.empty(out.EdgeID)) {
+		err = ex.publishItem(ctx, edge, item)
+		if err == nil {
+			return
+		}
+		klog.V(2).Infof("service publish queued, serviceID: %d, publish edge: %d err: %s", serviceID, out.EdgeID, err)
+		// a timed out edge may have got it, and a rejecting one would reject it again
+		if !unreachable(err) {
+			r2.SetError(err)
+			return
+		}
+	}
+	if ex.outbox == nil {
+		if edge == nil {
+			err = apis.ErrEdgeNotOnline
+		}
+		r2.SetError(err)
+		return
+	}
+	if err = ex.outbox.enqueue(out.EdgeID, item); err != nil {
+		klog.V(2).Infof("service publish queued, serviceID: %d, enqueue edge: %d err: %s", serviceID, out.EdgeID, err)
+		r2.SetError(err)
+		return
+	}
+	if edge != nil {
+		// messages ahead are flushing or the edge just online
+		go ex.FlushOutbox(edge)
+	}
+}
+
+// unreachable tells whether the edge went offline while publishing, only then the message is queued
+func unreachable(err error) bool {
+	return err == apis.ErrEdgeNotOnline || err == io.EOF || err == io.ErrClosedPipe
+}
+
+func (ex *exchange) publishItem(ctx context.Context, edge geminio.End, item *outboxItem) error {
+	done, ok := ex.inflight(edge.ClientID())
+	if !ok {
+		return apis.ErrEdgeDraining
+	}
+	defer done()
+	mopt := options.NewMessage()
+	mopt.SetCustom(item.Custom)
+	mopt.SetTopic(item.Topic)
+	newmsg := edge.NewMessage(item.Data, mopt)
+	// publish option
+	popt := options.Publish()
+	popt.SetTimeout(ex.messageTimeout(ctx, item.Topic, nil))
+	return edge.Publish(ctx, newmsg, popt)
+}
+
+// FlushOutbox delivers messages queued while the edge was offline
+func (ex *exchange) FlushOutbox(edge geminio.End) {
+	if ex.outbox == nil {
+		return
+	}
+	edgeID := edge.ClientID()
+	ex.outbox.flush(edgeID, func(item *outboxItem) error {
+		// the edge may reconnect during flushing, always deliver to the latest one
+		edge := ex.Edgebound.GetEdgeByID(edgeID)
+		if edge == nil {
+			return apis.ErrEdgeNotOnline
+		}
+		return ex.publishItem(context.TODO(), edge, item)
+	})
+}
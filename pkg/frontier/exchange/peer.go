@@ -0,0 +1,160 @@
+package exchange
+
+import (
+	"context"
+	"time"
+
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/geminio"
+	"github.com/singchia/geminio/options"
+	"k8s.io/klog/v2"
+)
+
+type edgePair struct {
+	src, dst uint64
+}
+
+// allowEdgeToEdge asks the policy service unless allowed within the ttl, denials are not cached
+// since they can't be told from failed calls
+func (ex *exchange) allowEdgeToEdge(srcEdgeID, dstEdgeID uint64) error {
+	if ex.edgeToEdgeTTL == 0 {
+		return ex.edgeToEdge(srcEdgeID, dstEdgeID)
+	}
+	key := edgePair{src: srcEdgeID, dst: dstEdgeID}
+	now := time.Now()
+	if value, ok := ex.edgeToEdges.Load(key); ok && now.Before(value.(time.Time)) {
+		return nil
+	}
+	if err := ex.edgeToEdge(srcEdgeID, dstEdgeID); err != nil {
+		return err
+	}
+	ex.edgeToEdges.Store(key, now.Add(ex.edgeToEdgeTTL))
+	return nil
+}
+
+// sweepEdgeToEdges drops the expired, pairs of edges would pile up
+func (ex *exchange) sweepEdgeToEdges(done <-chan struct{}) {
+	ticker := time.NewTicker(ex.edgeToEdgeTTL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			ex.edgeToEdges.Range(func(key, value interface{}) bool {
+				if !now.Before(value.(time.Time)) {
+					ex.edgeToEdges.Delete(key)
+				}
+				return true
+			})
+		case <-done:
+			return
+		}
+	}
+}
+
+// getPeer returns the dst edge if the src edge is allowed to reach it
+func (ex *exchange) getPeer(srcEdgeID, dstEdgeID uint64) (geminio.End, error) {
+	err := ex.allowEdgeToEdge(srcEdgeID, dstEdgeID)
+	if err != nil {
+		return nil, err
+	}
+	peer := ex.Edgebound.GetEdgeByID(dstEdgeID)
+	if peer == nil {
+		return nil, apis.ErrEdgeNotOnline
+	}
+	if ex.edgeDraining(dstEdgeID) {
+		return nil, apis.ErrEdgeDraining
+	}
+	return peer, nil
+}
+
+// rpc from edge, and forward to peer edge
+func (ex *exchange) forwardRPCToPeer(ctx context.Context, srcEdgeID, dstEdgeID uint64, method string, r1 geminio.Request, r2 geminio.Response) {
+	peer, err := ex.getPeer(srcEdgeID, dstEdgeID)
+	if err != nil {
+		klog.V(2).Infof("edge forward rpc to peer, get peer err: %s, srcEdgeID: %d, dstEdgeID: %d", err, srcEdgeID, dstEdgeID)
+		r2.SetError(err)
+		return
+	}
+	done, ok := ex.inflight(dstEdgeID)
+	if !ok {
+		r2.SetError(apis.ErrEdgeDraining)
+		return
+	}
+	defer done()
+	// call peer
+	ropt := options.NewRequest()
+	ropt.SetCustom(ex.peerCustom(ctx, peer, r1.Custom(), srcEdgeID))
+	r3 := peer.NewRequest(r1.Data(), ropt)
+	// call option
+	copt := options.Call()
+	copt.SetTimeout(ex.rpcTimeout(ctx, method))
+	r4, err := peer.Call(ctx, method, r3, copt)
+	if err != nil {
+		klog.V(2).Infof("edge forward rpc to peer, call err: %s, srcEdgeID: %d, dstEdgeID: %d", err, srcEdgeID, dstEdgeID)
+		r2.SetError(err)
+		return
+	}
+	klog.V(3).Infof("edge forward rpc to peer, call srcEdgeID: %d rpc: %s to dstEdgeID: %d success", srcEdgeID, method, dstEdgeID)
+
+	r2.SetData(r4.Data())
+	r2.SetCustom(r4.Custom())
+	r2.SetError(r4.Error())
+}
+
+// message from edge, and forward to peer edge
+func (ex *exchange) forwardMessageToPeer(srcEdgeID, dstEdgeID uint64, topic string, msg geminio.Message) error {
+	peer, err := ex.getPeer(srcEdgeID, dstEdgeID)
+	if err != nil {
+		klog.V(2).Infof("edge forward message to peer, get peer err: %s, srcEdgeID: %d, dstEdgeID: %d", err, srcEdgeID, dstEdgeID)
+		return err
+	}
+	done, ok := ex.inflight(dstEdgeID)
+	if !ok {
+		return apis.ErrEdgeDraining
+	}
+	defer done()
+	mopt := options.NewMessage()
+	mopt.SetCustom(ex.peerCustom(context.TODO(), peer, msg.Custom(), srcEdgeID))
+	mopt.SetTopic(topic)
+	mopt.SetCnss(msg.Cnss())
+	newmsg := peer.NewMessage(msg.Data(), mopt)
+	// publish option
+	popt := options.Publish()
+	popt.SetTimeout(ex.messageTimeout(context.TODO(), topic, msg))
+	err = peer.Publish(context.TODO(), newmsg, popt)
+	if err != nil {
+		klog.V(2).Infof("edge forward message to peer, publish err: %s, srcEdgeID: %d, dstEdgeID: %d", err, srcEdgeID, dstEdgeID)
+		return err
+	}
+	return nil
+}
+
+// stream from edge, and forward to peer edge
+func (ex *exchange) streamToPeer(edgeStream geminio.Stream, dstEdgeID uint64) {
+	srcEdgeID := edgeStream.ClientID()
+	streamID := edgeStream.StreamID()
+
+	peer, err := ex.getPeer(srcEdgeID, dstEdgeID)
+	if err != nil {
+		klog.V(1).Infof("stream to peer, get peer err: %s, srcEdgeID: %d, dstEdgeID: %d, streamID: %d", err, srcEdgeID, dstEdgeID, streamID)
+		ex.ackStream(edgeStream, err)
+		return
+	}
+	// the peer knows who opened the stream
+	opt := callerOption(edgeStream)
+	opt.SetPeer(apis.EdgeTarget(srcEdgeID, ""))
+	peerStream, err := peer.OpenStream(opt)
+	if err != nil {
+		klog.Errorf("stream to peer, open stream err: %s, srcEdgeID: %d, dstEdgeID: %d", err, srcEdgeID, dstEdgeID)
+		ex.ackStream(edgeStream, apis.ErrStreamRefused)
+		return
+	}
+	if !ex.ackStream(edgeStream, nil) {
+		peerStream.Close()
+		return
+	}
+
+	// do stream forward
+	ex.streamForward(edgeStream, peerStream, false)
+}
@@ -0,0 +1,152 @@
+package exchange
+
+import (
+	"sync"
+	"time"
+
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/frontier/pkg/frontier/config"
+	"github.com/singchia/frontier/pkg/frontier/misc"
+	"k8s.io/klog/v2"
+)
+
+// idle topic limiters are swept at the interval
+const topicLimitSweep = 10 * time.Second
+
+type limiter struct {
+	// a call takes from both the count and bytes buckets or neither
+	mtx      sync.Mutex
+	messages *misc.Bucket
+	rpcs     *misc.Bucket
+	bytes    *misc.Bucket
+}
+
+func newLimiter(limit apis.RateLimit) *limiter {
+	return &limiter{
+		messages: misc.NewBucket(limit.Messages),
+		rpcs:     misc.NewBucket(limit.RPCs),
+		bytes:    misc.NewBucket(limit.Bytes),
+	}
+}
+
+func (l *limiter) allow(kind string, size int) bool {
+	count := l.messages
+	if kind == kindRPC {
+		count = l.rpcs
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if !count.Ready() || !l.bytes.Ready() {
+		return false
+	}
+	count.Take(1)
+	l.bytes.Take(size)
+	return true
+}
+
+// idle tells whether all buckets are refilled, the limiter is the same as a new one then
+func (l *limiter) idle() bool {
+	return l.messages.Full() && l.rpcs.Full() && l.bytes.Full()
+}
+
+const (
+	scopeEdge    = "edge"
+	scopeService = "service"
+	scopeTopic   = "topic"
+
+	kindMessage = "message"
+	kindRPC     = "rpc"
+)
+
+func toRateLimit(limit config.Limit) apis.RateLimit {
+	return apis.RateLimit{
+		Messages: limit.Messages,
+		RPCs:     limit.RPCs,
+		Bytes:    limit.Bytes,
+	}
+}
+
+func (ex *exchange) SetEdgeRateLimit(edgeID uint64, limit *apis.RateLimit) {
+	if limit == nil {
+		ex.edgeOverrides.Delete(edgeID)
+	} else {
+		ex.edgeOverrides.Store(edgeID, *limit)
+	}
+	// rebuilt with the new limit at next use
+	ex.edgeLimits.Delete(edgeID)
+}
+
+func (ex *exchange) GetEdgeRateLimit(edgeID uint64) apis.RateLimit {
+	if value, ok := ex.edgeOverrides.Load(edgeID); ok {
+		return value.(apis.RateLimit)
+	}
+	return toRateLimit(ex.conf.Exchange.RateLimit.Edge)
+}
+
+func (ex *exchange) allowEdge(edgeID uint64, kind string, size int) bool {
+	value, ok := ex.edgeLimits.Load(edgeID)
+	if !ok {
+		value, _ = ex.edgeLimits.LoadOrStore(edgeID, newLimiter(ex.GetEdgeRateLimit(edgeID)))
+	}
+	if value.(*limiter).allow(kind, size) {
+		return true
+	}
+	klog.V(2).Infof("edge rate limited, edgeID: %d, kind: %s, size: %d", edgeID, kind, size)
+	rateLimited.WithLabelValues(scopeEdge, kind).Inc()
+	return false
+}
+
+func (ex *exchange) allowService(serviceID uint64, kind string, size int) bool {
+	value, ok := ex.serviceLimits.Load(serviceID)
+	if !ok {
+		value, _ = ex.serviceLimits.LoadOrStore(serviceID, newLimiter(toRateLimit(ex.conf.Exchange.RateLimit.Service)))
+	}
+	if value.(*limiter).allow(kind, size) {
+		return true
+	}
+	klog.V(2).Infof("service rate limited, serviceID: %d, kind: %s, size: %d", serviceID, kind, size)
+	rateLimited.WithLabelValues(scopeService, kind).Inc()
+	return false
+}
+
+func (ex *exchange) allowTopic(topic string, size int) bool {
+	limit := ex.conf.Exchange.RateLimit.Topic
+	if limit.Messages <= 0 && limit.Bytes <= 0 {
+		return true
+	}
+	value, ok := ex.topicLimits.Load(topic)
+	if !ok {
+		value, _ = ex.topicLimits.LoadOrStore(topic, newLimiter(apis.RateLimit{Messages: limit.Messages, Bytes: limit.Bytes}))
+	}
+	if value.(*limiter).allow(kindMessage, size) {
+		return true
+	}
+	klog.V(2).Infof("topic rate limited, topic: %s, size: %d", topic, size)
+	rateLimited.WithLabelValues(scopeTopic, kindMessage).Inc()
+	return false
+}
+
+// sweepTopicLimits drops the idle limiters, topics are named by edges and would pile up
+func (ex *exchange) sweepTopicLimits(done <-chan struct{}) {
+	ticker := time.NewTicker(topicLimitSweep)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ex.dropIdleTopicLimits()
+		case <-done:
+			return
+		}
+	}
+}
+
+func (ex *exchange) dropIdleTopicLimits() {
+	ex.topicLimits.Range(func(key, value interface{}) bool {
+		if value.(*limiter).idle() {
+			ex.topicLimits.Delete(key)
+		}
+		return true
+	})
+}
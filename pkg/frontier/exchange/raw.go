@@ -0,0 +1,319 @@
+package exchange
+
+import (
+	"io"
+	"sync"
+
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/frontier/pkg/frontier/misc"
+	"github.com/singchia/frontier/pkg/frontier/raw"
+	"github.com/singchia/geminio"
+	"k8s.io/klog/v2"
+)
+
+// rawConn is frontier side of raw io on an End, frontier reads frames all the time,
+// and forwards them along paths with the windows granted by both sides.
+type rawConn struct {
+	end geminio.End
+
+	mtx  sync.Mutex
+	cond *sync.Cond
+	// bytes we can send to the end, absent means raw.Window
+	credits map[raw.Peer]int
+	// bytes from the end consumed and not granted back yet
+	consumed map[raw.Peer]int
+	// paths from the end
+	paths  map[raw.Peer]*rawPath
+	closed bool
+}
+
+// rawPath forwards data from the src's peer to the dst's peer in order
+type rawPath struct {
+	src, dst         *rawConn
+	srcPeer, dstPeer raw.Peer
+	// told to the src if the dst is gone
+	offline error
+
+	mtx    sync.Mutex
+	queue  [][]byte
+	notify chan struct{}
+	closed bool
+}
+
+func newRawConn(end geminio.End) *rawConn {
+	rc := &rawConn{
+		end:      end,
+		credits:  map[raw.Peer]int{},
+		consumed: map[raw.Peer]int{},
+		paths:    map[raw.Peer]*rawPath{},
+	}
+	rc.cond = sync.NewCond(&rc.mtx)
+	return rc
+}
+
+// must be called with mtx held
+func (rc *rawConn) credit(peer raw.Peer) int {
+	credit, ok := rc.credits[peer]
+	if !ok {
+		return raw.Window
+	}
+	return credit
+}
+
+// acquire waits for the window of the peer, and returns at most n bytes to send
+func (rc *rawConn) acquire(peer raw.Peer, n int) (int, bool) {
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+
+	for rc.credit(peer) <= 0 && !rc.closed {
+		rc.cond.Wait()
+	}
+	if rc.closed {
+		return 0, false
+	}
+	n = min(n, rc.credit(peer))
+	rc.credits[peer] = rc.credit(peer) - n
+	return n, true
+}
+
+// consume grants the window back when half of it is consumed
+func (rc *rawConn) consume(peer raw.Peer, n int) {
+	rc.mtx.Lock()
+	if rc.closed {
+		rc.mtx.Unlock()
+		return
+	}
+	consumed := rc.consumed[peer] + n
+	if consumed < raw.Window/2 {
+		rc.consumed[peer] = consumed
+		rc.mtx.Unlock()
+		return
+	}
+	delete(rc.consumed, peer)
+	rc.mtx.Unlock()
+
+	rc.write(&raw.Frame{Type: raw.FrameWindow, Peer: peer, N: uint32(consumed)})
+}
+
+func (rc *rawConn) write(frame *raw.Frame) error {
+	buf, err := frame.Encode()
+	if err != nil {
+		return err
+	}
+	_, err = rc.end.Write(buf)
+	return err
+}
+
+// reject tells the end the data to the peer is dropped
+func (rc *rawConn) reject(peer raw.Peer, reason error) {
+	rc.write(&raw.Frame{Type: raw.FrameError, Peer: peer, Data: []byte(reason.Error())})
+}
+
+func (rc *rawConn) close() {
+	rc.mtx.Lock()
+	rc.closed = true
+	paths := rc.paths
+	rc.paths = map[raw.Peer]*rawPath{}
+	rc.cond.Broadcast()
+	rc.mtx.Unlock()
+
+	for _, path := range paths {
+		path.close()
+	}
+}
+
+func (path *rawPath) push(data []byte) bool {
+	path.mtx.Lock()
+	defer path.mtx.Unlock()
+	if path.closed {
+		return false
+	}
+	path.queue = append(path.queue, data)
+	select {
+	case path.notify <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+func (path *rawPath) pop() ([]byte, bool) {
+	for {
+		path.mtx.Lock()
+		if path.closed {
+			path.mtx.Unlock()
+			return nil, false
+		}
+		if len(path.queue) != 0 {
+			data := path.queue[0]
+			path.queue[0] = nil
+			path.queue = path.queue[1:]
+			path.mtx.Unlock()
+			return data, true
+		}
+		path.mtx.Unlock()
+		<-path.notify
+	}
+}
+
+// close returns the bytes dropped
+func (path *rawPath) close() int {
+	path.mtx.Lock()
+	defer path.mtx.Unlock()
+	if path.closed {
+		return 0
+	}
+	path.closed = true
+	dropped := 0
+	for _, data := range path.queue {
+		dropped += len(data)
+	}
+	path.queue = nil
+	close(path.notify)
+	return dropped
+}
+
+func (path *rawPath) run() {
+	for {
+		data, ok := path.pop()
+		if !ok {
+			return
+		}
+		sent := 0
+		for sent < len(data) {
+			n, ok := path.dst.acquire(path.dstPeer, len(data)-sent)
+			if !ok {
+				break
+			}
+			err := path.dst.write(&raw.Frame{Type: raw.FrameData, Peer: path.dstPeer, Data: data[sent : sent+n]})
+			if err != nil {
+				klog.V(2).Infof("exchange forward raw, write err: %s, src: %d, dst: %d", err, path.src.end.ClientID(), path.dst.end.ClientID())
+				break
+			}
+			sent += n
+		}
+		path.src.consume(path.srcPeer, len(data))
+		if sent < len(data) {
+			// the dst is gone, the next data from src will find a new path
+			path.src.mtx.Lock()
+			if path.src.paths[path.srcPeer] == path {
+				delete(path.src.paths, path.srcPeer)
+			}
+			path.src.mtx.Unlock()
+			path.src.consume(path.srcPeer, path.close())
+			path.src.reject(path.srcPeer, path.offline)
+			return
+		}
+	}
+}
+
+// readRaw reads frames from the end until EOF, route returns the dst of a peer,
+// and the end is told offline if the dst is not found or gone
+func (ex *exchange) readRaw(rc *rawConn, offline error, route func(raw.Peer) (*rawConn, raw.Peer, bool)) {
+	defer rc.close()
+
+	// the sdk may be connected to another frontier before
+	rc.write(&raw.Frame{Type: raw.FrameReset})
+
+	buf := make([]byte, raw.MaxFrame)
+	for {
+		n, err := rc.end.Read(buf)
+		if err != nil {
+			if err != io.EOF {
+				klog.V(2).Infof("exchange forward raw, read err: %s, clientID: %d", err, rc.end.ClientID())
+			}
+			return
+		}
+		frame, err := raw.Decode(buf[:n])
+		if err != nil {
+			klog.V(2).Infof("exchange forward raw, decode err: %s, clientID: %d", err, rc.end.ClientID())
+			continue
+		}
+		switch frame.Type {
+		case raw.FrameWindow:
+			rc.mtx.Lock()
+			rc.credits[frame.Peer] = rc.credit(frame.Peer) + int(frame.N)
+			rc.cond.Broadcast()
+			rc.mtx.Unlock()
+			continue
+		case raw.FrameData:
+		default:
+			continue
+		}
+
+		rc.mtx.Lock()
+		path, ok := rc.paths[frame.Peer]
+		rc.mtx.Unlock()
+		if !ok {
+			dst, dstPeer, ok := route(frame.Peer)
+			if !ok {
+				// nowhere to go, drop it, grant back and tell the end
+				rc.consume(frame.Peer, len(frame.Data))
+				rc.reject(frame.Peer, offline)
+				continue
+			}
+			path = &rawPath{
+				src:     rc,
+				srcPeer: frame.Peer,
+				dst:     dst,
+				dstPeer: dstPeer,
+				offline: offline,
+				notify:  make(chan struct{}, 1),
+			}
+			rc.mtx.Lock()
+			rc.paths[frame.Peer] = path
+			rc.mtx.Unlock()
+			go path.run()
+		}
+		data := make([]byte, len(frame.Data))
+		copy(data, frame.Data)
+		if !path.push(data) {
+			rc.consume(frame.Peer, len(data))
+		}
+	}
+}
+
+// raw io from service, and forward to edge
+func (ex *exchange) forwardRawToEdge(service string, end geminio.End) {
+	serviceID := end.ClientID()
+	rc := newRawConn(end)
+	ex.serviceRaws.Store(serviceID, rc)
+
+	go func() {
+		defer ex.serviceRaws.CompareAndDelete(serviceID, rc)
+
+		ex.readRaw(rc, apis.ErrEdgeNotOnline, func(peer raw.Peer) (*rawConn, raw.Peer, bool) {
+			value, ok := ex.edgeRaws.Load(peer.ID)
+			if !ok {
+				klog.V(2).Infof("service forward raw, serviceID: %d, the edge: %d is not online", serviceID, peer.ID)
+				return nil, raw.Peer{}, false
+			}
+			return value.(*rawConn), raw.Peer{Name: service}, true
+		})
+	}()
+}
+
+// raw io from edge, and forward to service
+func (ex *exchange) forwardRawToService(end geminio.End) {
+	edgeID := end.ClientID()
+	addr := end.RemoteAddr()
+	rc := newRawConn(end)
+	ex.edgeRaws.Store(edgeID, rc)
+
+	go func() {
+		defer ex.edgeRaws.CompareAndDelete(edgeID, rc)
+
+		ex.readRaw(rc, apis.ErrServiceNotOnline, func(peer raw.Peer) (*rawConn, raw.Peer, bool) {
+			svcs, err := ex.Servicebound.GetServicesByName(peer.Name)
+			if err != nil || len(svcs) == 0 {
+				klog.V(2).Infof("edge forward raw, edgeID: %d, the service: %s is not online", edgeID, peer.Name)
+				return nil, raw.Peer{}, false
+			}
+			index := misc.Hash(ex.conf.Exchange.HashBy, misc.EndNodes(ex.conf.Exchange.HashBy, svcs), edgeID, addr)
+			value, ok := ex.serviceRaws.Load(svcs[index].ClientID())
+			if !ok {
+				return nil, raw.Peer{}, false
+			}
+			return value.(*rawConn), raw.Peer{ID: edgeID}, true
+		})
+	}()
+}
@@ -2,16 +2,24 @@ package exchange
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"strconv"
 	"time"
 
+	"github.com/singchia/frontier/pkg/frontier/apis"
 	"github.com/singchia/geminio"
 	"github.com/singchia/geminio/options"
 	"k8s.io/klog/v2"
 )
 
 func (ex *exchange) StreamToEdge(serviceStream geminio.Stream) {
+	// the stream is accepted before the opener knows it's opened,
+	// so ack and forward it asynchronously
+	go ex.streamToEdge(serviceStream)
+}
+
+func (ex *exchange) streamToEdge(serviceStream geminio.Stream) {
 	serviceID := serviceStream.ClientID()
 	streamID := serviceStream.StreamID()
 	// get edgeID
@@ -19,8 +27,7 @@ func (ex *exchange) StreamToEdge(serviceStream geminio.Stream) {
 	edgeID, err := strconv.ParseUint(peer, 10, 64)
 	if err != nil {
 		klog.Errorf("stream to edge err: %s, serviceID: %d, streamID: %d", err, serviceID, streamID)
-		// TODO return err to the stream
-		serviceStream.Close()
+		ex.ackStream(serviceStream, apis.ErrIllegalEdgeID)
 		return
 	}
 
@@ -28,54 +35,145 @@ func (ex *exchange) StreamToEdge(serviceStream geminio.Stream) {
 	edge := ex.Edgebound.GetEdgeByID(edgeID)
 	if edge == nil {
 		klog.V(1).Infof("stream to edge, serviceID: %d, edgeID: %d, is not online", serviceID, streamID)
-		serviceStream.Close()
+		ex.ackStream(serviceStream, apis.ErrEdgeNotOnline)
+		return
+	}
+	if ex.edgeDraining(edgeID) {
+		klog.V(1).Infof("stream to edge, serviceID: %d, edgeID: %d, is draining", serviceID, edgeID)
+		ex.ackStream(serviceStream, apis.ErrEdgeDraining)
 		return
 	}
 
-	// open stream from edge
-	edgeStream, err := edge.OpenStream()
+	// open stream from edge, the edge is told the caller by the meta
+	edgeStream, err := edge.OpenStream(callerOption(serviceStream))
 	if err != nil {
 		klog.Errorf("stream to edge, open stream err: %s, serviceID: %d, edgeID: %d", err, serviceID, streamID)
-		serviceStream.Close()
+		ex.ackStream(serviceStream, apis.ErrStreamRefused)
+		return
+	}
+	if !ex.ackStream(serviceStream, nil) {
+		edgeStream.Close()
 		return
 	}
 
 	// do stream forward
-	ex.streamForward(serviceStream, edgeStream)
+	ex.streamForward(serviceStream, edgeStream, false)
 }
 
 func (ex *exchange) StreamToService(edgeStream geminio.Stream) {
+	// the stream is accepted before the opener knows it's opened,
+	// so ack and forward it asynchronously
+	go ex.streamToService(edgeStream)
+}
+
+func (ex *exchange) streamToService(edgeStream geminio.Stream) {
 	edgeID := edgeStream.ClientID()
 	streamID := edgeStream.StreamID()
 
-	// get service
+	if ex.edgeDraining(edgeID) {
+		klog.V(1).Infof("stream to service, edgeID: %d is draining, streamID: %d", edgeID, streamID)
+		ex.ackStream(edgeStream, apis.ErrEdgeDraining)
+		return
+	}
 	peer := edgeStream.Peer()
+	// stream to peer edge
+	if dstEdgeID, _, ok := apis.ParseEdgeTarget(peer); ok {
+		ex.streamToPeer(edgeStream, dstEdgeID)
+		return
+	}
+
+	// get service
 	svc, err := ex.Servicebound.GetServiceByName(peer)
-	if err != nil {
-		klog.V(1).Infof("stream to service, get service: %s err: %s, edgeID: %d, streamID: %d", peer, err, edgeID, streamID)
-		// TODO return err to the stream
-		edgeStream.ClientID()
+	if err != nil || svc == nil {
+		klog.V(1).Infof("stream to service, get service: %s err: %v, edgeID: %d, streamID: %d", peer, err, edgeID, streamID)
+		ex.ackStream(edgeStream, apis.ErrServiceNotOnline)
 		return
 	}
 
-	serviceStream, err := svc.OpenStream()
+	serviceStream, err := svc.OpenStream(callerOption(edgeStream))
 	if err != nil {
 		klog.Errorf("stream to service, open stream err: %s, serviceID: %d, edgeID: %d", err, svc.ClientID(), edgeID)
-		edgeStream.Close()
+		ex.ackStream(edgeStream, apis.ErrStreamRefused)
+		return
+	}
+	if !ex.ackStream(edgeStream, nil) {
+		serviceStream.Close()
 		return
 	}
 
 	// do stream forward
-	ex.streamForward(edgeStream, serviceStream)
+	ex.streamForward(edgeStream, serviceStream, envelope(svc) >= 1)
 }
 
-func (ex *exchange) streamForward(left, right geminio.Stream) {
+// max time to wait for the StreamOpen from the opener
+const streamAckTimeout = 30 * time.Second
+
+// streamOpen returns the StreamOpen carried in the meta by the opener, empty if it's an old sdk
+func streamOpen(stream geminio.Stream) *apis.StreamOpen {
+	open := &apis.StreamOpen{}
+	if meta := stream.Meta(); len(meta) != 0 {
+		json.Unmarshal(meta, open)
+	}
+	return open
+}
+
+// callerOption tells the acceptor the opener of the stream by the meta
+func callerOption(opener geminio.Stream) *options.OpenStreamOptions {
+	meta, _ := json.Marshal(&apis.StreamCaller{ClientID: opener.ClientID(), StreamID: opener.StreamID()})
+	opt := options.OpenStream()
+	opt.SetMeta(meta)
+	return opt
+}
+
+// ackStream tells the opener whether the stream is forwarded if asked,
+// the stream is closed on err, and false is returned if the stream is closed
+func (ex *exchange) ackStream(stream geminio.Stream, err error) bool {
+	if streamOpen(stream).Ack {
+		// read the StreamOpen from the opener before acking
+		buf := make([]byte, 1024)
+		stream.SetReadDeadline(time.Now().Add(streamAckTimeout))
+		_, rerr := stream.Read(buf)
+		stream.SetReadDeadline(time.Time{})
+		if rerr != nil {
+			klog.V(2).Infof("stream ack, read err: %s, clientID: %d, streamID: %d", rerr, stream.ClientID(), stream.StreamID())
+			stream.Close()
+			return false
+		}
+		ack := &apis.StreamAck{}
+		if err != nil {
+			ack.Error = err.Error()
+		}
+		data, _ := json.Marshal(ack)
+		if _, werr := stream.Write(data); werr != nil {
+			klog.V(2).Infof("stream ack, write err: %s, clientID: %d, streamID: %d", werr, stream.ClientID(), stream.StreamID())
+			err = werr
+		}
+	}
+	if err != nil {
+		stream.Close()
+		return false
+	}
+	return true
+}
+
+// streamForward forwards between the opener and the acceptor, the caller is carried in the envelope
+// only to the side asked for it, so old sdks get the custom as is
+func (ex *exchange) streamForward(opener, acceptor geminio.Stream, acceptorCaller bool) {
+	openerCaller := streamOpen(opener).Caller
 	// raw
-	ex.streamForwardRaw(left, right)
+	ex.streamForwardRaw(opener, acceptor)
 	// message
-	ex.streamForwardMessage(left, right)
+	ex.streamForwardMessage(opener, acceptor, openerCaller, acceptorCaller)
 	// rpc
-	ex.streamForwardRPC(left, right)
+	ex.streamForwardRPC(opener, acceptor, openerCaller, acceptorCaller)
+}
+
+// callerCustom adds the caller to the custom if the receiver asked for it
+func callerCustom(custom []byte, from geminio.Stream, caller bool) []byte {
+	if !caller {
+		return custom
+	}
+	return apis.EncodeCaller(custom, from.ClientID(), from.StreamID())
 }
 
 func (ex *exchange) streamForwardRaw(left, right geminio.Stream) {
@@ -98,8 +196,8 @@ func (ex *exchange) streamForwardRaw(left, right geminio.Stream) {
 	go copy(right, left)
 }
 
-func (ex *exchange) streamForwardMessage(left, right geminio.Stream) {
-	recvPub := func(from, to geminio.Stream) {
+func (ex *exchange) streamForwardMessage(left, right geminio.Stream, leftCaller, rightCaller bool) {
+	recvPub := func(from, to geminio.Stream, caller bool) {
 		fromID := from.ClientID()
 		toID := to.ClientID()
 
@@ -112,15 +210,15 @@ func (ex *exchange) streamForwardMessage(left, right geminio.Stream) {
 				return
 			}
 
-			// message and options
+			// message and options, carry the caller to the next
 			mopt := options.NewMessage()
-			mopt.SetCustom(msg.Custom())
+			mopt.SetCustom(callerCustom(msg.Custom(), from, caller))
 			mopt.SetTopic(msg.Topic())
 			mopt.SetCnss(msg.Cnss())
 			newmsg := to.NewMessage(msg.Data(), mopt)
 			// publish options
 			popt := options.Publish()
-			popt.SetTimeout(30 * time.Second)
+			popt.SetTimeout(ex.messageTimeout(context.TODO(), msg.Topic(), msg))
 			err = to.Publish(context.TODO(), newmsg, popt)
 			if err != nil {
 				klog.Errorf("stream forward message, publish err: %s, fromID: %d, toID: %d", err, fromID, toID)
@@ -131,23 +229,23 @@ func (ex *exchange) streamForwardMessage(left, right geminio.Stream) {
 		}
 	}
 
-	go recvPub(left, right)
-	go recvPub(right, left)
+	go recvPub(left, right, rightCaller)
+	go recvPub(right, left, leftCaller)
 }
 
-func (ex *exchange) streamForwardRPC(left, right geminio.Stream) {
-	forwardRPC := func(from, to geminio.Stream) {
+func (ex *exchange) streamForwardRPC(left, right geminio.Stream, leftCaller, rightCaller bool) {
+	forwardRPC := func(from, to geminio.Stream, caller bool) {
 		fromID := from.ClientID()
 		toID := to.ClientID()
 
 		from.Hijack(func(ctx context.Context, method string, r1 geminio.Request, r2 geminio.Response) {
-			// TODO to carry the fromID to next Call
+			// carry the caller to next Call
 			ropt := options.NewRequest()
-			ropt.SetCustom(r1.Custom())
+			ropt.SetCustom(callerCustom(r1.Custom(), from, caller))
 			r3 := to.NewRequest(r1.Data(), ropt)
 			// call option
 			copt := options.Call()
-			copt.SetTimeout(30 * time.Second)
+			copt.SetTimeout(ex.rpcTimeout(ctx, method))
 			r4, err := to.Call(ctx, method, r3, copt)
 			if err != nil {
 				klog.Errorf("stream forward rpc, call err: %s, fromID: %d, toID: %d", err, fromID, toID)
@@ -161,6 +259,6 @@ func (ex *exchange) streamForwardRPC(left, right geminio.Stream) {
 		})
 	}
 
-	forwardRPC(left, right)
-	forwardRPC(right, left)
+	forwardRPC(left, right, rightCaller)
+	forwardRPC(right, left, leftCaller)
 }
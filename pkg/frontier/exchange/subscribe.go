@@ -0,0 +1,47 @@
+package exchange
+
+import (
+	"encoding/json"
+
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/geminio"
+	"k8s.io/klog/v2"
+)
+
+// edgeSubscribe records the topics of edge, services publish to them by PublishTopic
+func (ex *exchange) edgeSubscribe(edgeID uint64, r1 geminio.Request, r2 geminio.Response, subscribe bool) {
+	sub := &apis.Subscription{}
+	err := json.Unmarshal(r1.Data(), sub)
+	if err != nil {
+		klog.Errorf("edge subscription, json unmarshal err: %s, edgeID: %d", err, edgeID)
+		r2.SetError(err)
+		return
+	}
+	if subscribe {
+		err = ex.Edgebound.SubscribeTopics(edgeID, sub.Topics)
+	} else {
+		err = ex.Edgebound.UnsubscribeTopics(edgeID, sub.Topics)
+	}
+	if err != nil {
+		r2.SetError(err)
+	}
+}
+
+// serviceSubscribe updates the topics received by service at runtime
+func (ex *exchange) serviceSubscribe(end geminio.End, r1 geminio.Request, r2 geminio.Response, subscribe bool) {
+	sub := &apis.Subscription{}
+	err := json.Unmarshal(r1.Data(), sub)
+	if err != nil {
+		klog.Errorf("service subscription, json unmarshal err: %s, serviceID: %d", err, end.ClientID())
+		r2.SetError(err)
+		return
+	}
+	if subscribe {
+		err = ex.Servicebound.SubscribeTopics(end, sub.Topics)
+	} else {
+		err = ex.Servicebound.UnsubscribeTopics(end, sub.Topics)
+	}
+	if err != nil {
+		r2.SetError(err)
+	}
+}
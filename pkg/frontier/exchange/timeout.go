@@ -0,0 +1,51 @@
+package exchange
+
+import (
+	"context"
+	"time"
+
+	"github.com/singchia/geminio"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// timeout returns how long to wait for the downstream, the caller's deadline is always honored,
+// the override caps it, and the default is only used when neither exists
+func (ex *exchange) timeout(deadline time.Time, override int) time.Duration {
+	capped := time.Duration(override) * time.Millisecond
+	if !deadline.IsZero() {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			// zero means no timeout for geminio
+			remaining = time.Nanosecond
+		}
+		if capped <= 0 || remaining < capped {
+			return remaining
+		}
+	}
+	if capped > 0 {
+		return capped
+	}
+	if ex.conf.Exchange.Timeout.Default > 0 {
+		return time.Duration(ex.conf.Exchange.Timeout.Default) * time.Millisecond
+	}
+	return defaultTimeout
+}
+
+// the caller's deadline is carried by the hijacked ctx
+func (ex *exchange) rpcTimeout(ctx context.Context, method string) time.Duration {
+	deadline, _ := ctx.Deadline()
+	return ex.timeout(deadline, ex.conf.Exchange.Timeout.Methods[method])
+}
+
+// the caller's deadline is carried by the message timeout if set
+func (ex *exchange) messageTimeout(ctx context.Context, topic string, msg geminio.Message) time.Duration {
+	deadline, _ := ctx.Deadline()
+	if msg != nil && msg.Timeout() > 0 {
+		msgDeadline := time.Now().Add(msg.Timeout())
+		if deadline.IsZero() || msgDeadline.Before(deadline) {
+			deadline = msgDeadline
+		}
+	}
+	return ex.timeout(deadline, ex.conf.Exchange.Timeout.Topics[topic])
+}
@@ -1,6 +1,8 @@
 package frontier
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"time"
 
@@ -14,6 +16,8 @@ import (
 	"k8s.io/klog/v2"
 )
 
+var errDraining = errors.New("draining")
+
 type Frontier struct {
 	repo   apis.Repo
 	mqm    apis.MQM
@@ -81,6 +85,23 @@ func (frontier *Frontier) Run() {
 	frontier.server.Serve()
 }
 
+// DrainRequested delivers the timeout once the control plane asks to drain, 0 means the default
+func (frontier *Frontier) DrainRequested() <-chan time.Duration {
+	return frontier.server.DrainRequested()
+}
+
+// Drain marks frontier not ready, and returns after the edges are drained, timeout or ctx is done
+func (frontier *Frontier) Drain(ctx context.Context, timeout time.Duration) {
+	frontier.obs.SetReadiness(func(context.Context) error {
+		return errDraining
+	})
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := frontier.server.Drain(ctx); err != nil {
+		klog.Warningf("frontier drain err: %s", err)
+	}
+}
+
 func (frontier *Frontier) Close() {
 	frontier.obs.Shutdown(5 * time.Second)
 	frontier.repo.Close()
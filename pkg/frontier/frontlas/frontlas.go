@@ -267,3 +267,27 @@ func (informer *Informer) SetServiceCount(count int) {
 	count32 := int32(count)
 	atomic.StoreInt32(&informer.serviceCount, count32)
 }
+
+// frontier events
+// PickFrontier asks frontlas for another frontier to take over the edges, returns its advertised edgebound addr
+func (informer *Informer) PickFrontier(ctx context.Context) (string, error) {
+	msg := apis.FrontierPick{
+		FrontierID: informer.conf.Daemon.FrontierID,
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		klog.Errorf("frontlas pick frontier, json marshal err: %s", err)
+		return "", err
+	}
+	rsp, err := informer.end.Call(ctx, apis.RPCFrontierPick, informer.end.NewRequest(data))
+	if err != nil {
+		klog.Errorf("frontlas pick frontier, call rpc err: %s", err)
+		return "", err
+	}
+	ins := &apis.FrontierInstance{}
+	if err = json.Unmarshal(rsp.Data(), ins); err != nil {
+		klog.Errorf("frontlas pick frontier, json unmarshal err: %s", err)
+		return "", err
+	}
+	return ins.AdvertisedEdgeboundAddr, nil
+}
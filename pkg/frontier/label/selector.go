@@ -0,0 +1,235 @@
+package label
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// label selectors in the kubernetes style, requirements are ANDed:
+//   site=fra1, site==fra1, site!=fra1, site in (fra1,ams1), site notin (fra1), site, !site
+// != and notin also match edges without the key.
+
+type Operator string
+
+const (
+	// = and == are parsed as in with one value, != as notin with one value
+	OpIn           Operator = "in"
+	OpNotIn        Operator = "notin"
+	OpExists       Operator = "exists"
+	OpDoesNotExist Operator = "!"
+)
+
+type Requirement struct {
+	Key      string
+	Operator Operator
+	// only for in and notin
+	Values []string
+}
+
+// Selector matches all if empty
+type Selector []Requirement
+
+var (
+	ErrIllegalSelector = errors.New("illegal label selector")
+	ErrIllegalLabel    = errors.New("illegal label")
+)
+
+var (
+	labelNamePattern = regexp.MustCompile(`^([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]$`)
+	labelSetPattern  = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+)
+
+const (
+	labelPrefixMaxLen = 253
+	labelNameMaxLen   = 63
+)
+
+// ParseSelector parses the selector, empty string gives the empty selector
+func ParseSelector(str string) (Selector, error) {
+	selector := Selector{}
+	for _, part := range splitSelector(str) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			if strings.TrimSpace(str) == "" {
+				break
+			}
+			return nil, fmt.Errorf("%w: empty requirement in %q", ErrIllegalSelector, str)
+		}
+		req, err := parseRequirement(part)
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, req)
+	}
+	return selector, nil
+}
+
+// split by commas out of parentheses
+func splitSelector(str string) []string {
+	parts := []string{}
+	depth, start := 0, 0
+	for i, c := range str {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, str[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, str[start:])
+}
+
+func parseRequirement(part string) (Requirement, error) {
+	var req Requirement
+	if matches := labelSetPattern.FindStringSubmatch(part); matches != nil {
+		req.Key, req.Operator = matches[1], Operator(matches[2])
+		for _, value := range strings.Split(matches[3], ",") {
+			req.Values = append(req.Values, strings.TrimSpace(value))
+		}
+	} else if key, value, ok := strings.Cut(part, "!="); ok {
+		req.Key, req.Operator = strings.TrimSpace(key), OpNotIn
+		req.Values = []string{strings.TrimSpace(value)}
+	} else if key, value, ok := strings.Cut(part, "="); ok {
+		req.Key, req.Operator = strings.TrimSpace(key), OpIn
+		req.Values = []string{strings.TrimSpace(strings.TrimPrefix(value, "="))}
+	} else if strings.HasPrefix(part, "!") {
+		req.Key, req.Operator = strings.TrimSpace(part[1:]), OpDoesNotExist
+	} else {
+		req.Key, req.Operator = part, OpExists
+	}
+	if err := validateLabelKey(req.Key); err != nil {
+		return req, fmt.Errorf("%w: %s", ErrIllegalSelector, err)
+	}
+	for _, value := range req.Values {
+		if err := validateLabelValue(value); err != nil {
+			return req, fmt.Errorf("%w: %s", ErrIllegalSelector, err)
+		}
+	}
+	return req, nil
+}
+
+// Matches tells whether the labels meet the requirement
+func (req Requirement) Matches(labels map[string]string) bool {
+	value, ok := labels[req.Key]
+	switch req.Operator {
+	case OpExists:
+		return ok
+	case OpDoesNotExist:
+		return !ok
+	case OpIn:
+		return ok && req.has(value)
+	case OpNotIn:
+		return !ok || !req.has(value)
+	}
+	return false
+}
+
+func (req Requirement) has(value string) bool {
+	for _, v := range req.Values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (req Requirement) String() string {
+	switch req.Operator {
+	case OpExists:
+		return req.Key
+	case OpDoesNotExist:
+		return "!" + req.Key
+	case OpIn:
+		if len(req.Values) == 1 {
+			return req.Key + "=" + req.Values[0]
+		}
+	case OpNotIn:
+		if len(req.Values) == 1 {
+			return req.Key + "!=" + req.Values[0]
+		}
+	}
+	return req.Key + " " + string(req.Operator) + " (" + strings.Join(req.Values, ",") + ")"
+}
+
+// Matches tells whether the labels meet all requirements
+func (selector Selector) Matches(labels map[string]string) bool {
+	for _, req := range selector {
+		if !req.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// Keys returns the keys of requirements without duplicates
+func (selector Selector) Keys() []string {
+	keys := []string{}
+	seen := map[string]struct{}{}
+	for _, req := range selector {
+		if _, ok := seen[req.Key]; ok {
+			continue
+		}
+		seen[req.Key] = struct{}{}
+		keys = append(keys, req.Key)
+	}
+	return keys
+}
+
+func (selector Selector) String() string {
+	reqs := make([]string, 0, len(selector))
+	for _, req := range selector {
+		reqs = append(reqs, req.String())
+	}
+	return strings.Join(reqs, ",")
+}
+
+// Validate checks keys and values of labels, keys are like "site" or "example.com/site",
+// names and values are at most 63 alphanumerics, '-', '_' or '.', values can be empty
+func Validate(labels map[string]string) error {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := validateLabelKey(key); err != nil {
+			return fmt.Errorf("%w: %s", ErrIllegalLabel, err)
+		}
+		if err := validateLabelValue(labels[key]); err != nil {
+			return fmt.Errorf("%w: %s", ErrIllegalLabel, err)
+		}
+	}
+	return nil
+}
+
+func validateLabelKey(key string) error {
+	name := key
+	if prefix, rest, ok := strings.Cut(key, "/"); ok {
+		if prefix == "" || len(prefix) > labelPrefixMaxLen || !labelNamePattern.MatchString(prefix) {
+			return fmt.Errorf("key %q has an illegal prefix", key)
+		}
+		name = rest
+	}
+	if len(name) > labelNameMaxLen || !labelNamePattern.MatchString(name) {
+		return fmt.Errorf("key %q has an illegal name", key)
+	}
+	return nil
+}
+
+func validateLabelValue(value string) error {
+	if value == "" {
+		return nil
+	}
+	if len(value) > labelNameMaxLen || !labelNamePattern.MatchString(value) {
+		return fmt.Errorf("value %q is illegal", value)
+	}
+	return nil
+}
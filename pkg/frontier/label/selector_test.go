@@ -0,0 +1,63 @@
+package label
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSelector(t *testing.T) {
+	labels := map[string]string{"site": "fra1", "model": "x200", "fw": "3.2"}
+	cases := []struct {
+		selector string
+		str      string
+		matched  bool
+	}{
+		{"", "", true},
+		{"site=fra1", "site=fra1", true},
+		{"site==fra1", "site=fra1", true},
+		{"site = ams1", "site=ams1", false},
+		{"site!=ams1", "site!=ams1", true},
+		{"region!=eu", "region!=eu", true},
+		{"model in (x100, x200)", "model in (x100,x200)", true},
+		{"model notin (x200)", "model!=x200", false},
+		{"region notin (eu,us)", "region notin (eu,us)", true},
+		{"fw", "fw", true},
+		{"!fw", "!fw", false},
+		{"!region", "!region", true},
+		{"site=fra1, model in (x200,x300), fw=3.2", "site=fra1,model in (x200,x300),fw=3.2", true},
+		{"site=fra1,fw=3.3", "site=fra1,fw=3.3", false},
+		{"example.com/tier=edge", "example.com/tier=edge", false},
+	}
+	for _, c := range cases {
+		selector, err := ParseSelector(c.selector)
+		if err != nil {
+			t.Errorf("parse %q err: %s", c.selector, err)
+			continue
+		}
+		if selector.String() != c.str {
+			t.Errorf("parse %q, got %q, want %q", c.selector, selector.String(), c.str)
+		}
+		if selector.Matches(labels) != c.matched {
+			t.Errorf("selector %q matched: %v, want %v", c.selector, !c.matched, c.matched)
+		}
+	}
+
+	illegals := []string{"site=fra 1", "=fra1", "site in fra1", ",site", "site,", "-site", "site=a=b", "/site", "a.b/"}
+	for _, illegal := range illegals {
+		_, err := ParseSelector(illegal)
+		if !errors.Is(err, ErrIllegalSelector) {
+			t.Errorf("parse %q, unexpected err: %v", illegal, err)
+		}
+	}
+}
+
+func TestValidateLabels(t *testing.T) {
+	if err := Validate(map[string]string{"site": "fra1", "example.com/fw": "3.2", "empty": ""}); err != nil {
+		t.Error(err)
+	}
+	for _, labels := range []map[string]string{{"": "x"}, {"site": "fra 1"}, {"si te": "x"}, {"site": "a,b"}} {
+		if err := Validate(labels); !errors.Is(err, ErrIllegalLabel) {
+			t.Errorf("validate %v, unexpected err: %v", labels, err)
+		}
+	}
+}
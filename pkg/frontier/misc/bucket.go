@@ -0,0 +1,76 @@
+package misc
+
+import (
+	"sync"
+	"time"
+)
+
+// Bucket is a token bucket refilled at rate per second, the burst is the same as the rate
+type Bucket struct {
+	mtx    sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// nil bucket means unlimited
+func NewBucket(rate int) *Bucket {
+	if rate <= 0 {
+		return nil
+	}
+	return &Bucket{
+		rate:   float64(rate),
+		tokens: float64(rate),
+		last:   time.Now(),
+	}
+}
+
+// Take n tokens if there is any left, the debt is paid by later refills,
+// so a message larger than the rate still passes alone
+func (b *Bucket) Take(n int) bool {
+	if b == nil {
+		return true
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.refill()
+	if b.tokens <= 0 {
+		return false
+	}
+	b.tokens -= float64(n)
+	return true
+}
+
+// Ready tells whether Take would pass, no token is taken
+func (b *Bucket) Ready() bool {
+	if b == nil {
+		return true
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.refill()
+	return b.tokens > 0
+}
+
+// Full tells whether the bucket is refilled to the burst, it's the same as a new one then
+func (b *Bucket) Full() bool {
+	if b == nil {
+		return true
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.refill()
+	return b.tokens >= b.rate
+}
+
+func (b *Bucket) refill() {
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.rate {
+		b.tokens = b.rate
+	}
+	b.last = now
+}
@@ -1,11 +1,15 @@
 package misc
 
 import (
+	"encoding/json"
+	"math"
 	"math/rand"
 	"net"
 	"reflect"
 
+	"github.com/singchia/frontier/pkg/frontier/apis"
 	"github.com/singchia/frontier/pkg/utils"
+	"github.com/singchia/geminio"
 )
 
 func IsNil(i interface{}) bool {
@@ -20,7 +24,15 @@ func GetKeys(m map[string]struct{}) []string {
 	return keys
 }
 
-func Hash(hashby string, count int, edgeID uint64, addr net.Addr) int {
+// Node is a candidate to hash to, ID must be stable while others join or leave
+type Node struct {
+	ID     uint64
+	Weight int
+}
+
+// Hash returns the index of nodes
+func Hash(hashby string, nodes []Node, edgeID uint64, addr net.Addr) int {
+	count := len(nodes)
 	switch hashby {
 	case "srcip":
 		tcpaddr, ok := addr.(*net.TCPAddr)
@@ -33,7 +45,63 @@ func Hash(hashby string, count int, edgeID uint64, addr net.Addr) int {
 	case "random":
 		return rand.Intn(count)
 
+	case "consistent":
+		// rendezvous hashing, only edges on the leaving or joining node are remapped
+		return rendezvous(nodes, edgeID, false)
+
+	case "weighted":
+		// weighted rendezvous hashing, nodes get edges in proportion to their weights
+		return rendezvous(nodes, edgeID, true)
+
 	default: // "edgeid" or empty
 		return int(edgeID % uint64(count))
 	}
 }
+
+func rendezvous(nodes []Node, key uint64, weighted bool) int {
+	index := 0
+	max := math.Inf(-1)
+	for i, node := range nodes {
+		h := mix(key ^ mix(node.ID))
+		var score float64
+		if weighted {
+			weight := node.Weight
+			if weight <= 0 {
+				weight = 1
+			}
+			// uniform in (0, 1)
+			u := (float64(h>>11) + 0.5) / (1 << 53)
+			score = -float64(weight) / math.Log(u)
+		} else {
+			score = float64(h)
+		}
+		if score > max {
+			index, max = i, score
+		}
+	}
+	return index
+}
+
+// splitmix64 finalizer
+func mix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// EndNodes returns the nodes of ends, the weight is parsed from meta only when hashing by weighted
+func EndNodes(hashby string, ends []geminio.End) []Node {
+	nodes := make([]Node, len(ends))
+	for i, end := range ends {
+		nodes[i] = Node{ID: end.ClientID(), Weight: 1}
+		if hashby != "weighted" {
+			continue
+		}
+		meta := &apis.Meta{}
+		if err := json.Unmarshal(end.Meta(), meta); err == nil && meta.Weight > 0 {
+			nodes[i].Weight = meta.Weight
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,54 @@
+package misc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHashConsistent(t *testing.T) {
+	nodes := []Node{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+	before := map[uint64]uint64{}
+	for edgeID := uint64(0); edgeID < 10000; edgeID++ {
+		before[edgeID] = nodes[Hash("consistent", nodes, edgeID, nil)].ID
+	}
+	// a node joins, only edges moving to it are remapped
+	nodes = append(nodes, Node{ID: 5})
+	moved := 0
+	for edgeID := uint64(0); edgeID < 10000; edgeID++ {
+		id := nodes[Hash("consistent", nodes, edgeID, nil)].ID
+		if id == before[edgeID] {
+			continue
+		}
+		if id != 5 {
+			t.Fatalf("edge: %d remapped from %d to %d", edgeID, before[edgeID], id)
+		}
+		moved++
+	}
+	if moved < 1500 || moved > 2500 {
+		t.Fatalf("moved %d edges, want about 2000", moved)
+	}
+	// a node leaves, only edges on it are remapped
+	nodes = []Node{{ID: 1}, {ID: 2}, {ID: 4}}
+	for edgeID := uint64(0); edgeID < 10000; edgeID++ {
+		id := nodes[Hash("consistent", nodes, edgeID, nil)].ID
+		if before[edgeID] != 3 && id != before[edgeID] {
+			t.Fatalf("edge: %d remapped from %d to %d", edgeID, before[edgeID], id)
+		}
+	}
+}
+
+func TestHashWeighted(t *testing.T) {
+	nodes := []Node{{ID: 1, Weight: 1}, {ID: 2, Weight: 3}, {ID: 3}}
+	counts := make([]int, len(nodes))
+	for edgeID := uint64(0); edgeID < 50000; edgeID++ {
+		counts[Hash("weighted", nodes, edgeID, nil)]++
+	}
+	// weight 0 is treated as 1
+	want := []float64{0.2, 0.6, 0.2}
+	for i, count := range counts {
+		got := float64(count) / 50000
+		if math.Abs(got-want[i]) > 0.02 {
+			t.Fatalf("node: %d got %.3f of edges, want %.3f", nodes[i].ID, got, want[i])
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package mq
 
 import (
+	"fmt"
+	"hash/fnv"
+	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/singchia/frontier/pkg/frontier/apis"
 	"github.com/singchia/frontier/pkg/frontier/config"
@@ -17,6 +21,8 @@ type mqManager struct {
 	mtx     sync.RWMutex
 	mqs     map[string][]apis.MQ // key: topic, value: mqs
 	mqindex map[string]*uint64   // for round robin
+	// topics with wildcards
+	trie *topicTrie
 }
 
 func NewMQM(config *config.Configuration) (apis.MQM, error) {
@@ -27,6 +33,7 @@ func newMQManager(config *config.Configuration) (*mqManager, error) {
 	mqm := &mqManager{
 		mqs:     make(map[string][]apis.MQ),
 		mqindex: make(map[string]*uint64),
+		trie:    newTopicTrie(),
 		conf:    config,
 	}
 	conf := config.MQM
@@ -83,12 +90,17 @@ func (mqm *mqManager) AddMQ(topics []string, mq apis.MQ) {
 			klog.V(2).Infof("mq manager, add topic: %s mq succeed", topic)
 			mqm.mqs[topic] = []apis.MQ{mq}
 			mqm.mqindex[topic] = new(uint64)
+			if isWildcard(topic) {
+				mqm.trie.add(topic)
+			}
 			continue
 		}
+		existed := false
 		for _, exist := range mqs {
 			if exist == mq {
 				klog.V(2).Infof("mq manager, add topic: %s mq existed", topic)
-				continue
+				existed = true
+				break
 			}
 			// special handle for service, a deep comparison
 			left, ok := exist.(*mqService)
@@ -96,10 +108,14 @@ func (mqm *mqManager) AddMQ(topics []string, mq apis.MQ) {
 				right, ok := mq.(*mqService)
 				if ok && left.end == right.end {
 					klog.V(2).Infof("mq manager, add topic: %s service mq existed", topic)
-					continue
+					existed = true
+					break
 				}
 			}
 		}
+		if existed {
+			continue
+		}
 		mqs = append(mqs, mq)
 		mqm.mqs[topic] = mqs
 		klog.V(2).Infof("mq mqnager, add topic: %s mq succeed", topic)
@@ -128,6 +144,7 @@ func (mqm *mqManager) DelMQ(mq apis.MQ) {
 			// delete array of this topic
 			delete(mqm.mqs, topic)
 			delete(mqm.mqindex, topic)
+			mqm.trie.del(topic)
 			continue
 		}
 		mqm.mqs[topic] = news
@@ -135,11 +152,14 @@ func (mqm *mqManager) DelMQ(mq apis.MQ) {
 }
 
 // special handle for service, a deep comparison
-func (mqm *mqManager) DelMQByEnd(end geminio.End) {
+func (mqm *mqManager) DelMQByEnd(end geminio.End, topics ...string) {
 	mqm.mtx.Lock()
 	defer mqm.mtx.Unlock()
 
 	for topic, mqs := range mqm.mqs {
+		if len(topics) != 0 && !slices.Contains(topics, topic) {
+			continue
+		}
 		news := []apis.MQ{}
 		for _, exist := range mqs {
 			left, ok := exist.(*mqService)
@@ -154,16 +174,31 @@ func (mqm *mqManager) DelMQByEnd(end geminio.End) {
 		if len(news) == 0 {
 			delete(mqm.mqs, topic)
 			delete(mqm.mqindex, topic)
+			mqm.trie.del(topic)
 			continue
 		}
 		mqm.mqs[topic] = news
 	}
 }
 
+// match returns the subscription of the topic, the exact one goes first and then the most specific wildcard one,
+// must be called with mtx held
+func (mqm *mqManager) match(topic string) string {
+	if _, ok := mqm.mqs[topic]; ok {
+		return topic
+	}
+	pattern, ok := mqm.trie.match(topic)
+	if ok {
+		return pattern
+	}
+	return topic
+}
+
 func (mqm *mqManager) GetMQ(topic string) apis.MQ {
 	mqm.mtx.RLock()
 	defer mqm.mtx.RUnlock()
 
+	topic = mqm.match(topic)
 	mqs, ok := mqm.mqs[topic]
 	if !ok {
 		return nil
@@ -175,10 +210,36 @@ func (mqm *mqManager) GetMQ(topic string) apis.MQ {
 	return mqs[i]
 }
 
+// GetMQs returns the mqs of all subscriptions matching the topic, each delivery group
+// takes the mqs from its most specific subscription
 func (mqm *mqManager) GetMQs(topic string) []apis.MQ {
 	mqm.mtx.RLock()
 	defer mqm.mtx.RUnlock()
-	return mqm.mqs[topic]
+
+	patterns := mqm.trie.matchAll(topic)
+	if _, ok := mqm.mqs[topic]; ok {
+		patterns = append([]string{topic}, patterns...)
+	}
+	if len(patterns) == 1 {
+		return mqm.mqs[patterns[0]]
+	}
+	var ret []apis.MQ
+	taken := map[string]struct{}{}
+	for _, pattern := range patterns {
+		groups := map[string]struct{}{}
+		for _, mq := range mqm.mqs[pattern] {
+			group := mqGroup(mq)
+			if _, ok := taken[group]; ok {
+				continue
+			}
+			groups[group] = struct{}{}
+			ret = append(ret, mq)
+		}
+		for group := range groups {
+			taken[group] = struct{}{}
+		}
+	}
+	return ret
 }
 
 func (mqm *mqManager) Produce(topic string, data []byte, opts ...apis.OptionProduce) error {
@@ -190,15 +251,68 @@ func (mqm *mqManager) Produce(topic string, data []byte, opts ...apis.OptionProd
 			klog.V(2).Infof("mq manager, get mq nil, topic: %s err: %s", topic, err)
 			return err
 		}
+		mqs = []apis.MQ{mq}
 	}
 	// TODO optimize the logic
 	opt := &apis.ProduceOption{}
 	for _, fun := range opts {
 		fun(opt)
 	}
-	index := misc.Hash(mqm.conf.Exchange.HashBy, len(mqs), opt.EdgeID, opt.Addr)
+	// mqs in the same group share messages, and each group gets a copy
+	groups := mqGroups(mqs)
+	if len(groups) == 1 {
+		return mqm.produce(groups[0], topic, data, opt, opts...)
+	}
+	errs := make([]error, len(groups))
+	wg := sync.WaitGroup{}
+	wg.Add(len(groups))
+	for i, group := range groups {
+		go func(i int, group []apis.MQ) {
+			defer wg.Done()
+			errs[i] = mqm.produce(group, topic, data, opt, opts...)
+		}(i, group)
+	}
+	wg.Wait()
+	// the groups delivered would get duplicates if the edge retried, so it fails only if none is delivered
+	var reterr error
+	delivered := false
+	for i, err := range errs {
+		if err == nil {
+			delivered = true
+			continue
+		}
+		klog.V(1).Infof("mq manager, produce topic: %s to group: %s err: %s", topic, mqGroup(groups[i][0]), err)
+		if reterr == nil {
+			reterr = err
+		}
+	}
+	if delivered {
+		return nil
+	}
+	return reterr
+}
+
+func (mqm *mqManager) produce(mqs []apis.MQ, topic string, data []byte, opt *apis.ProduceOption, opts ...apis.OptionProduce) error {
+	if opt.Breaker != nil {
+		// skip the tripped service instances
+		mqs = ready(mqs, opt.Breaker)
+		if len(mqs) == 0 {
+			klog.V(2).Infof("mq manager, all service instances of topic: %s tripped", topic)
+			return apis.ErrCircuitOpen
+		}
+	}
+	index := misc.Hash(mqm.conf.Exchange.HashBy, mqNodes(mqm.conf.Exchange.HashBy, mqs), opt.EdgeID, opt.Addr)
 	mq := mqs[index]
+	if service, ok := mq.(*mqService); ok && opt.Breaker != nil && !opt.Breaker.Allow(service.end.ClientID()) {
+		// the probe to the half-open instance is taken by another message
+		klog.V(2).Infof("mq manager, service instance: %d of topic: %s is probing", service.end.ClientID(), topic)
+		return apis.ErrCircuitOpen
+	}
+	start := time.Now()
 	err := mq.Produce(topic, data, opts...)
+	if service, ok := mq.(*mqService); ok && opt.Breaker != nil {
+		opt.Breaker.Record(service.end.ClientID(), err, time.Since(start))
+	}
 	if err != nil {
 		klog.Errorf("mq manager, produce topic: %s message err: %s", topic, err)
 		return err
@@ -207,6 +321,58 @@ func (mqm *mqManager) Produce(topic string, data []byte, opts ...apis.OptionProd
 	return nil
 }
 
+// ready returns the mqs except the tripped service instances, the states are not changed
+func ready(mqs []apis.MQ, breaker apis.Breaker) []apis.MQ {
+	ret := make([]apis.MQ, 0, len(mqs))
+	for _, mq := range mqs {
+		if service, ok := mq.(*mqService); ok && !breaker.Ready(service.end.ClientID()) {
+			continue
+		}
+		ret = append(ret, mq)
+	}
+	return ret
+}
+
+// mqGroups splits mqs by delivery group in order, external mqs and services without group are in the default group
+func mqGroups(mqs []apis.MQ) [][]apis.MQ {
+	groups := [][]apis.MQ{}
+	index := map[string]int{}
+	for _, mq := range mqs {
+		group := mqGroup(mq)
+		i, ok := index[group]
+		if !ok {
+			i = len(groups)
+			index[group] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], mq)
+	}
+	return groups
+}
+
+// external mqs and services without group are in the default group
+func mqGroup(mq apis.MQ) string {
+	if service, ok := mq.(*mqService); ok {
+		return service.group
+	}
+	return ""
+}
+
+// mqNodes returns the nodes of mqs, services are identified by their IDs and others by their kinds
+func mqNodes(hashby string, mqs []apis.MQ) []misc.Node {
+	nodes := make([]misc.Node, len(mqs))
+	for i, mq := range mqs {
+		if service, ok := mq.(*mqService); ok {
+			nodes[i] = misc.EndNodes(hashby, []geminio.End{service.end})[0]
+			continue
+		}
+		h := fnv.New64a()
+		h.Write([]byte(fmt.Sprintf("%T", mq)))
+		nodes[i] = misc.Node{ID: h.Sum64(), Weight: 1}
+	}
+	return nodes
+}
+
 func (mqm *mqManager) Close() error {
 	mqm.mtx.RLock()
 	defer mqm.mtx.RUnlock()
@@ -0,0 +1,42 @@
+package mq
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/frontier/pkg/frontier/config"
+)
+
+type testMQ struct {
+	topics []string
+}
+
+func (mq *testMQ) Produce(topic string, _ []byte, _ ...apis.OptionProduce) error {
+	mq.topics = append(mq.topics, topic)
+	return nil
+}
+
+func (mq *testMQ) Close() error {
+	return nil
+}
+
+func TestProduceFallback(t *testing.T) {
+	mqm, err := newMQManager(&config.Configuration{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mqm.Produce("news", []byte("hi")); err != apis.ErrTopicNotOnline {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	// topics without mqs go to the mq of "*"
+	mq := &testMQ{}
+	mqm.AddMQ([]string{"*"}, mq)
+	if err := mqm.Produce("news", []byte("hi")); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(mq.topics, []string{"news"}) {
+		t.Fatalf("unexpected produced topics: %v", mq.topics)
+	}
+}
@@ -3,6 +3,7 @@ package mq
 import (
 	"context"
 	"encoding/binary"
+	"encoding/json"
 	"time"
 
 	"github.com/singchia/frontier/pkg/frontier/apis"
@@ -13,10 +14,20 @@ import (
 
 type mqService struct {
 	end geminio.End
+	// delivery group declared in meta
+	group string
+	// envelope version known by the service
+	envelope int
 }
 
 func NewMQServiceFromEnd(end geminio.End) apis.MQ {
-	return &mqService{end}
+	mq := &mqService{end: end}
+	meta := &apis.Meta{}
+	if err := json.Unmarshal(end.Meta(), meta); err == nil {
+		mq.group = meta.Group
+		mq.envelope = meta.Envelope
+	}
+	return mq
 }
 
 func (mq *mqService) Produce(topic string, data []byte, opts ...apis.OptionProduce) error {
@@ -26,15 +37,20 @@ func (mq *mqService) Produce(topic string, data []byte, opts ...apis.OptionProdu
 	}
 	message := opt.Origin.(geminio.Message)
 	edgeID := opt.EdgeID
-	tail := make([]byte, 8)
-	binary.BigEndian.PutUint64(tail, edgeID)
 
-	// we record the edgeID to service
-	custom := message.Custom()
-	if custom == nil {
-		custom = tail
+	// we record the edgeID to service, the origin may be produced to more than one group
+	custom, headers, _ := apis.DecodeEnvelope(message.Custom())
+	if mq.envelope < 1 {
+		tail := make([]byte, 8)
+		binary.BigEndian.PutUint64(tail, edgeID)
+		custom = append(custom[:len(custom):len(custom)], tail...)
 	} else {
-		custom = append(custom, tail...)
+		out := apis.Headers{}
+		for key, value := range headers {
+			out[key] = value
+		}
+		out.SetUint64(apis.HeaderEdgeID, edgeID)
+		custom = apis.EncodeEnvelope(custom, out)
 	}
 	// new message
 	mopt := options.NewMessage()
@@ -0,0 +1,157 @@
+package mq
+
+import "strings"
+
+// topicTrie matches topics against MQTT style subscriptions, levels are separated by "/",
+// "+" matches exactly one level and "#" as the last level matches any remaining levels including none.
+//
+// When more than one subscription matches, the most specific one wins, subscriptions are compared
+// level by level from left to right, and at the first differing level a literal beats "+" and "+" beats "#".
+// Like MQTT, topics beginning with "$" are not matched by wildcards at the first level.
+type topicTrie struct {
+	root *topicNode
+}
+
+type topicNode struct {
+	children map[string]*topicNode
+	// the subscription ends here
+	pattern string
+	end     bool
+}
+
+func newTopicNode() *topicNode {
+	return &topicNode{children: map[string]*topicNode{}}
+}
+
+func newTopicTrie() *topicTrie {
+	return &topicTrie{root: newTopicNode()}
+}
+
+// isWildcard returns true if the pattern is a valid subscription with wildcards
+func isWildcard(pattern string) bool {
+	wildcard := false
+	levels := strings.Split(pattern, "/")
+	for i, level := range levels {
+		switch {
+		case level == "+":
+			wildcard = true
+		case level == "#":
+			if i != len(levels)-1 {
+				return false
+			}
+			wildcard = true
+		case strings.ContainsAny(level, "+#"):
+			return false
+		}
+	}
+	return wildcard
+}
+
+func (trie *topicTrie) add(pattern string) {
+	node := trie.root
+	for _, level := range strings.Split(pattern, "/") {
+		child, ok := node.children[level]
+		if !ok {
+			child = newTopicNode()
+			node.children[level] = child
+		}
+		node = child
+	}
+	node.pattern = pattern
+	node.end = true
+}
+
+func (trie *topicTrie) del(pattern string) {
+	levels := strings.Split(pattern, "/")
+	path := make([]*topicNode, 0, len(levels)+1)
+	node := trie.root
+	path = append(path, node)
+	for _, level := range levels {
+		child, ok := node.children[level]
+		if !ok {
+			return
+		}
+		node = child
+		path = append(path, node)
+	}
+	node.end = false
+	node.pattern = ""
+	// prune the empty branch
+	for i := len(levels) - 1; i >= 0; i-- {
+		child := path[i+1]
+		if child.end || len(child.children) != 0 {
+			break
+		}
+		delete(path[i].children, levels[i])
+	}
+}
+
+// match returns the most specific subscription matching the topic
+func (trie *topicTrie) match(topic string) (string, bool) {
+	levels := strings.Split(topic, "/")
+	return trie.root.match(levels, !strings.HasPrefix(topic, "$"))
+}
+
+// children are visited in the order of precedence, so the first found is the most specific
+func (node *topicNode) match(levels []string, wildcard bool) (string, bool) {
+	if len(levels) == 0 {
+		if node.end {
+			return node.pattern, true
+		}
+		// "a/#" matches "a"
+		if child, ok := node.children["#"]; ok && child.end {
+			return child.pattern, true
+		}
+		return "", false
+	}
+	level := levels[0]
+	if child, ok := node.children[level]; ok && level != "+" && level != "#" {
+		if pattern, ok := child.match(levels[1:], true); ok {
+			return pattern, true
+		}
+	}
+	if !wildcard {
+		return "", false
+	}
+	if child, ok := node.children["+"]; ok {
+		if pattern, ok := child.match(levels[1:], true); ok {
+			return pattern, true
+		}
+	}
+	if child, ok := node.children["#"]; ok && child.end {
+		return child.pattern, true
+	}
+	return "", false
+}
+
+// matchAll returns all subscriptions matching the topic, the more specific goes first
+func (trie *topicTrie) matchAll(topic string) []string {
+	levels := strings.Split(topic, "/")
+	return trie.root.matchAll(levels, !strings.HasPrefix(topic, "$"), nil)
+}
+
+func (node *topicNode) matchAll(levels []string, wildcard bool, patterns []string) []string {
+	if len(levels) == 0 {
+		if node.end {
+			patterns = append(patterns, node.pattern)
+		}
+		if child, ok := node.children["#"]; ok && child.end {
+			patterns = append(patterns, child.pattern)
+		}
+		return patterns
+	}
+	level := levels[0]
+	if child, ok := node.children[level]; ok && level != "+" && level != "#" {
+		patterns = child.matchAll(levels[1:], true, patterns)
+	}
+	if !wildcard {
+		return patterns
+	}
+	if child, ok := node.children["+"]; ok {
+		patterns = child.matchAll(levels[1:], true, patterns)
+	}
+	if child, ok := node.children["#"]; ok && child.end {
+		patterns = append(patterns, child.pattern)
+	}
+	return patterns
+}
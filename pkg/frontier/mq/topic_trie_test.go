@@ -0,0 +1,81 @@
+package mq
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTopicTrieMatch(t *testing.T) {
+	trie := newTopicTrie()
+	for _, pattern := range []string{
+		"telemetry/+/+/temp",
+		"telemetry/site1/+/temp",
+		"telemetry/#",
+		"telemetry/site1/#",
+		"+/site2/dev1/temp",
+		"#",
+	} {
+		trie.add(pattern)
+	}
+	tests := []struct {
+		topic string
+		want  string
+	}{
+		{"telemetry/site1/dev1/temp", "telemetry/site1/+/temp"},
+		{"telemetry/site2/dev1/temp", "telemetry/+/+/temp"},
+		{"telemetry/site1/dev1/humidity", "telemetry/site1/#"},
+		{"telemetry/site2/dev1/humidity", "telemetry/#"},
+		{"telemetry", "telemetry/#"},
+		{"alarm/site2/dev1/temp", "+/site2/dev1/temp"},
+		{"alarm", "#"},
+		// wildcards don't match the first level beginning with "$"
+		{"$edge/1/temp", ""},
+	}
+	for _, tt := range tests {
+		got, _ := trie.match(tt.topic)
+		if got != tt.want {
+			t.Errorf("match(%q) = %q, want %q", tt.topic, got, tt.want)
+		}
+	}
+
+	trie.del("telemetry/site1/+/temp")
+	trie.del("#")
+	if got, _ := trie.match("telemetry/site1/dev1/temp"); got != "telemetry/site1/#" {
+		t.Errorf("match after del = %q, want %q", got, "telemetry/site1/#")
+	}
+	if _, ok := trie.match("alarm"); ok {
+		t.Errorf("match after del should fail")
+	}
+}
+
+func TestIsWildcard(t *testing.T) {
+	tests := map[string]bool{
+		"telemetry/+/temp": true,
+		"telemetry/#":      true,
+		"#":                true,
+		"telemetry/temp":   false,
+		"telemetry/#/temp": false,
+		"telemetry/a+/b":   false,
+		"*":                false,
+	}
+	for pattern, want := range tests {
+		if got := isWildcard(pattern); got != want {
+			t.Errorf("isWildcard(%q) = %v, want %v", pattern, got, want)
+		}
+	}
+}
+
+func TestTopicTrieMatchAll(t *testing.T) {
+	trie := newTopicTrie()
+	for _, pattern := range []string{"telemetry/#", "telemetry/+/temp", "+/site1/temp", "telemetry/site1/#", "#"} {
+		trie.add(pattern)
+	}
+	got := trie.matchAll("telemetry/site1/temp")
+	want := []string{"telemetry/site1/#", "telemetry/+/temp", "telemetry/#", "+/site1/temp", "#"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("matchAll = %v, want %v", got, want)
+	}
+	if got := trie.matchAll("$edge/1"); len(got) != 0 {
+		t.Errorf("matchAll($edge/1) = %v, want none", got)
+	}
+}
@@ -0,0 +1,167 @@
+package raw
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// Conn is the sdk side of raw io, it reads frames from the main End all the time,
+// and a writer never sends more than the window granted by the receiver,
+// so the raw io not read never blocks messages and rpcs on the same End.
+type Conn struct {
+	rw io.ReadWriter
+
+	mtx  sync.Mutex
+	cond *sync.Cond
+	// bytes we can send to the peer, absent means Window
+	credits map[Peer]int
+	// bytes read from the peer and not granted back yet
+	consumed map[Peer]int
+	// data dropped by frontier, returned by the next Write to the peer
+	errs  map[Peer]error
+	queue []*Frame
+	err   error
+}
+
+func NewConn(rw io.ReadWriter) *Conn {
+	conn := &Conn{
+		rw:       rw,
+		credits:  map[Peer]int{},
+		consumed: map[Peer]int{},
+		errs:     map[Peer]error{},
+	}
+	conn.cond = sync.NewCond(&conn.mtx)
+	go conn.readLoop()
+	return conn
+}
+
+func (conn *Conn) readLoop() {
+	buf := make([]byte, MaxFrame)
+	for {
+		n, err := conn.rw.Read(buf)
+		if err != nil {
+			conn.fini(err)
+			return
+		}
+		frame, err := Decode(buf[:n])
+		if err != nil {
+			continue
+		}
+		conn.mtx.Lock()
+		switch frame.Type {
+		case FrameData:
+			data := make([]byte, len(frame.Data))
+			copy(data, frame.Data)
+			frame.Data = data
+			conn.queue = append(conn.queue, frame)
+		case FrameWindow:
+			conn.credits[frame.Peer] = conn.credit(frame.Peer) + int(frame.N)
+		case FrameReset:
+			// frontier is a new one
+			conn.credits = map[Peer]int{}
+			conn.consumed = map[Peer]int{}
+			conn.errs = map[Peer]error{}
+		case FrameError:
+			conn.errs[frame.Peer] = errors.New(string(frame.Data))
+		}
+		conn.cond.Broadcast()
+		conn.mtx.Unlock()
+	}
+}
+
+// must be called with mtx held
+func (conn *Conn) credit(peer Peer) int {
+	credit, ok := conn.credits[peer]
+	if !ok {
+		return Window
+	}
+	return credit
+}
+
+// Read returns data from any peer
+func (conn *Conn) Read() (Peer, []byte, error) {
+	conn.mtx.Lock()
+	for len(conn.queue) == 0 && conn.err == nil {
+		conn.cond.Wait()
+	}
+	if len(conn.queue) == 0 {
+		err := conn.err
+		conn.mtx.Unlock()
+		return Peer{}, nil, err
+	}
+	frame := conn.queue[0]
+	conn.queue[0] = nil
+	conn.queue = conn.queue[1:]
+	// grant back when half of the window is consumed
+	grant := 0
+	consumed := conn.consumed[frame.Peer] + len(frame.Data)
+	if consumed >= Window/2 {
+		grant = consumed
+		delete(conn.consumed, frame.Peer)
+	} else {
+		conn.consumed[frame.Peer] = consumed
+	}
+	conn.mtx.Unlock()
+
+	if grant > 0 {
+		conn.writeFrame(&Frame{Type: FrameWindow, Peer: frame.Peer, N: uint32(grant)})
+	}
+	return frame.Peer, frame.Data, nil
+}
+
+// Write blocks until all data is sent or the conn is closed, the data frontier failed to deliver
+// before fails the next Write to the peer
+func (conn *Conn) Write(peer Peer, data []byte) (int, error) {
+	written := 0
+	for len(data) > 0 {
+		conn.mtx.Lock()
+		for conn.credit(peer) <= 0 && conn.err == nil && conn.errs[peer] == nil {
+			conn.cond.Wait()
+		}
+		if conn.err != nil {
+			err := conn.err
+			conn.mtx.Unlock()
+			return written, err
+		}
+		if err, ok := conn.errs[peer]; ok {
+			delete(conn.errs, peer)
+			conn.mtx.Unlock()
+			return written, err
+		}
+		n := min(conn.credit(peer), len(data), MaxPayload)
+		conn.credits[peer] = conn.credit(peer) - n
+		conn.mtx.Unlock()
+
+		err := conn.writeFrame(&Frame{Type: FrameData, Peer: peer, Data: data[:n]})
+		if err != nil {
+			return written, err
+		}
+		written += n
+		data = data[n:]
+	}
+	return written, nil
+}
+
+func (conn *Conn) writeFrame(frame *Frame) error {
+	buf, err := frame.Encode()
+	if err != nil {
+		return err
+	}
+	_, err = conn.rw.Write(buf)
+	return err
+}
+
+func (conn *Conn) fini(err error) {
+	conn.mtx.Lock()
+	defer conn.mtx.Unlock()
+	if conn.err == nil {
+		conn.err = err
+	}
+	conn.cond.Broadcast()
+}
+
+// Close wakes up all the blocking Read and Write, the End should be closed by the owner
+func (conn *Conn) Close() {
+	conn.fini(io.EOF)
+}
@@ -0,0 +1,101 @@
+package raw
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// raw io on the main connection is framed, every frame is written as one geminio stream packet,
+// so frames are never split by reading with a MaxFrame buffer.
+//
+// | type 1 byte | id 8 bytes | name length 1 byte | name | n 4 bytes | data |
+//
+// the peer of a frame is the service name at edge side, and the edgeID at service side.
+const (
+	// data to or from the peer
+	FrameData byte = 1
+	// the receiver grants n more bytes from the peer
+	FrameWindow byte = 2
+	// frontier resets all windows, sent when the connection comes
+	FrameReset byte = 3
+	// data to the peer dropped by frontier, the data of the frame is the reason
+	FrameError byte = 4
+)
+
+const (
+	// initial window of every peer in bytes
+	Window = 256 * 1024
+	// max data carried by one frame
+	MaxPayload = 32 * 1024
+	// max name length of a peer
+	MaxName = 255
+
+	headerLen = 1 + 8 + 1 + 4
+	MaxFrame  = headerLen + MaxName + MaxPayload
+)
+
+var (
+	ErrIllegalFrame = errors.New("illegal raw frame")
+	ErrNameTooLong  = errors.New("raw peer name too long")
+)
+
+// Peer is the other side of raw io, only one of the fields is set
+type Peer struct {
+	ID   uint64
+	Name string
+}
+
+type Frame struct {
+	Type byte
+	Peer Peer
+	N    uint32
+	Data []byte
+}
+
+func (frame *Frame) Encode() ([]byte, error) {
+	name := frame.Peer.Name
+	if len(name) > MaxName {
+		return nil, ErrNameTooLong
+	}
+	buf := make([]byte, headerLen+len(name)+len(frame.Data))
+	buf[0] = frame.Type
+	binary.BigEndian.PutUint64(buf[1:9], frame.Peer.ID)
+	buf[9] = byte(len(name))
+	pos := 10 + copy(buf[10:], name)
+	n := frame.N
+	if frame.Type == FrameData {
+		n = uint32(len(frame.Data))
+	}
+	binary.BigEndian.PutUint32(buf[pos:pos+4], n)
+	copy(buf[pos+4:], frame.Data)
+	return buf, nil
+}
+
+// Decode a frame, the data refers to buf
+func Decode(buf []byte) (*Frame, error) {
+	if len(buf) < headerLen {
+		return nil, ErrIllegalFrame
+	}
+	frame := &Frame{
+		Type: buf[0],
+		Peer: Peer{ID: binary.BigEndian.Uint64(buf[1:9])},
+	}
+	nameLen := int(buf[9])
+	if len(buf) < headerLen+nameLen {
+		return nil, ErrIllegalFrame
+	}
+	frame.Peer.Name = string(buf[10 : 10+nameLen])
+	pos := 10 + nameLen
+	frame.N = binary.BigEndian.Uint32(buf[pos : pos+4])
+	frame.Data = buf[pos+4:]
+	switch frame.Type {
+	case FrameData:
+		if int(frame.N) != len(frame.Data) || len(frame.Data) > MaxPayload {
+			return nil, ErrIllegalFrame
+		}
+	case FrameWindow, FrameReset, FrameError:
+	default:
+		return nil, ErrIllegalFrame
+	}
+	return frame, nil
+}
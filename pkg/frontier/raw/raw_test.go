@@ -0,0 +1,149 @@
+package raw
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+// packetRW keeps the boundaries of writes like a geminio End
+type packetRW struct {
+	in  chan []byte
+	out chan []byte
+}
+
+func (rw *packetRW) Read(b []byte) (int, error) {
+	data, ok := <-rw.in
+	if !ok {
+		return 0, io.EOF
+	}
+	return copy(b, data), nil
+}
+
+func (rw *packetRW) Write(b []byte) (int, error) {
+	data := make([]byte, len(b))
+	copy(data, b)
+	rw.out <- data
+	return len(b), nil
+}
+
+func TestFrameEncodeDecode(t *testing.T) {
+	frames := []*Frame{
+		{Type: FrameData, Peer: Peer{Name: "raw-svc"}, Data: []byte("hello")},
+		{Type: FrameData, Peer: Peer{ID: 10086}, Data: []byte{}},
+		{Type: FrameWindow, Peer: Peer{ID: 10086}, N: Window},
+		{Type: FrameReset},
+		{Type: FrameError, Peer: Peer{Name: "raw-svc"}, Data: []byte("service not online")},
+	}
+	for _, frame := range frames {
+		buf, err := frame.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Decode(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Type != frame.Type || got.Peer != frame.Peer || !bytes.Equal(got.Data, frame.Data) {
+			t.Fatalf("decode got %+v, want %+v", got, frame)
+		}
+	}
+	if _, err := Decode([]byte{FrameData, 0}); err != ErrIllegalFrame {
+		t.Fatalf("decode short frame err: %v", err)
+	}
+}
+
+func TestConnFlowControl(t *testing.T) {
+	in, out := make(chan []byte, 64), make(chan []byte, 64)
+	conn := NewConn(&packetRW{in: in, out: out})
+	defer conn.Close()
+
+	peer := Peer{Name: "raw-svc"}
+	done := make(chan struct{})
+	go func() {
+		conn.Write(peer, make([]byte, Window+MaxPayload))
+		close(done)
+	}()
+
+	// only the window is sent before granting
+	sent := 0
+	for sent < Window {
+		frame, err := Decode(<-out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sent += len(frame.Data)
+	}
+	select {
+	case <-done:
+		t.Fatal("write returned before granting")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	buf, _ := (&Frame{Type: FrameWindow, Peer: peer, N: MaxPayload}).Encode()
+	in <- buf
+	frame, err := Decode(<-out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frame.Data) != MaxPayload {
+		t.Fatalf("sent %d bytes after granting, want %d", len(frame.Data), MaxPayload)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("write blocked after granting")
+	}
+
+	// the reader grants back when half of the window is consumed
+	for i := 0; i < Window/2/MaxPayload; i++ {
+		buf, _ := (&Frame{Type: FrameData, Peer: peer, Data: make([]byte, MaxPayload)}).Encode()
+		in <- buf
+		if _, _, err := conn.Read(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	frame, err = Decode(<-out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if frame.Type != FrameWindow || frame.N != Window/2 {
+		t.Fatalf("got frame %+v, want window %d", frame, Window/2)
+	}
+}
+
+func TestConnPeerError(t *testing.T) {
+	in, out := make(chan []byte, 64), make(chan []byte, 64)
+	conn := NewConn(&packetRW{in: in, out: out})
+	defer conn.Close()
+
+	peer := Peer{Name: "raw-svc"}
+	if _, err := conn.Write(peer, []byte("lost")); err != nil {
+		t.Fatal(err)
+	}
+	<-out
+	buf, _ := (&Frame{Type: FrameError, Peer: peer, Data: []byte("service not online")}).Encode()
+	in <- buf
+	time.Sleep(20 * time.Millisecond)
+
+	// other peers not affected
+	if _, err := conn.Write(Peer{Name: "other-svc"}, []byte("ok")); err != nil {
+		t.Fatal(err)
+	}
+	<-out
+	// the next write to the peer fails, and the one after is sent
+	if _, err := conn.Write(peer, []byte("again")); err == nil || err.Error() != "service not online" {
+		t.Fatalf("write after dropped err: %v", err)
+	}
+	if _, err := conn.Write(peer, []byte("again")); err != nil {
+		t.Fatal(err)
+	}
+	frame, err := Decode(<-out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(frame.Data) != "again" {
+		t.Fatalf("sent %q, want again", frame.Data)
+	}
+}
@@ -15,6 +15,9 @@ const (
 	IdxEdgeRPC_RPC             = "idx_edgerpc_rpc"
 	IdxEdgeRPC_EdgeID          = "idx_edgerpc_edge_id"
 	IdxEdgeRPC_CreateTime      = "idx_edgerpc_create_time"
+	IdxEdgeTopic_Topic         = "idx_edgetopic_topic"
+	IdxEdgeTopic_EdgeID        = "idx_edgetopic_edge_id"
+	IdxEdgeLabel_Key           = "idx_edgelabel_key"
 	IdxService_Service         = "idx_service_service"
 	IdxService_Addr            = "idx_service_addr"
 	IdxService_CreateTime      = "index_service_create_time"
@@ -70,6 +73,20 @@ func NewDao(config *config.Configuration) (*dao, error) {
 	if err != nil {
 		return nil, err
 	}
+	// edgeTopic's indexes
+	err = db.CreateIndex(IdxEdgeTopic_Topic, "edge_topics*", buntdb.IndexJSON("topic"))
+	if err != nil {
+		return nil, err
+	}
+	err = db.CreateIndex(IdxEdgeTopic_EdgeID, "edge_topics*", buntdb.IndexJSON("edge_id"))
+	if err != nil {
+		return nil, err
+	}
+	// edgeLabel's indexes
+	err = db.CreateIndex(IdxEdgeLabel_Key, "edge_labels*", buntdb.IndexJSON("key"))
+	if err != nil {
+		return nil, err
+	}
 	// service's indexes
 	err = db.CreateIndex(IdxService_Service, "services*", buntdb.IndexJSON("service"))
 	if err != nil {
@@ -3,9 +3,12 @@ package membuntdb
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/singchia/frontier/pkg/frontier/apis"
+	"github.com/singchia/frontier/pkg/frontier/label"
 	"github.com/singchia/frontier/pkg/frontier/misc"
 	"github.com/singchia/frontier/pkg/frontier/repo/model"
 	"github.com/singchia/frontier/pkg/frontier/repo/query"
@@ -35,6 +38,12 @@ func (dao *dao) ListEdges(query *query.EdgeQuery) ([]*model.Edge, error) {
 		return nil, ErrUnsupportedMultipleFieldsForBuntDB
 	}
 
+	// labels are filtered while iterating edges
+	selected, err := dao.selectEdges(query.Selector)
+	if err != nil {
+		return nil, err
+	}
+
 	// pagination
 	if query.Page <= 0 || query.PageSize <= 0 {
 		query.Page, query.PageSize = 1, 10
@@ -75,7 +84,7 @@ func (dao *dao) ListEdges(query *query.EdgeQuery) ([]*model.Edge, error) {
 			}
 			skip := 0
 			finderr := find(idx, pivot, func(key, value string) bool {
-				edge, keepon, err := edgeMatch(query.Addr, query.Meta, query.StartTime, query.EndTime, offset, size, &skip, desc, value)
+				edge, keepon, err := edgeMatch(query.Addr, query.Meta, selected, query.StartTime, query.EndTime, offset, size, &skip, desc, value)
 				if err != nil {
 					// TODO
 					return true
@@ -103,7 +112,7 @@ func (dao *dao) ListEdges(query *query.EdgeQuery) ([]*model.Edge, error) {
 				greater := fmt.Sprintf(`{"create_time": %d}`, query.StartTime)
 				skip := 0
 				finderr := find(idx, less, greater, func(key, value string) bool {
-					edge, keepon, err := edgeMatch(query.Addr, query.Meta, query.StartTime, query.EndTime, offset, size, &skip, desc, value)
+					edge, keepon, err := edgeMatch(query.Addr, query.Meta, selected, query.StartTime, query.EndTime, offset, size, &skip, desc, value)
 					if err != nil {
 						// TODO
 						return true
@@ -122,7 +131,7 @@ func (dao *dao) ListEdges(query *query.EdgeQuery) ([]*model.Edge, error) {
 				}
 				skip := 0
 				finderr := find(idx, func(key, value string) bool {
-					edge, keepon, err := edgeMatch(query.Addr, query.Meta, query.StartTime, query.EndTime, offset, size, &skip, desc, value)
+					edge, keepon, err := edgeMatch(query.Addr, query.Meta, selected, query.StartTime, query.EndTime, offset, size, &skip, desc, value)
 					if err != nil {
 						// TODO
 						return true
@@ -139,7 +148,7 @@ func (dao *dao) ListEdges(query *query.EdgeQuery) ([]*model.Edge, error) {
 	}
 }
 
-func edgeMatch(addr string, meta string, startTime int64, endTime int64, offset int, size int, skip *int, desc bool, edgeStr string) (*model.Edge, bool, error) {
+func edgeMatch(addr string, meta string, selected func(uint64) bool, startTime int64, endTime int64, offset int, size int, skip *int, desc bool, edgeStr string) (*model.Edge, bool, error) {
 	edge := &model.Edge{}
 	err := json.Unmarshal([]byte(edgeStr), edge)
 	if err != nil {
@@ -160,6 +169,10 @@ func edgeMatch(addr string, meta string, startTime int64, endTime int64, offset
 		// continue
 		return nil, true, nil
 	}
+	// labels unmatch
+	if selected != nil && !selected(edge.EdgeID) {
+		return nil, true, nil
+	}
 	// offset and size
 	defer func() { *skip = *skip + 1 }()
 	if *skip < offset {
@@ -172,6 +185,45 @@ func edgeMatch(addr string, meta string, startTime int64, endTime int64, offset
 	}
 }
 
+// selectEdges loads the labels on the keys of selector, nil means no selector
+func (dao *dao) selectEdges(selector label.Selector) (func(uint64) bool, error) {
+	if len(selector) == 0 {
+		return nil, nil
+	}
+	labels := map[uint64]map[string]string{}
+	err := dao.db.View(func(tx *buntdb.Tx) error {
+		for _, key := range selector.Keys() {
+			pivot, err := json.Marshal(&model.EdgeLabel{Key: key})
+			if err != nil {
+				return err
+			}
+			err = tx.AscendEqual(IdxEdgeLabel_Key, string(pivot), func(_, value string) bool {
+				label := &model.EdgeLabel{}
+				if err := json.Unmarshal([]byte(value), label); err != nil {
+					return true
+				}
+				edgeLabels, ok := labels[label.EdgeID]
+				if !ok {
+					edgeLabels = map[string]string{}
+					labels[label.EdgeID] = edgeLabels
+				}
+				edgeLabels[label.Key] = label.Value
+				return true
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func(edgeID uint64) bool {
+		return selector.Matches(labels[edgeID])
+	}, nil
+}
+
 func (dao *dao) CountEdges(query *query.EdgeQuery) (int64, error) {
 	return 0, ErrUnimplemented
 }
@@ -212,6 +264,31 @@ func (dao *dao) CreateEdge(edge *model.Edge) error {
 	return err
 }
 
+// UpdateEdgeMeta updates the meta of the online edge, the indexes follow
+func (dao *dao) UpdateEdgeMeta(edgeID uint64, meta string) error {
+	err := dao.db.Update(func(tx *buntdb.Tx) error {
+		value, err := tx.Get(getEdgeKey(edgeID))
+		if err != nil {
+			if err == buntdb.ErrNotFound {
+				return apis.ErrRecordNotFound
+			}
+			return err
+		}
+		edge := &model.Edge{}
+		if err = json.Unmarshal([]byte(value), edge); err != nil {
+			return err
+		}
+		edge.Meta = meta
+		data, err := json.Marshal(edge)
+		if err != nil {
+			return err
+		}
+		_, _, err = tx.Set(getEdgeKey(edgeID), string(data), nil)
+		return err
+	})
+	return err
+}
+
 func getEdgeKey(edgeID uint64) string {
 	return "edges:" + strconv.FormatUint(edgeID, 10)
 }
@@ -405,6 +482,24 @@ func (dao *dao) CreateEdgeRPC(rpc *model.EdgeRPC) error {
 	return err
 }
 
+func (dao *dao) GetEdgeRPC(edgeID uint64, rpc string) (*model.EdgeRPC, error) {
+	edgeRPC := &model.EdgeRPC{}
+	err := dao.db.View(func(tx *buntdb.Tx) error {
+		value, err := tx.Get(getEdgeRPCKey(edgeID, rpc))
+		if err != nil {
+			if err == buntdb.ErrNotFound {
+				return apis.ErrRecordNotFound
+			}
+			return err
+		}
+		return json.Unmarshal([]byte(value), edgeRPC)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return edgeRPC, nil
+}
+
 func getEdgeRPCKey(edgeID uint64, rpc string) string {
 	return "edge_rpcs:" + strconv.FormatUint(edgeID, 10) + "-" + rpc
 }
@@ -415,3 +510,156 @@ func getEdgeRPCPrefixKey(edgeID uint64) string {
 	}
 	return "edge_rpcs:"
 }
+
+func (dao *dao) GetEdgeTopics(topic string) ([]*model.EdgeTopic, error) {
+	edgeTopics := []*model.EdgeTopic{}
+	err := dao.db.View(func(tx *buntdb.Tx) error {
+		pivot, err := json.Marshal(&model.EdgeTopic{Topic: topic})
+		if err != nil {
+			return err
+		}
+		err = tx.AscendEqual(IdxEdgeTopic_Topic, string(pivot), func(key, value string) bool {
+			edgeTopic := &model.EdgeTopic{}
+			err := json.Unmarshal([]byte(value), edgeTopic)
+			if err != nil {
+				return true
+			}
+			edgeTopics = append(edgeTopics, edgeTopic)
+			return true
+		})
+		return err
+	})
+	if len(edgeTopics) == 0 {
+		return nil, apis.ErrRecordNotFound
+	}
+	return edgeTopics, err
+}
+
+func (dao *dao) DeleteEdgeTopic(edgeID uint64, topic string) error {
+	err := dao.db.Update(func(tx *buntdb.Tx) error {
+		_, err := tx.Delete(getEdgeTopicKey(edgeID, topic))
+		if err == buntdb.ErrNotFound {
+			return nil
+		}
+		return err
+	})
+	return err
+}
+
+func (dao *dao) DeleteEdgeTopics(edgeID uint64) error {
+	err := dao.db.Update(func(tx *buntdb.Tx) error {
+		var delkeys []string
+		pivot := fmt.Sprintf(`{"edge_id": %d}`, edgeID)
+		tx.AscendEqual(IdxEdgeTopic_EdgeID, pivot, func(key, value string) bool {
+			delkeys = append(delkeys, key)
+			return true
+		})
+		for _, key := range delkeys {
+			if _, err := tx.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+func (dao *dao) CreateEdgeTopic(topic *model.EdgeTopic) error {
+	err := dao.db.Update(func(tx *buntdb.Tx) error {
+		data, err := json.Marshal(topic)
+		if err != nil {
+			return err
+		}
+		_, _, err = tx.Set(getEdgeTopicKey(topic.EdgeID, topic.Topic), string(data), nil)
+		return err
+	})
+	return err
+}
+
+func getEdgeTopicKey(edgeID uint64, topic string) string {
+	return "edge_topics:" + strconv.FormatUint(edgeID, 10) + "-" + topic
+}
+
+func (dao *dao) GetEdgeLabels(edgeID uint64) ([]*model.EdgeLabel, error) {
+	labels := []*model.EdgeLabel{}
+	err := dao.db.View(func(tx *buntdb.Tx) error {
+		return tx.AscendKeys(getEdgeLabelPattern(edgeID), func(_, value string) bool {
+			label := &model.EdgeLabel{}
+			if err := json.Unmarshal([]byte(value), label); err != nil {
+				return true
+			}
+			labels = append(labels, label)
+			return true
+		})
+	})
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].Key < labels[j].Key
+	})
+	return labels, err
+}
+
+// GetEdgesLabels returns the labels of edges by edgeID in one scan, edges without labels are left out
+func (dao *dao) GetEdgesLabels(edgeIDs []uint64) (map[uint64][]*model.EdgeLabel, error) {
+	wanted := make(map[uint64]struct{}, len(edgeIDs))
+	for _, edgeID := range edgeIDs {
+		wanted[edgeID] = struct{}{}
+	}
+	labels := map[uint64][]*model.EdgeLabel{}
+	err := dao.db.View(func(tx *buntdb.Tx) error {
+		return tx.AscendKeys("edge_labels:*", func(_, value string) bool {
+			label := &model.EdgeLabel{}
+			if err := json.Unmarshal([]byte(value), label); err != nil {
+				return true
+			}
+			if _, ok := wanted[label.EdgeID]; ok {
+				labels[label.EdgeID] = append(labels[label.EdgeID], label)
+			}
+			return true
+		})
+	})
+	for _, edgeLabels := range labels {
+		sort.Slice(edgeLabels, func(i, j int) bool {
+			return edgeLabels[i].Key < edgeLabels[j].Key
+		})
+	}
+	return labels, err
+}
+
+func (dao *dao) DeleteEdgeLabels(edgeID uint64) error {
+	err := dao.db.Update(func(tx *buntdb.Tx) error {
+		var delkeys []string
+		tx.AscendKeys(getEdgeLabelPattern(edgeID), func(key, value string) bool {
+			delkeys = append(delkeys, key)
+			return true
+		})
+		for _, key := range delkeys {
+			if _, err := tx.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// CreateEdgeLabel replaces the value if the key is attached again
+func (dao *dao) CreateEdgeLabel(label *model.EdgeLabel) error {
+	err := dao.db.Update(func(tx *buntdb.Tx) error {
+		data, err := json.Marshal(label)
+		if err != nil {
+			return err
+		}
+		_, _, err = tx.Set(getEdgeLabelKey(label.EdgeID, label.Key), string(data), nil)
+		return err
+	})
+	return err
+}
+
+func getEdgeLabelKey(edgeID uint64, key string) string {
+	return "edge_labels:" + strconv.FormatUint(edgeID, 10) + "-" + key
+}
+
+// labels of the edge, the edgeID is matched by key since the json index compares numbers as float64
+func getEdgeLabelPattern(edgeID uint64) string {
+	return "edge_labels:" + strconv.FormatUint(edgeID, 10) + "-*"
+}
@@ -1,9 +1,13 @@
 package membuntdb
 
 import (
+	"reflect"
+	"sort"
 	"testing"
 
+	"github.com/singchia/frontier/pkg/frontier/apis"
 	"github.com/singchia/frontier/pkg/frontier/config"
+	"github.com/singchia/frontier/pkg/frontier/label"
 	"github.com/singchia/frontier/pkg/frontier/repo/model"
 	"github.com/singchia/frontier/pkg/frontier/repo/query"
 )
@@ -165,3 +169,226 @@ func TestListEdgeRPCs(t *testing.T) {
 		t.Error("unmatched length of edge rpcs")
 	}
 }
+
+func TestGetEdgeRPC(t *testing.T) {
+	config := &config.Configuration{}
+	dao, err := NewDao(config)
+	if err != nil {
+		t.Error(err)
+	}
+	defer dao.Close()
+	err = dao.CreateEdgeRPC(&model.EdgeRPC{
+		RPC:        "rpc1",
+		EdgeID:     1,
+		CreateTime: 11,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	edgeRPC, err := dao.GetEdgeRPC(1, "rpc1")
+	if err != nil {
+		t.Error(err)
+	}
+	if edgeRPC.RPC != "rpc1" || edgeRPC.EdgeID != 1 {
+		t.Error("unmatched edge rpc")
+	}
+	// registered by another edge
+	_, err = dao.GetEdgeRPC(2, "rpc1")
+	if err != apis.ErrRecordNotFound {
+		t.Error("unexpected err", err)
+	}
+}
+
+func TestEdgeTopics(t *testing.T) {
+	config := &config.Configuration{}
+	dao, err := NewDao(config)
+	if err != nil {
+		t.Error(err)
+	}
+	defer dao.Close()
+	edgeTopics := []*model.EdgeTopic{
+		{Topic: "topic1", EdgeID: 1, CreateTime: 11},
+		{Topic: "topic1", EdgeID: 2, CreateTime: 12},
+		{Topic: "topic2", EdgeID: 2, CreateTime: 13},
+		// subscribed again
+		{Topic: "topic1", EdgeID: 1, CreateTime: 14},
+	}
+	for _, edgeTopic := range edgeTopics {
+		err = dao.CreateEdgeTopic(edgeTopic)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	retEdgeTopics, err := dao.GetEdgeTopics("topic1")
+	if err != nil {
+		t.Error(err)
+	}
+	if len(retEdgeTopics) != 2 {
+		t.Error("unmatched length of edge topics")
+	}
+
+	// unsubscribe one
+	err = dao.DeleteEdgeTopic(1, "topic1")
+	if err != nil {
+		t.Error(err)
+	}
+	retEdgeTopics, err = dao.GetEdgeTopics("topic1")
+	if err != nil {
+		t.Error(err)
+	}
+	if len(retEdgeTopics) != 1 || retEdgeTopics[0].EdgeID != 2 {
+		t.Error("unmatched edge topics")
+	}
+
+	// edge offline
+	err = dao.DeleteEdgeTopics(2)
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = dao.GetEdgeTopics("topic2")
+	if err == nil {
+		t.Error("edge topics not deleted")
+	}
+
+	// topics are escaped in the pivot
+	for _, topic := range []string{`quote"d`, `back\slash`} {
+		if err = dao.CreateEdgeTopic(&model.EdgeTopic{Topic: topic, EdgeID: 3, CreateTime: 15}); err != nil {
+			t.Error(err)
+		}
+		retEdgeTopics, err = dao.GetEdgeTopics(topic)
+		if err != nil || len(retEdgeTopics) != 1 || retEdgeTopics[0].Topic != topic {
+			t.Errorf("unmatched edge topics of %s, err: %v", topic, err)
+		}
+	}
+}
+
+func TestUpdateEdgeMeta(t *testing.T) {
+	config := &config.Configuration{}
+	dao, err := NewDao(config)
+	if err != nil {
+		t.Error(err)
+	}
+	defer dao.Close()
+	err = dao.CreateEdge(&model.Edge{EdgeID: 1, Meta: "v1", Addr: "192.168.1.101", CreateTime: 11})
+	if err != nil {
+		t.Error(err)
+	}
+	err = dao.UpdateEdgeMeta(1, "v2")
+	if err != nil {
+		t.Error(err)
+	}
+	edge, err := dao.GetEdge(1)
+	if err != nil {
+		t.Error(err)
+	}
+	if edge.Meta != "v2" || edge.Addr != "192.168.1.101" {
+		t.Error("unmatched edge", edge)
+	}
+	// the meta index follows
+	retEdges, err := dao.ListEdges(&query.EdgeQuery{Meta: "v2"})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(retEdges) != 1 {
+		t.Error("unmatched length of edges", len(retEdges))
+	}
+	// offline edge
+	err = dao.UpdateEdgeMeta(2, "v2")
+	if err != apis.ErrRecordNotFound {
+		t.Error("unexpected err", err)
+	}
+}
+
+func TestEdgeLabels(t *testing.T) {
+	config := &config.Configuration{}
+	dao, err := NewDao(config)
+	if err != nil {
+		t.Error(err)
+	}
+	defer dao.Close()
+	edges := []*model.Edge{
+		{EdgeID: 101, Meta: "test1", Addr: "192.168.1.101", CreateTime: 11},
+		{EdgeID: 102, Meta: "test2", Addr: "192.168.1.102", CreateTime: 12},
+		{EdgeID: 103, Meta: "test3", Addr: "192.168.1.103", CreateTime: 13},
+	}
+	for _, edge := range edges {
+		err = dao.CreateEdge(edge)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	edgeLabels := []*model.EdgeLabel{
+		{EdgeID: 101, Key: "site", Value: "fra1", CreateTime: 11},
+		{EdgeID: 101, Key: "model", Value: "x200", CreateTime: 11},
+		{EdgeID: 102, Key: "site", Value: "ams1", CreateTime: 12},
+		{EdgeID: 102, Key: "model", Value: "x100", CreateTime: 12},
+		// attached again
+		{EdgeID: 102, Key: "model", Value: "x200", CreateTime: 13},
+	}
+	for _, edgeLabel := range edgeLabels {
+		err = dao.CreateEdgeLabel(edgeLabel)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	retEdgeLabels, err := dao.GetEdgeLabels(102)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(retEdgeLabels) != 2 || retEdgeLabels[0].Key != "model" || retEdgeLabels[0].Value != "x200" {
+		t.Error("unmatched edge labels")
+	}
+	retEdgesLabels, err := dao.GetEdgesLabels([]uint64{101, 102, 103})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(retEdgesLabels) != 2 || len(retEdgesLabels[101]) != 2 || retEdgesLabels[101][0].Key != "model" ||
+		len(retEdgesLabels[102]) != 2 || retEdgesLabels[102][1].Value != "ams1" || len(retEdgesLabels[103]) != 0 {
+		t.Error("unmatched edges labels")
+	}
+
+	selects := map[string][]uint64{
+		"site=fra1":                    {101},
+		"model=x200":                   {101, 102},
+		"site in (fra1,ams1),model":    {101, 102},
+		"site!=fra1":                   {102, 103},
+		"!site":                        {103},
+		"model=x200,site notin (ams1)": {101},
+	}
+	for str, edgeIDs := range selects {
+		selector, err := label.ParseSelector(str)
+		if err != nil {
+			t.Error(err)
+		}
+		retEdges, err := dao.ListEdges(&query.EdgeQuery{
+			Selector: selector,
+			Query:    query.Query{Order: "edge_id"},
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		retEdgeIDs := []uint64{}
+		for _, edge := range retEdges {
+			if edge.EdgeID >= 101 && edge.EdgeID <= 103 {
+				retEdgeIDs = append(retEdgeIDs, edge.EdgeID)
+			}
+		}
+		sort.Slice(retEdgeIDs, func(i, j int) bool { return retEdgeIDs[i] < retEdgeIDs[j] })
+		if !reflect.DeepEqual(retEdgeIDs, edgeIDs) {
+			t.Error("unmatched edges of selector", str, retEdgeIDs)
+		}
+	}
+
+	// edge offline
+	err = dao.DeleteEdgeLabels(101)
+	if err != nil {
+		t.Error(err)
+	}
+	retEdgeLabels, err = dao.GetEdgeLabels(101)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(retEdgeLabels) != 0 {
+		t.Error("edge labels not deleted")
+	}
+}
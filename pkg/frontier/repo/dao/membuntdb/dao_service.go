@@ -669,6 +669,17 @@ func (dao *dao) CountServiceTopics(query *query.ServiceTopicQuery) (int64, error
 	return 0, ErrUnimplemented
 }
 
+func (dao *dao) DeleteServiceTopic(serviceID uint64, topic string) error {
+	err := dao.db.Update(func(tx *buntdb.Tx) error {
+		_, err := tx.Delete(getServiceTopicKey(serviceID, topic))
+		if err == buntdb.ErrNotFound {
+			return nil
+		}
+		return err
+	})
+	return err
+}
+
 func (dao *dao) DeleteServiceTopics(serviceID uint64) error {
 	err := dao.db.Update(func(tx *buntdb.Tx) error {
 		var delkeys []string
@@ -42,10 +42,18 @@ func (dao *dao) CreateEdge(edge *model.Edge) error {
 	return nil
 }
 
+func (dao *dao) CreateEdgeLabel(label *model.EdgeLabel) error {
+	return nil
+}
+
 func (dao *dao) CreateEdgeRPC(rpc *model.EdgeRPC) error {
 	return nil
 }
 
+func (dao *dao) CreateEdgeTopic(topic *model.EdgeTopic) error {
+	return nil
+}
+
 func (dao *dao) CreateService(service *model.Service) error {
 	return nil
 }
@@ -62,10 +70,22 @@ func (dao *dao) DeleteEdge(delete *query.EdgeDelete) error {
 	return nil
 }
 
+func (dao *dao) DeleteEdgeLabels(edgeID uint64) error {
+	return nil
+}
+
 func (dao *dao) DeleteEdgeRPCs(edgeID uint64) error {
 	return nil
 }
 
+func (dao *dao) DeleteEdgeTopic(edgeID uint64, topic string) error {
+	return nil
+}
+
+func (dao *dao) DeleteEdgeTopics(edgeID uint64) error {
+	return nil
+}
+
 func (dao *dao) DeleteService(delete *query.ServiceDelete) error {
 	return nil
 }
@@ -74,6 +94,10 @@ func (dao *dao) DeleteServiceRPCs(serviceID uint64) error {
 	return nil
 }
 
+func (dao *dao) DeleteServiceTopic(serviceID uint64, topic string) error {
+	return nil
+}
+
 func (dao *dao) DeleteServiceTopics(serviceID uint64) error {
 	return nil
 }
@@ -82,6 +106,22 @@ func (dao *dao) GetEdge(edgeID uint64) (*model.Edge, error) {
 	return nil, errors.New("not found")
 }
 
+func (dao *dao) GetEdgeLabels(edgeID uint64) ([]*model.EdgeLabel, error) {
+	return nil, nil
+}
+
+func (dao *dao) GetEdgesLabels(edgeIDs []uint64) (map[uint64][]*model.EdgeLabel, error) {
+	return nil, nil
+}
+
+func (dao *dao) GetEdgeRPC(edgeID uint64, rpc string) (*model.EdgeRPC, error) {
+	return nil, errors.New("not found")
+}
+
+func (dao *dao) GetEdgeTopics(topic string) ([]*model.EdgeTopic, error) {
+	return nil, errors.New("not found")
+}
+
 func (dao *dao) GetService(serviceID uint64) (*model.Service, error) {
 	return nil, errors.New("not found")
 }
@@ -117,3 +157,7 @@ func (dao *dao) ListServiceTopics(query *query.ServiceTopicQuery) ([]string, err
 func (dao *dao) ListServices(query *query.ServiceQuery) ([]*model.Service, error) {
 	return nil, errors.New("not found")
 }
+
+func (dao *dao) UpdateEdgeMeta(edgeID uint64, meta string) error {
+	return nil
+}
@@ -37,7 +37,7 @@ func NewDao(config *config.Configuration) (*dao, error) {
 	sqlDB.Exec("PRAGMA locking_mode = EXCLUSIVE;")
 	sqlDB.Exec("PRAGMA mmap_size = 268435456;") // 256MB memory map size
 	sqlDB.SetMaxOpenConns(0)
-	if err = dbEdge.AutoMigrate(&model.Edge{}, &model.EdgeRPC{}); err != nil {
+	if err = dbEdge.AutoMigrate(&model.Edge{}, &model.EdgeRPC{}, &model.EdgeTopic{}, &model.EdgeLabel{}); err != nil {
 		return nil, err
 	}
 
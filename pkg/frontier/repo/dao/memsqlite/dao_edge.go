@@ -1,6 +1,7 @@
 package memsqlite
 
 import (
+	"github.com/singchia/frontier/pkg/frontier/label"
 	"github.com/singchia/frontier/pkg/frontier/repo/model"
 	"github.com/singchia/frontier/pkg/frontier/repo/query"
 	"gorm.io/gorm"
@@ -79,6 +80,21 @@ func (dao *dao) CreateEdge(edge *model.Edge) error {
 	return tx.Create(edge).Error
 }
 
+func (dao *dao) UpdateEdgeMeta(edgeID uint64, meta string) error {
+	tx := dao.dbEdge.Model(&model.Edge{})
+	if dao.config.Dao.Debug {
+		tx = tx.Debug()
+	}
+	tx = tx.Where("edge_id = ?", edgeID).Update("meta", meta)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func buildEdgeQuery(tx *gorm.DB, query *query.EdgeQuery) *gorm.DB {
 	// join
 	if query.RPC != "" {
@@ -91,6 +107,10 @@ func buildEdgeQuery(tx *gorm.DB, query *query.EdgeQuery) *gorm.DB {
 	if query.Addr != "" {
 		tx = tx.Where("addr LIKE ?", query.Addr+"%")
 	}
+	// labels, on the index of key and value
+	for _, req := range query.Selector {
+		tx = buildEdgeLabelRequirement(tx, req)
+	}
 	// time range
 	if query.StartTime != 0 && query.EndTime != 0 && query.EndTime > query.StartTime {
 		tx = tx.Where("create_time >= ? AND create_time < ?", query.StartTime, query.EndTime)
@@ -98,6 +118,21 @@ func buildEdgeQuery(tx *gorm.DB, query *query.EdgeQuery) *gorm.DB {
 	return tx
 }
 
+func buildEdgeLabelRequirement(tx *gorm.DB, req label.Requirement) *gorm.DB {
+	switch req.Operator {
+	case label.OpIn:
+		return tx.Where("edges.edge_id IN (SELECT edge_id FROM edge_labels WHERE `key` = ? AND value IN ?)", req.Key, req.Values)
+	case label.OpNotIn:
+		// edges without the key are matched too
+		return tx.Where("edges.edge_id NOT IN (SELECT edge_id FROM edge_labels WHERE `key` = ? AND value IN ?)", req.Key, req.Values)
+	case label.OpExists:
+		return tx.Where("edges.edge_id IN (SELECT edge_id FROM edge_labels WHERE `key` = ?)", req.Key)
+	case label.OpDoesNotExist:
+		return tx.Where("edges.edge_id NOT IN (SELECT edge_id FROM edge_labels WHERE `key` = ?)", req.Key)
+	}
+	return tx
+}
+
 func buildEdgeDelete(tx *gorm.DB, delete *query.EdgeDelete) *gorm.DB {
 	if delete.EdgeID != 0 {
 		tx = tx.Where("edge_id = ?", delete.EdgeID)
@@ -168,6 +203,22 @@ func (dao *dao) CreateEdgeRPC(rpc *model.EdgeRPC) error {
 	return tx.Create(rpc).Error
 }
 
+func (dao *dao) GetEdgeRPC(edgeID uint64, rpc string) (*model.EdgeRPC, error) {
+	tx := dao.dbEdge.Model(&model.EdgeRPC{})
+	if dao.config.Dao.Debug {
+		tx = tx.Debug()
+	}
+	tx = tx.Where("edge_id = ? AND rpc = ?", edgeID, rpc).Limit(1)
+
+	// we not use First to avoid the warn log when record not found
+	var erpc model.EdgeRPC
+	tx = tx.Find(&erpc)
+	if tx.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &erpc, tx.Error
+}
+
 func buildEdgeRPCQuery(tx *gorm.DB, query *query.EdgeRPCQuery) *gorm.DB {
 	// join
 	if query.Meta != "" {
@@ -183,3 +234,94 @@ func buildEdgeRPCQuery(tx *gorm.DB, query *query.EdgeRPCQuery) *gorm.DB {
 	}
 	return tx
 }
+
+func (dao *dao) GetEdgeTopics(topic string) ([]*model.EdgeTopic, error) {
+	tx := dao.dbEdge.Model(&model.EdgeTopic{})
+	if dao.config.Dao.Debug {
+		tx = tx.Debug()
+	}
+	tx = tx.Where("topic = ?", topic)
+
+	mtopics := []*model.EdgeTopic{}
+	tx = tx.Find(&mtopics)
+	if tx.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return mtopics, tx.Error
+}
+
+func (dao *dao) DeleteEdgeTopic(edgeID uint64, topic string) error {
+	tx := dao.dbEdge.Where("edge_id = ? AND topic = ?", edgeID, topic)
+	if dao.config.Dao.Debug {
+		tx = tx.Debug()
+	}
+	return tx.Delete(&model.EdgeTopic{}).Error
+}
+
+func (dao *dao) DeleteEdgeTopics(edgeID uint64) error {
+	tx := dao.dbEdge.Where("edge_id = ?", edgeID)
+	if dao.config.Dao.Debug {
+		tx = tx.Debug()
+	}
+	return tx.Delete(&model.EdgeTopic{}).Error
+}
+
+func (dao *dao) CreateEdgeTopic(topic *model.EdgeTopic) error {
+	tx := dao.dbEdge
+	if dao.config.Dao.Debug {
+		tx = tx.Debug()
+	}
+	// subscribing again is a no-op
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(topic).Error
+}
+
+func (dao *dao) GetEdgeLabels(edgeID uint64) ([]*model.EdgeLabel, error) {
+	tx := dao.dbEdge.Model(&model.EdgeLabel{})
+	if dao.config.Dao.Debug {
+		tx = tx.Debug()
+	}
+	labels := []*model.EdgeLabel{}
+	tx = tx.Where("edge_id = ?", edgeID).Order("`key`").Find(&labels)
+	return labels, tx.Error
+}
+
+// GetEdgesLabels returns the labels of edges by edgeID in one query, edges without labels are left out
+func (dao *dao) GetEdgesLabels(edgeIDs []uint64) (map[uint64][]*model.EdgeLabel, error) {
+	labels := map[uint64][]*model.EdgeLabel{}
+	if len(edgeIDs) == 0 {
+		return labels, nil
+	}
+	tx := dao.dbEdge.Model(&model.EdgeLabel{})
+	if dao.config.Dao.Debug {
+		tx = tx.Debug()
+	}
+	mlabels := []*model.EdgeLabel{}
+	tx = tx.Where("edge_id IN ?", edgeIDs).Order("edge_id, `key`").Find(&mlabels)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	for _, label := range mlabels {
+		labels[label.EdgeID] = append(labels[label.EdgeID], label)
+	}
+	return labels, nil
+}
+
+func (dao *dao) DeleteEdgeLabels(edgeID uint64) error {
+	tx := dao.dbEdge.Where("edge_id = ?", edgeID)
+	if dao.config.Dao.Debug {
+		tx = tx.Debug()
+	}
+	return tx.Delete(&model.EdgeLabel{}).Error
+}
+
+func (dao *dao) CreateEdgeLabel(label *model.EdgeLabel) error {
+	tx := dao.dbEdge
+	if dao.config.Dao.Debug {
+		tx = tx.Debug()
+	}
+	// the value is replaced if the key is attached again
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "edge_id"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "create_time"}),
+	}).Create(label).Error
+}
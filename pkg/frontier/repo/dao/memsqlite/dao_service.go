@@ -333,6 +333,14 @@ func (dao *dao) CountServiceTopics(query *query.ServiceTopicQuery) (int64, error
 	return count, tx.Error
 }
 
+func (dao *dao) DeleteServiceTopic(serviceID uint64, topic string) error {
+	tx := dao.dbService.Where("service_id = ? AND topic = ?", serviceID, topic)
+	if dao.config.Dao.Debug {
+		tx = tx.Debug()
+	}
+	return tx.Delete(&model.ServiceTopic{}).Error
+}
+
 func (dao *dao) DeleteServiceTopics(serviceID uint64) error {
 	tx := dao.dbService.Where("service_id = ?", serviceID)
 	if dao.config.Dao.Debug {
@@ -346,7 +354,8 @@ func (dao *dao) CreateServiceTopic(topic *model.ServiceTopic) error {
 	if dao.config.Dao.Debug {
 		tx = tx.Debug()
 	}
-	return tx.Create(topic).Error
+	// subscribing again is a no-op
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(topic).Error
 }
 
 func buildServiceTopicQuery(tx *gorm.DB, query *query.ServiceTopicQuery) *gorm.DB {
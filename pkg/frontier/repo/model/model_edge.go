@@ -1,8 +1,10 @@
 package model
 
 const (
-	TnEdges    = "edges"
-	TnEdgeRPCs = "edge_rpcs"
+	TnEdges      = "edges"
+	TnEdgeRPCs   = "edge_rpcs"
+	TnEdgeTopics = "edge_topics"
+	TnEdgeLabels = "edge_labels"
 )
 
 type Edge struct {
@@ -25,3 +27,26 @@ type EdgeRPC struct {
 func (EdgeRPC) TableName() string {
 	return TnEdgeRPCs
 }
+
+// topics subscribed by the edge, services publish to them by topic
+type EdgeTopic struct {
+	Topic      string `gorm:"column:topic;index:idx_edgetopic_topic;uniqueIndex:idx_edgetopic_edge_id_topic,priority:2" json:"topic"`
+	EdgeID     uint64 `gorm:"column:edge_id;uniqueIndex:idx_edgetopic_edge_id_topic,priority:1" json:"edge_id"`
+	CreateTime int64  `gorm:"column:create_time;index:idx_edgetopic_create_time" json:"create_time"`
+}
+
+func (EdgeTopic) TableName() string {
+	return TnEdgeTopics
+}
+
+// labels attached by the edge or derived from its certificate, edges are selected by them
+type EdgeLabel struct {
+	EdgeID     uint64 `gorm:"column:edge_id;uniqueIndex:idx_edgelabel_edge_id_key,priority:1" json:"edge_id"`
+	Key        string `gorm:"column:key;uniqueIndex:idx_edgelabel_edge_id_key,priority:2;index:idx_edgelabel_key_value,priority:1" json:"key"`
+	Value      string `gorm:"column:value;index:idx_edgelabel_key_value,priority:2" json:"value"`
+	CreateTime int64  `gorm:"column:create_time" json:"create_time"`
+}
+
+func (EdgeLabel) TableName() string {
+	return TnEdgeLabels
+}
@@ -28,8 +28,8 @@ func (ServiceRPC) TableName() string {
 }
 
 type ServiceTopic struct {
-	Topic      string `gorm:"column:topic;index:idx_servicetopic_topic;type:text collate nocase" json:"topic"`
-	ServiceID  uint64 `gorm:"service_id;index:idx_servicetopic_service_id" json:"service_id"`
+	Topic      string `gorm:"column:topic;index:idx_servicetopic_topic;uniqueIndex:idx_servicetopic_service_id_topic,priority:2;type:text collate nocase" json:"topic"`
+	ServiceID  uint64 `gorm:"service_id;index:idx_servicetopic_service_id;uniqueIndex:idx_servicetopic_service_id_topic,priority:1" json:"service_id"`
 	CreateTime int64  `gorm:"column:create_time;index:idx_servicetopic_create_time" json:"create_time"`
 }
 
@@ -1,5 +1,7 @@
 package query
 
+import "github.com/singchia/frontier/pkg/frontier/label"
+
 type Query struct {
 	// Pagination
 	Page, PageSize int
@@ -16,6 +18,8 @@ type EdgeQuery struct {
 	Meta string
 	Addr string
 	RPC  string
+	// labels of edges, empty matches all
+	Selector label.Selector
 }
 
 type EdgeRPCQuery struct {
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+func (s *Server) RequestDrain(timeout time.Duration) {
+	if !s.draining.CompareAndSwap(false, true) {
+		klog.V(1).Infof("drain requested, already draining")
+		return
+	}
+	klog.V(0).Infof("drain requested, timeout: %s", timeout)
+	s.drainCh <- timeout
+}
+
+// DrainRequested delivers the timeout once the control plane asks to drain
+func (s *Server) DrainRequested() <-chan time.Duration {
+	return s.drainCh
+}
+
+// Drain stops accepting edges, and returns after the edges are closed once their rpcs and streams are done,
+// or ctx is done
+func (s *Server) Drain(ctx context.Context) error {
+	s.draining.Store(true)
+	addr := ""
+	if s.informer != nil && s.conf.Daemon.Drain.Redirect {
+		picked, err := s.informer.PickFrontier(ctx)
+		if err != nil {
+			klog.Warningf("drain pick frontier err: %s, edges reconnect without redirect", err)
+		} else {
+			addr = picked
+		}
+	}
+	return s.edgebound.Drain(ctx, addr)
+}
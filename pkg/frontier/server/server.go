@@ -1,6 +1,9 @@
 package server
 
 import (
+	"sync/atomic"
+	"time"
+
 	"github.com/singchia/frontier/pkg/frontier/apis"
 	"github.com/singchia/frontier/pkg/frontier/config"
 	"github.com/singchia/frontier/pkg/frontier/controlplane"
@@ -13,14 +16,26 @@ import (
 )
 
 type Server struct {
+	conf         *config.Configuration
 	tmr          timer.Timer
+	informer     *frontlas.Informer
+	exchange     apis.Exchange
 	servicebound apis.Servicebound
 	edgebound    apis.Edgebound
 	controlplane *controlplane.ControlPlane
+
+	// drain requested by the control plane
+	draining atomic.Bool
+	drainCh  chan time.Duration
 }
 
 func NewServer(conf *config.Configuration, repo apis.Repo, mqm apis.MQM) (*Server, error) {
 	tmr := timer.NewTimer()
+	s := &Server{
+		conf:    conf,
+		tmr:     tmr,
+		drainCh: make(chan time.Duration, 1),
+	}
 
 	// informer
 	var (
@@ -37,7 +52,11 @@ func NewServer(conf *config.Configuration, repo apis.Repo, mqm apis.MQM) (*Serve
 	}
 
 	// exchange
-	exchange := exchange.NewExchange(conf, mqm)
+	exchange, err := exchange.NewExchange(conf, mqm)
+	if err != nil {
+		klog.Errorf("new exchange err: %s", err)
+		return nil, err
+	}
 
 	// servicebound
 	servicebound, err := servicebound.NewServicebound(conf, repo, inf, exchange, mqm, tmr)
@@ -55,20 +74,19 @@ func NewServer(conf *config.Configuration, repo apis.Repo, mqm apis.MQM) (*Serve
 
 	// controlplane
 	if conf.ControlPlane.Enable {
-		cp, err = controlplane.NewControlPlane(conf, repo, servicebound, edgebound)
+		cp, err = controlplane.NewControlPlane(conf, repo, servicebound, edgebound, exchange, s)
 		if err != nil {
 			klog.Errorf("new controlplane err: %s", err)
 			return nil, err
 		}
 	}
 
-	return &Server{
-		tmr:          tmr,
-		servicebound: servicebound,
-		edgebound:    edgebound,
-		controlplane: cp,
-	}, nil
-
+	s.informer = inf
+	s.exchange = exchange
+	s.servicebound = servicebound
+	s.edgebound = edgebound
+	s.controlplane = cp
+	return s, nil
 }
 
 func (s *Server) Serve() {
@@ -85,5 +103,6 @@ func (s *Server) Close() {
 	if s.controlplane != nil {
 		s.controlplane.Close()
 	}
+	s.exchange.Close()
 	s.tmr.Close()
 }
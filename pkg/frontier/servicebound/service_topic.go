@@ -0,0 +1,37 @@
+package servicebound
+
+import (
+	"github.com/singchia/geminio"
+	"k8s.io/klog/v2"
+)
+
+// SubscribeTopics adds the topics to receive besides those declared in meta
+func (sm *serviceManager) SubscribeTopics(end geminio.End, topics []string) error {
+	serviceID := end.ClientID()
+	if err := sm.remoteReceiveClaim(serviceID, topics); err != nil {
+		return err
+	}
+	if sm.mqm != nil {
+		sm.mqm.AddMQByEnd(topics, end)
+	}
+	klog.V(2).Infof("service subscribe, serviceID: %d, topics: %v", serviceID, topics)
+	return nil
+}
+
+func (sm *serviceManager) UnsubscribeTopics(end geminio.End, topics []string) error {
+	serviceID := end.ClientID()
+	if len(topics) == 0 {
+		return nil
+	}
+	for _, topic := range topics {
+		if err := sm.repo.DeleteServiceTopic(serviceID, topic); err != nil {
+			klog.Errorf("service unsubscribe, delete service topic err: %s, serviceID: %d, topic: %s", err, serviceID, topic)
+			return err
+		}
+	}
+	if sm.mqm != nil {
+		sm.mqm.DelMQByEnd(end, topics...)
+	}
+	klog.V(2).Infof("service unsubscribe, serviceID: %d, topics: %v", serviceID, topics)
+	return nil
+}
@@ -9,4 +9,5 @@ var (
 	ErrWrongLengthInRedis       = errors.New("wrong length in redis")
 	ErrFrontierAlreadySet       = errors.New("frontier already set")
 	ErrIllegalRequest           = errors.New("illegal request")
+	ErrNoFrontierAvailable      = errors.New("no frontier available")
 )
@@ -126,6 +126,11 @@ func (fm *FrontierManager) register(end geminio.End) error {
 	if err != nil {
 		klog.Errorf("register frontier_stats err: %s", err)
 	}
+	// frontier_pick
+	err = end.Register(context.TODO(), gapis.RPCFrontierPick, fm.FrontierPick)
+	if err != nil {
+		klog.Errorf("register frontier_pick err: %s", err)
+	}
 	return nil
 }
 
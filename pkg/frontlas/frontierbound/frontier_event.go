@@ -210,3 +210,45 @@ func (fm *FrontierManager) FrontierStats(ctx context.Context, req geminio.Reques
 		return
 	}
 }
+
+// pick the frontier with the fewest edges except the draining one
+func (fm *FrontierManager) FrontierPick(ctx context.Context, req geminio.Request, rsp geminio.Response) {
+	pick := &gapis.FrontierPick{}
+	err := json.Unmarshal(req.Data(), pick)
+	if err != nil {
+		klog.Errorf("frontier manager frontier pick, json unmarshal err: %s", err)
+		rsp.SetError(err)
+		return
+	}
+	frontiers, err := fm.repo.GetAllFrontiers()
+	if err != nil {
+		klog.Errorf("frontier manager frontier pick, get all frontiers err: %s", err)
+		rsp.SetError(err)
+		return
+	}
+	var picked *repo.Frontier
+	for _, frontier := range frontiers {
+		if frontier.FrontierID == pick.FrontierID || frontier.AdvertisedEdgeboundAddr == "" {
+			continue
+		}
+		if picked == nil || frontier.EdgeCount < picked.EdgeCount {
+			picked = frontier
+		}
+	}
+	if picked == nil {
+		klog.V(1).Infof("frontier manager frontier pick, no frontier available, frontierID: %s", pick.FrontierID)
+		rsp.SetError(apis.ErrNoFrontierAvailable)
+		return
+	}
+	data, err := json.Marshal(&gapis.FrontierInstance{
+		FrontierID:                 picked.FrontierID,
+		AdvertisedServiceboundAddr: picked.AdvertisedServiceboundAddr,
+		AdvertisedEdgeboundAddr:    picked.AdvertisedEdgeboundAddr,
+	})
+	if err != nil {
+		klog.Errorf("frontier manager frontier pick, json marshal err: %s", err)
+		rsp.SetError(err)
+		return
+	}
+	rsp.SetData(data)
+}
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/singchia/frontier/pkg/config"
+	"k8s.io/klog/v2"
+)
+
+// the interval to check cert files, changed files are reloaded
+var CertsCheckInterval = 10 * time.Second
+
+var (
+	loadersMtx sync.Mutex
+	// key: cert, key and ca files
+	loaders   = map[string]*certLoader{}
+	watchOnce sync.Once
+)
+
+// certLoader holds certs and the ca pool loaded from files, they are
+// swapped on reload so new handshakes use them and established conns are kept
+type certLoader struct {
+	conf config.TLS
+
+	mtx    sync.RWMutex
+	certs  []tls.Certificate
+	caPool *x509.CertPool
+	// key: file; value: modification time at last load
+	mods map[string]time.Time
+}
+
+// getCertLoader returns the shared loader of the same files, loads them at the first time
+func getCertLoader(conf *config.TLS) (*certLoader, error) {
+	files := []string{}
+	for _, certFile := range conf.Certs {
+		files = append(files, certFile.Cert, certFile.Key)
+	}
+	key := strings.Join(files, ",") + ";" + strings.Join(conf.CACerts, ",")
+
+	loadersMtx.Lock()
+	defer loadersMtx.Unlock()
+	if loader, ok := loaders[key]; ok {
+		return loader, nil
+	}
+	loader := &certLoader{conf: *conf}
+	if err := loader.load(); err != nil {
+		return nil, err
+	}
+	loaders[key] = loader
+	watchOnce.Do(func() { go watchCerts() })
+	return loader, nil
+}
+
+func (loader *certLoader) load() error {
+	mods := map[string]time.Time{}
+	stat := func(file string) {
+		if info, err := os.Stat(file); err == nil {
+			mods[file] = info.ModTime()
+		}
+	}
+	// load all certs
+	certs := []tls.Certificate{}
+	failed := false
+	for _, certFile := range loader.conf.Certs {
+		stat(certFile.Cert)
+		stat(certFile.Key)
+		cert, err := tls.LoadX509KeyPair(certFile.Cert, certFile.Key)
+		if err != nil {
+			klog.Errorf("tls load x509 cert err: %s, cert: %s, key: %s", err, certFile.Cert, certFile.Key)
+			failed = true
+			continue
+		}
+		certs = append(certs, cert)
+	}
+	// load all ca certs to pool
+	var caPool *x509.CertPool
+	if len(loader.conf.CACerts) != 0 {
+		caPool = x509.NewCertPool()
+		for _, caFile := range loader.conf.CACerts {
+			stat(caFile)
+			ca, err := os.ReadFile(caFile)
+			if err != nil {
+				klog.Errorf("read ca cert err: %s, file: %s", err, caFile)
+				return err
+			}
+			if !caPool.AppendCertsFromPEM(ca) {
+				klog.Warningf("append ca cert to ca pool failed, file: %s", caFile)
+				continue
+			}
+		}
+	}
+
+	loader.mtx.Lock()
+	defer loader.mtx.Unlock()
+	if loader.mods != nil && failed {
+		// keep the old certs while reloading if any new one is broken, e.g. the key is not written yet,
+		// it's retried when the files change again
+		loader.mods = mods
+		return errors.New("cert load failed")
+	}
+	loader.certs = certs
+	loader.caPool = caPool
+	loader.mods = mods
+	return nil
+}
+
+// changed tells if any file is modified since the last load
+func (loader *certLoader) changed() bool {
+	loader.mtx.RLock()
+	defer loader.mtx.RUnlock()
+
+	for file, mod := range loader.mods {
+		info, err := os.Stat(file)
+		if err == nil && !info.ModTime().Equal(mod) {
+			return true
+		}
+	}
+	return false
+}
+
+func (loader *certLoader) get() ([]tls.Certificate, *x509.CertPool) {
+	loader.mtx.RLock()
+	defer loader.mtx.RUnlock()
+	return loader.certs, loader.caPool
+}
+
+// serverConfig returns the config for listening, certs and the ca pool are taken per handshake
+func (loader *certLoader) serverConfig(base *tls.Config) *tls.Config {
+	conf := base.Clone()
+	conf.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		certs, caPool := loader.get()
+		handshake := base.Clone()
+		handshake.Certificates = certs
+		if base.ClientAuth == tls.RequireAndVerifyClientCert {
+			handshake.ClientCAs = caPool
+		}
+		return handshake, nil
+	}
+	return conf
+}
+
+// ReloadCerts reloads all certs and ca pools in use
+func ReloadCerts() {
+	loadersMtx.Lock()
+	all := make([]*certLoader, 0, len(loaders))
+	for _, loader := range loaders {
+		all = append(all, loader)
+	}
+	loadersMtx.Unlock()
+
+	for _, loader := range all {
+		if err := loader.load(); err != nil {
+			klog.Errorf("reload certs err: %s", err)
+		}
+	}
+	klog.V(1).Infof("certs reloaded, count: %d", len(all))
+}
+
+// CertsNotifier reloads all certs on signals, e.g. sigaction.Signal.Add(syscall.SIGHUP, utils.CertsNotifier{})
+type CertsNotifier struct{}
+
+func (CertsNotifier) Notify(os.Signal) {
+	ReloadCerts()
+}
+
+func watchCerts() {
+	ticker := time.NewTicker(CertsCheckInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		loadersMtx.Lock()
+		changed := []*certLoader{}
+		for _, loader := range loaders {
+			if loader.changed() {
+				changed = append(changed, loader)
+			}
+		}
+		loadersMtx.Unlock()
+
+		for _, loader := range changed {
+			if err := loader.load(); err != nil {
+				klog.Errorf("reload changed certs err: %s", err)
+				continue
+			}
+			klog.V(1).Infof("changed certs reloaded")
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/singchia/frontier/pkg/config"
+)
+
+func writeCert(t *testing.T, dir string, serial int64, ca *x509.Certificate, caKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, config.CertKey) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "frontier"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if ca == nil {
+		tmpl.IsCA, tmpl.BasicConstraintsValid, tmpl.KeyUsage = true, true, x509.KeyUsageCertSign
+		ca, caKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	certKey := config.CertKey{Cert: filepath.Join(dir, "cert.pem"), Key: filepath.Join(dir, "key.pem")}
+	if ca == tmpl {
+		certKey = config.CertKey{Cert: filepath.Join(dir, "ca.pem"), Key: filepath.Join(dir, "ca.key")}
+	}
+	os.WriteFile(certKey.Cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	os.WriteFile(certKey.Key, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	cert, _ := x509.ParseCertificate(der)
+	return cert, key, certKey
+}
+
+func TestReloadCerts(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey, caFiles := writeCert(t, dir, 1, nil, nil)
+	_, _, certKey := writeCert(t, dir, 2, ca, caKey)
+
+	listen := &config.Listen{
+		Network: "tcp",
+		Addr:    "127.0.0.1:1205",
+		TLS: config.TLS{
+			Enable:  true,
+			MTLS:    true,
+			CACerts: []string{caFiles.Cert},
+			Certs:   []config.CertKey{certKey},
+		},
+	}
+	ln, err := Listen(listen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				conn.(*tls.Conn).Handshake()
+				conn.Close()
+			}()
+		}
+	}()
+
+	dial := &config.Dial{
+		Network: "tcp",
+		Addrs:   []string{listen.Addr},
+		TLS: config.TLS{
+			Enable:  true,
+			MTLS:    true,
+			CACerts: []string{caFiles.Cert},
+			Certs:   []config.CertKey{certKey},
+		},
+	}
+	serial := func() int64 {
+		conn, err := Dial(dial, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		return conn.(*tls.Conn).ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+	}
+	if got := serial(); got != 2 {
+		t.Fatalf("serial got %d, want 2", got)
+	}
+
+	// rotate the ca and the cert, established listeners and dialers take them after reload
+	ca, caKey, _ = writeCert(t, dir, 3, nil, nil)
+	writeCert(t, dir, 4, ca, caKey)
+	ReloadCerts()
+	if got := serial(); got != 4 {
+		t.Fatalf("serial after reload got %d, want 4", got)
+	}
+}
@@ -2,10 +2,8 @@ package utils
 
 import (
 	"crypto/tls"
-	"crypto/x509"
 	"errors"
 	"net"
-	"os"
 
 	"github.com/singchia/frontier/pkg/config"
 	"k8s.io/klog/v2"
@@ -32,55 +30,28 @@ func Dial(dial *config.Dial, index int) (net.Conn, error) {
 		}
 		return conn, err
 	} else {
-		// load all certs to dial
-		certs := []tls.Certificate{}
-		for _, certFile := range dial.TLS.Certs {
-			cert, err := tls.LoadX509KeyPair(certFile.Cert, certFile.Key)
-			if err != nil {
-				klog.Errorf("dial, tls load x509 cert err: %s, cert: %s, key: %s", err, certFile.Cert, certFile.Key)
-				continue
-			}
-			certs = append(certs, cert)
+		// certs and the ca pool are reloaded without restarting
+		loader, err := getCertLoader(&dial.TLS)
+		if err != nil {
+			klog.Errorf("dial load certs err: %s", err)
+			return nil, err
 		}
+		certs, caPool := loader.get()
 
-		if !dial.TLS.MTLS {
-			// tls
-			conn, err := tls.Dial(network, addr, &tls.Config{
-				Certificates: certs,
-				// it's user's call to verify the server certs or not.
-				InsecureSkipVerify: dial.TLS.InsecureSkipVerify,
-			})
-			if err != nil {
-				klog.Errorf("tls dial err: %s, network: %s, addr: %s", err, network, addr)
-				return nil, err
-			}
-			return conn, nil
-		} else {
-			// mtls, dial with our certs
-			// load all ca certs to pool
-			caPool := x509.NewCertPool()
-			for _, caFile := range dial.TLS.CACerts {
-				ca, err := os.ReadFile(caFile)
-				if err != nil {
-					klog.Errorf("dial read ca cert err: %s, file: %s", err, caFile)
-					return nil, err
-				}
-				if !caPool.AppendCertsFromPEM(ca) {
-					klog.Warningf("dial append ca cert to ca pool err: %s, file: %s", err, caFile)
-					continue
-				}
-			}
-			conn, err := tls.Dial(network, addr, &tls.Config{
-				Certificates: certs,
-				// we should not skip the verify.
-				InsecureSkipVerify: dial.TLS.InsecureSkipVerify,
-				RootCAs:            caPool,
-			})
-			if err != nil {
-				klog.Errorf("dial tls dial err: %s, network: %s, addr: %s", err, network, addr)
-				return nil, err
-			}
-			return conn, nil
+		conf := &tls.Config{
+			Certificates: certs,
+			// it's user's call to verify the server certs or not.
+			InsecureSkipVerify: dial.TLS.InsecureSkipVerify,
+		}
+		if dial.TLS.MTLS {
+			// mtls, dial with our certs and verify the server by the ca pool
+			conf.RootCAs = caPool
+		}
+		conn, err := tls.Dial(network, addr, conf)
+		if err != nil {
+			klog.Errorf("dial tls dial err: %s, network: %s, addr: %s", err, network, addr)
+			return nil, err
 		}
+		return conn, nil
 	}
 }
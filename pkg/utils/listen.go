@@ -2,10 +2,8 @@ package utils
 
 import (
 	"crypto/tls"
-	"crypto/x509"
 	"fmt"
 	"net"
-	"os"
 
 	"github.com/pion/transport/v2/udp"
 	"github.com/singchia/frontier/pkg/config"
@@ -38,53 +36,24 @@ func listenTCP(listen *config.Listen) (net.Listener, error) {
 		}
 
 	} else {
-		// load all certs to listen
-		certs := []tls.Certificate{}
-		for _, certFile := range listen.TLS.Certs {
-			cert, err := tls.LoadX509KeyPair(certFile.Cert, certFile.Key)
-			if err != nil {
-				klog.Errorf("listen tls load x509 cert err: %s, cert: %s, key: %s", err, certFile.Cert, certFile.Key)
-				continue
-			}
-			certs = append(certs, cert)
+		// certs and the ca pool are reloaded without restarting
+		loader, err := getCertLoader(&listen.TLS)
+		if err != nil {
+			klog.Errorf("listen load certs err: %s", err)
+			return nil, err
 		}
 
-		if !listen.TLS.MTLS {
-			// tls
-			if ln, err = tls.Listen(network, addr, &tls.Config{
-				MinVersion:   tls.VersionTLS12,
-				CipherSuites: security.CiperSuites,
-				Certificates: certs,
-			}); err != nil {
-				klog.Errorf("listen tls listen err: %s, network: %s, addr: %s", err, network, addr)
-				return nil, err
-			}
-
-		} else {
+		base := &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			CipherSuites: security.CiperSuites,
+		}
+		if listen.TLS.MTLS {
 			// mtls, require for edge cert
-			// load all ca certs to pool
-			caPool := x509.NewCertPool()
-			for _, caFile := range listen.TLS.CACerts {
-				ca, err := os.ReadFile(caFile)
-				if err != nil {
-					klog.Errorf("listen read ca cert err: %s, file: %s", err, caFile)
-					return nil, err
-				}
-				if !caPool.AppendCertsFromPEM(ca) {
-					klog.Warningf("listen append ca cert to ca pool err: %s, file: %s", err, caFile)
-					continue
-				}
-			}
-			if ln, err = tls.Listen(network, addr, &tls.Config{
-				MinVersion:   tls.VersionTLS12,
-				CipherSuites: security.CiperSuites,
-				ClientCAs:    caPool,
-				ClientAuth:   tls.RequireAndVerifyClientCert,
-				Certificates: certs,
-			}); err != nil {
-				klog.Errorf("listen tls listen err: %s, network: %s, addr: %s", err, network, addr)
-				return nil, err
-			}
+			base.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+		if ln, err = tls.Listen(network, addr, loader.serverConfig(base)); err != nil {
+			klog.Errorf("listen tls listen err: %s, network: %s, addr: %s", err, network, addr)
+			return nil, err
 		}
 	}
 	return ln, nil
@@ -77,7 +77,8 @@ func startFrontier(b *testing.B) *benchFrontier {
 	mqm, err := mq.NewMQM(conf)
 	require.NoError(b, err)
 	tmr := timer.NewTimer()
-	ex := exchange.NewExchange(conf, mqm)
+	ex, err := exchange.NewExchange(conf, mqm)
+	require.NoError(b, err)
 
 	sb, err := servicebound.NewServicebound(conf, r, nil, ex, mqm, tmr)
 	require.NoError(b, err)
@@ -242,6 +243,144 @@ func BenchmarkEdgePublishMessage(b *testing.B) {
 	b.ReportMetric(qps, "qps")
 }
 
+// BENCH-MSG-002: Service → Frontier → Edge 消息吞吐 (QPS)
+func BenchmarkServicePublishMessage(b *testing.B) {
+	f := startFrontier(b)
+
+	e := f.dialEdge(b)
+	go func() {
+		for {
+			msg, err := e.Receive(context.TODO())
+			if err != nil {
+				return
+			}
+			msg.Done()
+		}
+	}()
+	edgeID := e.EdgeID()
+	svc := f.dialService(b, "bench-msg-publisher")
+	time.Sleep(300 * time.Millisecond)
+
+	payload := []byte("message")
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			msg := svc.NewMessage(payload)
+			if err := svc.Publish(context.TODO(), edgeID, msg); err != nil {
+				b.Error(err)
+			}
+		}
+	})
+	b.StopTimer()
+
+	qps := float64(b.N) / b.Elapsed().Seconds()
+	b.ReportMetric(qps, "qps")
+}
+
+// BENCH-MSG-003: Service → Frontier → Edge 消息吞吐, 同一连接上存在慢 Edge (QPS)
+func BenchmarkServicePublishMessageWithSlowEdge(b *testing.B) {
+	f := startFrontier(b)
+
+	receive := func(e edge.Edge, delay time.Duration) {
+		for {
+			msg, err := e.Receive(context.TODO())
+			if err != nil {
+				return
+			}
+			time.Sleep(delay)
+			msg.Done()
+		}
+	}
+	slow := f.dialEdge(b)
+	go receive(slow, 20*time.Millisecond)
+	const numFast = 4
+	fastIDs := make([]uint64, numFast)
+	for i := 0; i < numFast; i++ {
+		e := f.dialEdge(b)
+		go receive(e, 0)
+		fastIDs[i] = e.EdgeID()
+	}
+	svc := f.dialService(b, "bench-msg-publisher")
+	time.Sleep(300 * time.Millisecond)
+
+	// keep the slow edge busy on the same service connection
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	for i := 0; i < 4; i++ {
+		go func() {
+			for ctx.Err() == nil {
+				svc.Publish(ctx, slow.EdgeID(), svc.NewMessage([]byte("slow")))
+			}
+		}()
+	}
+
+	payload := []byte("message")
+	var index uint64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			edgeID := fastIDs[atomic.AddUint64(&index, 1)%numFast]
+			msg := svc.NewMessage(payload)
+			if err := svc.Publish(context.TODO(), edgeID, msg); err != nil {
+				b.Error(err)
+			}
+		}
+	})
+	b.StopTimer()
+
+	qps := float64(b.N) / b.Elapsed().Seconds()
+	b.ReportMetric(qps, "qps")
+}
+
+// BENCH-MSG-004: Edge → Frontier → Service 消息吞吐, 另一 Edge 上存在慢 Topic (QPS)
+func BenchmarkEdgePublishMessageWithSlowTopic(b *testing.B) {
+	f := startFrontier(b)
+
+	receive := func(svc service.Service, delay time.Duration) {
+		for {
+			msg, err := svc.Receive(context.TODO())
+			if err != nil {
+				return
+			}
+			time.Sleep(delay)
+			msg.Done()
+		}
+	}
+	slow := f.dialService(b, "bench-slow-svc", service.OptionServiceReceiveTopics([]string{"slow-topic"}))
+	go receive(slow, 20*time.Millisecond)
+	fast := f.dialService(b, "bench-fast-svc", service.OptionServiceReceiveTopics([]string{"fast-topic"}))
+	go receive(fast, 0)
+	e := f.dialEdge(b)
+	other := f.dialEdge(b)
+	time.Sleep(300 * time.Millisecond)
+
+	// keep the slow topic busy by another edge, messages from one edge are produced in order
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	for i := 0; i < 4; i++ {
+		go func() {
+			for ctx.Err() == nil {
+				other.Publish(ctx, "slow-topic", other.NewMessage([]byte("slow")))
+			}
+		}()
+	}
+
+	payload := []byte("message")
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			msg := e.NewMessage(payload)
+			if err := e.Publish(context.TODO(), "fast-topic", msg); err != nil {
+				b.Error(err)
+			}
+		}
+	})
+	b.StopTimer()
+
+	qps := float64(b.N) / b.Elapsed().Seconds()
+	b.ReportMetric(qps, "qps")
+}
+
 // BENCH-STRM-001: Edge → Frontier → Service 流建立吞吐 (QPS)
 // Note: This benchmark may occasionally panic in geminio when run repeatedly
 // due to a race condition in stream cleanup. Run with -count=1 if issues occur.
@@ -301,7 +440,8 @@ func BenchmarkEdgeConnectDisconnect(b *testing.B) {
 	mqm, err := mq.NewMQM(conf)
 	require.NoError(b, err)
 	tmr := timer.NewTimer()
-	ex := exchange.NewExchange(conf, mqm)
+	ex, err := exchange.NewExchange(conf, mqm)
+	require.NoError(b, err)
 
 	sb, err := servicebound.NewServicebound(conf, r, nil, ex, mqm, tmr)
 	require.NoError(b, err)
@@ -59,7 +59,10 @@ func TestMain(m *testing.M) {
 		panic("new mqm: " + err.Error())
 	}
 	tmr := timer.NewTimer()
-	ex := exchange.NewExchange(conf, mqm)
+	ex, err := exchange.NewExchange(conf, mqm)
+	if err != nil {
+		panic("new exchange: " + err.Error())
+	}
 
 	sb, err := servicebound.NewServicebound(conf, r, nil, ex, mqm, tmr)
 	if err != nil {
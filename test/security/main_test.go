@@ -63,7 +63,10 @@ func TestMain(m *testing.M) {
 		panic(err)
 	}
 	tmr := timer.NewTimer()
-	ex := exchange.NewExchange(conf, mqm)
+	ex, err := exchange.NewExchange(conf, mqm)
+	if err != nil {
+		panic(err)
+	}
 
 	sb, err := servicebound.NewServicebound(conf, r, nil, ex, mqm, tmr)
 	if err != nil {